@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"io/ioutil"
@@ -21,6 +22,7 @@ import (
 	"github.com/Axway/agent-sdk/pkg/util/errors"
 	hc "github.com/Axway/agent-sdk/pkg/util/healthcheck"
 	"github.com/Axway/agent-sdk/pkg/util/log"
+	"github.com/Axway/agent-sdk/pkg/util/metrics"
 )
 
 // AgentStatus - status for Agent resource
@@ -48,14 +50,22 @@ var agentTypesMap = map[config.AgentType]string{
 	config.TraceabilityAgent: "traceabilityagents",
 }
 
+// dataplaneTypesMap - the dataplane resources by the dataplane type of the agent resource
+var dataplaneTypesMap = map[string]string{
+	"edge": "edgedataplanes",
+	"aws":  "awsdataplanes",
+}
+
 type agentData struct {
-	agentResource     *apiV1.ResourceInstance
-	prevAgentResource *apiV1.ResourceInstance
+	agentResource         *apiV1.ResourceInstance
+	prevAgentResource     *apiV1.ResourceInstance
+	dataplaneResource     *apiV1.ResourceInstance
+	prevDataplaneResource *apiV1.ResourceInstance
 
 	apicClient     apic.Client
 	cfg            *config.CentralConfiguration
 	agentCfg       interface{}
-	tokenRequester auth.PlatformTokenGetter
+	tokenRequester auth.TokenCache
 	loggerName     string
 	logLevel       string
 	logFormat      string
@@ -67,7 +77,9 @@ type agentData struct {
 	deleteServiceValidator     DeleteServiceValidator
 	configChangeHandler        ConfigChangeHandler
 	agentResourceChangeHandler ConfigChangeHandler
+	tokenChangeHandlers        []auth.TokenChangeHandler
 	isInitialized              bool
+	dryRun                     bool
 }
 
 var agent = agentData{}
@@ -99,10 +111,14 @@ func Initialize(centralCfg config.CentralConfig) error {
 	// Init apic client
 	if agent.apicClient == nil {
 		agent.apicClient = apic.New(centralCfg, agent.tokenRequester)
+		agent.apicClient.SetContext(GetContext())
 	} else {
 		agent.apicClient.SetTokenGetter(agent.tokenRequester)
 		agent.apicClient.OnConfigChange(centralCfg)
 	}
+	if _, ok := agent.apicClient.(*dryRunClient); agent.dryRun && !ok {
+		agent.apicClient = newDryRunClient(agent.apicClient)
+	}
 
 	if !agent.isInitialized {
 		if getAgentResourceType() != "" {
@@ -112,6 +128,9 @@ func Initialize(centralCfg config.CentralConfig) error {
 			return errors.Wrap(apic.ErrCentralConfig, "Agent name cannot be set. Config is used only for agents with API server resource definition")
 		}
 
+		hc.RegisterHealthcheck("Central Auth Token", centralTokenEndpoint, centralTokenHealthcheck)
+		hc.SetConfigSources(func() interface{} { return GetConfigSources() })
+		metrics.RegisterCollector("agent", collectAgentMetrics)
 		setupSignalProcessor()
 		// only do the periodic healthcheck stuff if NOT in unit tests and running binary agents
 		if isNotTest() && !isRunningInDockerContainer() {
@@ -120,6 +139,9 @@ func Initialize(centralCfg config.CentralConfig) error {
 
 		StartPeriodicStatusUpdate()
 		startAPIServiceCache()
+	} else if agent.agentResource != nil {
+		// the config was parsed again, the agent resource values take precedence over it
+		mergeResourceWithConfig()
 	}
 	agent.isInitialized = true
 	return nil
@@ -159,8 +181,15 @@ func OnAgentResourceChange(agentResourceChangeHandler ConfigChangeHandler) {
 }
 
 func startAPIServiceCache() {
+	cacheJob := &discoveryCache{}
+	if agent.cfg.CanWatchResources() {
+		// the watcher keeps the cache updated, the job only polls while the watch is disconnected
+		cacheJob.watcher = newResourceWatcher(newAPIServerWatchClient(agent.cfg), agent.cfg.PollInterval)
+		cacheJob.watcher.start()
+	}
+
 	// register the update cache job
-	id, err := jobs.RegisterIntervalJob(&discoveryCache{}, agent.cfg.PollInterval)
+	id, err := jobs.RegisterIntervalJob(cacheJob, agent.cfg.PollInterval, jobs.WithJobName("discovery-cache"))
 	if err != nil {
 		log.Errorf("could not start the API cache update job: %v", err.Error())
 		return
@@ -189,7 +218,13 @@ func isRunningInDockerContainer() bool {
 // initializeTokenRequester - Create a new auth token requestor
 func initializeTokenRequester(centralCfg config.CentralConfig) error {
 	var err error
-	agent.tokenRequester = auth.NewPlatformTokenGetterWithCentralConfig(centralCfg)
+	if agent.tokenRequester != nil {
+		// stop the background renewal of the token of the previous config
+		agent.tokenRequester.Close()
+	}
+	tokenGetter := auth.NewPlatformTokenGetterWithCentralConfig(centralCfg)
+	agent.tokenRequester = auth.NewTokenCache(tokenGetter, centralCfg.GetAuthConfig().GetRefreshPercentage())
+	agent.tokenRequester.OnTokenChange(notifyTokenChange)
 	if isNotTest() {
 		_, err = agent.tokenRequester.GetToken()
 	}
@@ -227,6 +262,11 @@ func GetAgentResource() *apiV1.ResourceInstance {
 	return agent.agentResource
 }
 
+// GetDataplaneResource - returns the dataplane resource the agent resource refers to
+func GetDataplaneResource() *apiV1.ResourceInstance {
+	return agent.dataplaneResource
+}
+
 // UpdateStatus - Updates the agent state
 func UpdateStatus(status, description string) {
 	updateAgentStatus(status, description)
@@ -260,17 +300,21 @@ func refreshResources() (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	agent.dataplaneResource = getDataplaneResource(agent.agentResource)
 
 	isChanged := true
 	if agent.prevAgentResource != nil {
 		agentResHash, _ := util.ComputeHash(agent.agentResource)
 		prevAgentResHash, _ := util.ComputeHash(agent.prevAgentResource)
+		dataplaneResHash, _ := util.ComputeHash(agent.dataplaneResource)
+		prevDataplaneResHash, _ := util.ComputeHash(agent.prevDataplaneResource)
 
-		if prevAgentResHash == agentResHash {
+		if prevAgentResHash == agentResHash && prevDataplaneResHash == dataplaneResHash {
 			isChanged = false
 		}
 	}
 	agent.prevAgentResource = agent.agentResource
+	agent.prevDataplaneResource = agent.dataplaneResource
 
 	return isChanged, nil
 }
@@ -285,7 +329,9 @@ func setupSignalProcessor() {
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 	go func() {
 		<-sigs
-		cleanUp()
+		ctx, cancel := context.WithTimeout(context.Background(), getShutdownTimeout())
+		defer cancel()
+		Shutdown(ctx)
 		os.Exit(0)
 	}()
 }
@@ -316,12 +362,41 @@ func getAgentResource() (*apiV1.ResourceInstance, error) {
 	return &agent, nil
 }
 
+// getDataplaneResource - returns the dataplane resource the agent resource refers to in spec.dataplane, nil when the
+// agent resource does not refer to a dataplane or the dataplane could not be read, the agent then runs with its own config
+func getDataplaneResource(agentResource *apiV1.ResourceInstance) *apiV1.ResourceInstance {
+	dataplaneName, _ := agentResource.Spec["dataplane"].(string)
+	dataplaneType, _ := agentResource.Spec["dataplaneType"].(string)
+	dataplaneResourceType, ok := dataplaneTypesMap[strings.ToLower(dataplaneType)]
+	if dataplaneName == "" || !ok {
+		return nil
+	}
+	dataplaneResourceURL := agent.cfg.GetEnvironmentURL() + "/" + dataplaneResourceType + "/" + dataplaneName
+
+	response, err := agent.apicClient.ExecuteAPI(coreapi.GET, dataplaneResourceURL, nil, nil)
+	if err != nil {
+		log.Warnf("Could not read the dataplane %s, continuing without the dataplane config: %s", dataplaneName, err.Error())
+		return nil
+	}
+
+	dataplane := apiV1.ResourceInstance{}
+	if err := json.Unmarshal(response, &dataplane); err != nil {
+		log.Warnf("Could not parse the dataplane %s, continuing without the dataplane config: %s", dataplaneName, err.Error())
+		return nil
+	}
+	return &dataplane
+}
+
 // updateAgentStatus - Updates the agent status in agent resource
 func updateAgentStatus(status, message string) error {
 	// IMP - To be removed once the model is in production
 	if agent.cfg == nil || agent.cfg.GetAgentName() == "" {
 		return nil
 	}
+	// the agent resource is not changed in dry run mode
+	if agent.dryRun {
+		return nil
+	}
 
 	if agent.agentResource != nil {
 		agentResourceType := getAgentResourceType()
@@ -361,38 +436,11 @@ func createAgentStatusSubResource(agentResourceType, status, message string) int
 	}
 }
 
+// mergeResourceWithConfig - sets the config properties driven by the agent resource
 func mergeResourceWithConfig() {
 	// IMP - To be removed once the model is in production
 	if agent.cfg.GetAgentName() == "" {
 		return
 	}
-
-	switch getAgentResourceType() {
-	case v1alpha1.DiscoveryAgentResource:
-		mergeDiscoveryAgentWithConfig(agent.cfg)
-	case v1alpha1.TraceabilityAgentResource:
-		mergeTraceabilityAgentWithConfig(agent.cfg)
-	default:
-		panic(ErrUnsupportedAgentType)
-	}
-}
-
-func applyResConfigToCentralConfig(cfg *config.CentralConfiguration, resCfgAdditionalTags, resCfgTeamName, resCfgLogLevel string) {
-	if cfg.TagsToPublish == "" && resCfgAdditionalTags != "" {
-		cfg.TagsToPublish = resCfgAdditionalTags
-	}
-
-	logLevel := agent.logLevel
-	if strings.ToUpper(agent.logLevel) == "INFO" && strings.ToUpper(resCfgLogLevel) != "INFO" {
-		logLevel = resCfgLogLevel
-	}
-	agent.logLevel = logLevel
-	if logLevel != "" {
-		log.GlobalLoggerConfig.Level(logLevel).Apply()
-	}
-
-	// If config team is blank, check resource team name.  If resource team name is not blank, use resource team name
-	if cfg.TeamName == "" && resCfgTeamName != "" {
-		cfg.TeamName = resCfgTeamName
-	}
+	agentResourceConfig.apply(GetAgentResource(), GetDataplaneResource())
 }
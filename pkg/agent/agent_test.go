@@ -209,6 +209,75 @@ func TestAgentConfigOverride(t *testing.T) {
 
 }
 
+func TestAgentDataplaneResource(t *testing.T) {
+	const daName = "discovery"
+	discoveryAgentRes := createDiscoveryAgentRes("111", daName, "Edge", "")
+	discoveryAgentRes.Spec["dataplane"] = "gateway"
+	dataplaneRes := &v1.ResourceInstance{
+		ResourceMeta: v1.ResourceMeta{Name: "gateway"},
+		Spec: map[string]interface{}{
+			"apiGatewayManager": map[string]interface{}{"host": "gatewayhost", "port": 8090},
+		},
+	}
+	s := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if strings.Contains(req.RequestURI, "/auth") {
+			resp.Write([]byte("{\"access_token\":\"somevalue\",\"expires_in\": 12235677}"))
+		}
+		if strings.Contains(req.RequestURI, "/v7/discoveryagents/"+daName) {
+			buf, _ := json.Marshal(discoveryAgentRes)
+			resp.Write(buf)
+		}
+		if strings.Contains(req.RequestURI, "/v7/edgedataplanes/gateway") {
+			buf, _ := json.Marshal(dataplaneRes)
+			resp.Write(buf)
+		}
+	}))
+	defer s.Close()
+	defer func() {
+		configValues.values = make(map[string]interface{})
+		agent.dataplaneResource = nil
+	}()
+
+	cfg := createCentralCfg(s.URL, "v7")
+	cfg.AgentName = daName
+	resetResources()
+	err := Initialize(cfg)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, GetDataplaneResource())
+	assert.Equal(t, "gateway", GetDataplaneResource().Name)
+	assert.Equal(t, "gatewayhost", GetConfigValue("dataplane.apiGatewayManager.host"))
+	assert.Equal(t, int32(8090), GetConfigValue("dataplane.apiGatewayManager.port"))
+}
+
+func TestAgentDataplaneResourceNotFound(t *testing.T) {
+	const daName = "discovery"
+	discoveryAgentRes := createDiscoveryAgentRes("111", daName, "Edge", "")
+	discoveryAgentRes.Spec["dataplane"] = "gateway"
+	s := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if strings.Contains(req.RequestURI, "/auth") {
+			resp.Write([]byte("{\"access_token\":\"somevalue\",\"expires_in\": 12235677}"))
+		}
+		if strings.Contains(req.RequestURI, "/v7/discoveryagents/"+daName) {
+			buf, _ := json.Marshal(discoveryAgentRes)
+			resp.Write(buf)
+		}
+		if strings.Contains(req.RequestURI, "/v7/edgedataplanes/gateway") {
+			resp.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	cfg := createCentralCfg(s.URL, "v7")
+	cfg.AgentName = daName
+	resetResources()
+	// the agent runs without the dataplane config when the dataplane can not be read
+	err := Initialize(cfg)
+	assert.Nil(t, err)
+	assert.NotNil(t, GetAgentResource())
+	assert.Nil(t, GetDataplaneResource())
+}
+
 func assertAgentResource(t *testing.T, res, expectedRes *v1.ResourceInstance) {
 	assert.Equal(t, expectedRes.Group, res.Group)
 	assert.Equal(t, expectedRes.Kind, res.Kind)
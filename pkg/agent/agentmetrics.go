@@ -0,0 +1,50 @@
+package agent
+
+import (
+	"github.com/Axway/agent-sdk/pkg/util/metrics"
+)
+
+// collectAgentMetrics - returns the metrics of the Central auth token and the items in the API cache
+func collectAgentMetrics() []metrics.Family {
+	stats := GetCentralAuthTokenStats()
+	valid := 0.0
+	if stats.Valid {
+		valid = 1
+	}
+	families := []metrics.Family{
+		{
+			Name:    "agent_token_refreshes",
+			Help:    "The renewals of the Central auth token",
+			Type:    metrics.CounterType,
+			Samples: []metrics.Sample{{Value: float64(stats.Refreshes)}},
+		},
+		{
+			Name:    "agent_token_refresh_failures",
+			Help:    "The failed renewals of the Central auth token",
+			Type:    metrics.CounterType,
+			Samples: []metrics.Sample{{Value: float64(stats.Failures)}},
+		},
+		{
+			Name:    "agent_token_valid",
+			Help:    "1 when the agent has a valid Central auth token, 0 otherwise",
+			Type:    metrics.GaugeType,
+			Samples: []metrics.Sample{{Value: valid}},
+		},
+		{
+			Name:    "agent_token_expires_in_seconds",
+			Help:    "The time until the Central auth token expires",
+			Type:    metrics.GaugeType,
+			Samples: []metrics.Sample{{Value: stats.ExpiresIn.Seconds()}},
+		},
+	}
+
+	if agent.apiMap != nil {
+		families = append(families, metrics.Family{
+			Name:    "agent_cache_items",
+			Help:    "The items in the cache",
+			Type:    metrics.GaugeType,
+			Samples: []metrics.Sample{{Labels: metrics.Labels{"cache": "apis"}, Value: float64(len(agent.apiMap.GetKeys()))}},
+		})
+	}
+	return families
+}
@@ -0,0 +1,45 @@
+package agent
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/apic/auth"
+	hc "github.com/Axway/agent-sdk/pkg/util/healthcheck"
+)
+
+const centralTokenEndpoint = "token"
+
+// OnCentralAuthTokenChange - Registers handler for the renewal of the Central auth token, the long running
+// connections, e.g. of the beats outputs, can use the new token without reconnecting
+func OnCentralAuthTokenChange(handler auth.TokenChangeHandler) {
+	agent.tokenChangeHandlers = append(agent.tokenChangeHandlers, handler)
+}
+
+// GetCentralAuthTokenStats - Returns the age of the Central auth token and the count of renewals and failures
+func GetCentralAuthTokenStats() auth.TokenStats {
+	if agent.tokenRequester == nil {
+		return auth.TokenStats{}
+	}
+	return agent.tokenRequester.GetStats()
+}
+
+func notifyTokenChange(token string) {
+	for _, handler := range agent.tokenChangeHandlers {
+		handler(token)
+	}
+}
+
+// centralTokenHealthcheck - fails when the agent has no valid token, the details are the token metrics
+func centralTokenHealthcheck(name string) *hc.Status {
+	stats := GetCentralAuthTokenStats()
+	details := fmt.Sprintf("age: %s, expires in: %s, refreshes: %d, failures: %d",
+		stats.Age.Round(time.Second), stats.ExpiresIn.Round(time.Second), stats.Refreshes, stats.Failures)
+	if !stats.Valid {
+		if stats.LastError != "" {
+			details += ", last error: " + stats.LastError
+		}
+		return &hc.Status{Result: hc.FAIL, Details: details}
+	}
+	return &hc.Status{Result: hc.OK, Details: details}
+}
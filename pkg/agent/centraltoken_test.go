@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/Axway/agent-sdk/pkg/apic/auth"
+	"github.com/Axway/agent-sdk/pkg/config"
+	hc "github.com/Axway/agent-sdk/pkg/util/healthcheck"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCentralAuthToken(t *testing.T) {
+	agent.tokenRequester = nil
+	status := centralTokenHealthcheck("token")
+	assert.Equal(t, hc.FAIL, status.Result)
+
+	s := auth.NewMockTokenServer("DOSA_1111", "secret")
+	defer s.Close()
+	cfg := createCentralCfg(s.URL, "env")
+	authCfg := cfg.Auth.(*config.AuthConfiguration)
+	authCfg.Type = config.AuthTypeClientSecret
+	authCfg.ClientSecret = "secret"
+
+	tokens := make([]string, 0)
+	OnCentralAuthTokenChange(func(token string) {
+		tokens = append(tokens, token)
+	})
+	defer func() { agent.tokenChangeHandlers = nil }()
+
+	assert.Nil(t, initializeTokenRequester(cfg))
+	token, err := GetCentralAuthToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token)
+	assert.Equal(t, []string{"token-1"}, tokens)
+
+	status = centralTokenHealthcheck("token")
+	assert.Equal(t, hc.OK, status.Result)
+	assert.Equal(t, 1, GetCentralAuthTokenStats().Refreshes)
+
+	// the token metrics
+	values := make(map[string]float64)
+	for _, family := range collectAgentMetrics() {
+		values[family.Name] = family.Samples[0].Value
+	}
+	assert.Equal(t, float64(1), values["agent_token_refreshes"])
+	assert.Equal(t, float64(0), values["agent_token_refresh_failures"])
+	assert.Equal(t, float64(1), values["agent_token_valid"])
+	assert.True(t, values["agent_token_expires_in_seconds"] > 0)
+
+	// bad secret, the new config has no valid token
+	authCfg.ClientSecret = "bad"
+	assert.Nil(t, initializeTokenRequester(cfg))
+	_, err = GetCentralAuthToken()
+	assert.NotNil(t, err)
+	status = centralTokenHealthcheck("token")
+	assert.Equal(t, hc.FAIL, status.Result)
+	assert.Contains(t, status.Details, "failures: 1")
+	agent.tokenRequester.Close()
+	agent.tokenRequester = nil
+}
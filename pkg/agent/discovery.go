@@ -126,6 +126,31 @@ func PublishAPI(serviceBody apic.ServiceBody) error {
 	return nil
 }
 
+// PublishAPIs - Publishes the APIs in parallel, returns the result of each API in the order of the service bodies
+func PublishAPIs(serviceBodies []apic.ServiceBody, opts apic.PublishServicesOptions) []apic.PublishServiceResult {
+	if agent.apicClient == nil {
+		return []apic.PublishServiceResult{}
+	}
+
+	results := agent.apicClient.PublishServices(serviceBodies, opts)
+	published := false
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		apiSvc, e := result.APIService.AsInstance()
+		if e == nil {
+			addItemToAPICache(*apiSvc)
+			published = true
+		}
+	}
+	if published {
+		//update the local activity timestamp for the event to compare against
+		UpdateLocalActivityTime()
+	}
+	return results
+}
+
 // RegisterAPIValidator - Registers callback for validating the API on gateway
 func RegisterAPIValidator(apiValidator APIValidator) {
 	agent.apiValidator = apiValidator
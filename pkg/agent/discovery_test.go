@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -25,9 +26,17 @@ func (m *mockSvcClient) GetAPIRevisions(queryParams map[string]string, stage str
 }
 
 func (m *mockSvcClient) SetTokenGetter(tokenGetter auth.PlatformTokenGetter) {}
+func (m *mockSvcClient) SetContext(ctx context.Context)                      {}
+func (m *mockSvcClient) Shutdown(ctx context.Context) error                  { return nil }
 func (m *mockSvcClient) PublishService(serviceBody apic.ServiceBody) (*v1alpha1.APIService, error) {
 	return m.apiSvc, nil
 }
+func (m *mockSvcClient) PlanPublishService(serviceBody apic.ServiceBody) (*apic.PublishPlan, error) {
+	return nil, nil
+}
+func (m *mockSvcClient) PublishServices(serviceBodies []apic.ServiceBody, opts apic.PublishServicesOptions) []apic.PublishServiceResult {
+	return nil
+}
 func (m *mockSvcClient) RegisterSubscriptionWebhook() error { return nil }
 func (m *mockSvcClient) RegisterSubscriptionSchema(subscriptionSchema apic.SubscriptionSchema, update bool) error {
 	return nil
@@ -1,8 +1,6 @@
 package agent
 
 import (
-	"strings"
-
 	apiV1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
 	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
 	"github.com/Axway/agent-sdk/pkg/config"
@@ -25,11 +23,3 @@ func createDiscoveryAgentStatusResource(status, message string) *v1alpha1.Discov
 
 	return &agentRes
 }
-
-func mergeDiscoveryAgentWithConfig(cfg *config.CentralConfiguration) {
-	da := discoveryAgent(GetAgentResource())
-	resCfgAdditionalTags := strings.Join(da.Spec.Config.AdditionalTags, ",")
-	resCfgTeamName := da.Spec.Config.OwningTeam
-	resCfgLogLevel := da.Spec.Logging.Level
-	applyResConfigToCentralConfig(cfg, resCfgAdditionalTags, resCfgTeamName, resCfgLogLevel)
-}
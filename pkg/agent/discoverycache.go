@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"sync"
 
 	coreapi "github.com/Axway/agent-sdk/pkg/api"
 	"github.com/Axway/agent-sdk/pkg/apic"
@@ -23,8 +24,12 @@ const (
 	apiServerFields      = "name,title,attributes"
 )
 
+// syncLock - serializes the full synchronization with the watched resource events
+var syncLock sync.Mutex
+
 type discoveryCache struct {
 	jobs.Job
+	watcher *resourceWatcher
 }
 
 //Ready -
@@ -47,13 +52,25 @@ func (j *discoveryCache) Status() error {
 
 //Execute -
 func (j *discoveryCache) Execute() error {
+	if j.watcher != nil && j.watcher.isStreaming() {
+		log.Trace("API server resources are being watched, skipping API cache update job")
+		return nil
+	}
 	log.Trace("executing API cache update job")
+	synchronizeResources()
+	return nil
+}
+
+// synchronizeResources - fully synchronizes the API cache, consumer instances and agent resource with API server
+func synchronizeResources() {
+	syncLock.Lock()
+	defer syncLock.Unlock()
+
 	updateAPICache()
 	if agent.cfg.GetAgentType() == config.DiscoveryAgent {
 		validateConsumerInstances()
 	}
 	fetchConfig()
-	return nil
 }
 
 func updateAPICache() {
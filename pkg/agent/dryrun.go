@@ -0,0 +1,243 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/Axway/agent-sdk/pkg/apic"
+	apiV1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+// DryRunAction - the action a dry run operation would take on an API server resource
+type DryRunAction string
+
+// Dry run actions
+const (
+	DryRunActionCreate DryRunAction = "create"
+	DryRunActionUpdate DryRunAction = "update"
+	DryRunActionDelete DryRunAction = "delete"
+)
+
+// Dry run plan formats
+const (
+	DryRunFormatTable = "table"
+	DryRunFormatJSON  = "json"
+)
+
+var dryRunActionSymbols = map[DryRunAction]string{
+	DryRunActionCreate: "+",
+	DryRunActionUpdate: "~",
+	DryRunActionDelete: "-",
+}
+
+// DryRunOperation - an operation against the API server recorded, but not executed, in dry run mode
+type DryRunOperation struct {
+	Action DryRunAction `json:"action"`
+	Kind   string       `json:"kind,omitempty"`
+	Name   string       `json:"name,omitempty"`
+	URL    string       `json:"url,omitempty"`
+}
+
+// dryRunClient - wraps the apic client, reads are executed while the operations changing resources are recorded
+type dryRunClient struct {
+	apic.Client
+	lock       sync.Mutex
+	operations []DryRunOperation
+}
+
+func newDryRunClient(client apic.Client) *dryRunClient {
+	return &dryRunClient{
+		Client:     client,
+		operations: make([]DryRunOperation, 0),
+	}
+}
+
+func (c *dryRunClient) record(operation DryRunOperation) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.operations = append(c.operations, operation)
+}
+
+func (c *dryRunClient) getOperations() []DryRunOperation {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	operations := make([]DryRunOperation, len(c.operations))
+	copy(operations, c.operations)
+	return operations
+}
+
+// PublishService - records the steps of the publish plan, returns the service that would be published
+func (c *dryRunClient) PublishService(serviceBody apic.ServiceBody) (*v1alpha1.APIService, error) {
+	plan, err := c.Client.PlanPublishService(serviceBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiService *v1alpha1.APIService
+	for _, step := range plan.Steps() {
+		action := DryRunActionCreate
+		if step.Action == apic.PublishActionUpdate {
+			action = DryRunActionUpdate
+		}
+		c.record(DryRunOperation{Action: action, Kind: step.Kind, Name: step.Name})
+		if svc, ok := step.Resource.(*v1alpha1.APIService); ok {
+			apiService = svc
+		}
+	}
+	return apiService, nil
+}
+
+// PublishServices - records the steps of the publish plans, one service at a time
+func (c *dryRunClient) PublishServices(serviceBodies []apic.ServiceBody, opts apic.PublishServicesOptions) []apic.PublishServiceResult {
+	results := make([]apic.PublishServiceResult, len(serviceBodies))
+	progress := apic.PublishProgress{Total: len(serviceBodies)}
+	for i, serviceBody := range serviceBodies {
+		results[i] = apic.PublishServiceResult{ID: serviceBody.RestAPIID, APIName: serviceBody.APIName}
+		results[i].APIService, results[i].Err = c.PublishService(serviceBody)
+		if results[i].Err != nil {
+			progress.Failed++
+		} else {
+			progress.Published++
+		}
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+	}
+	return results
+}
+
+// DeleteServiceByAPIID - records the deletion of the service
+func (c *dryRunClient) DeleteServiceByAPIID(externalAPIID string) error {
+	name := externalAPIID
+	if agent.apiMap != nil {
+		if api, err := agent.apiMap.Get(externalAPIID); err == nil {
+			name = api.(apiV1.ResourceInstance).Name
+		}
+	}
+	c.record(DryRunOperation{Action: DryRunActionDelete, Kind: v1alpha1.APIServiceGVK().Kind, Name: name})
+	return nil
+}
+
+// DeleteConsumerInstance - records the deletion of the consumer instance
+func (c *dryRunClient) DeleteConsumerInstance(instanceName string) error {
+	c.record(DryRunOperation{Action: DryRunActionDelete, Kind: v1alpha1.ConsumerInstanceGVK().Kind, Name: instanceName})
+	return nil
+}
+
+// RegisterSubscriptionSchema - records the creation, or update, of the subscription definition
+func (c *dryRunClient) RegisterSubscriptionSchema(subscriptionSchema apic.SubscriptionSchema, update bool) error {
+	name := subscriptionSchema.GetSubscriptionName()
+	if agent.cfg != nil {
+		_, err := c.Client.ExecuteAPI(http.MethodGet, agent.cfg.GetAPIServerSubscriptionDefinitionURL()+"/"+name, nil, nil)
+		if err == nil {
+			if update {
+				return c.UpdateSubscriptionSchema(subscriptionSchema)
+			}
+			return nil
+		}
+	}
+	c.record(DryRunOperation{Action: DryRunActionCreate, Kind: v1alpha1.ConsumerSubscriptionDefinitionGVK().Kind, Name: name})
+	return nil
+}
+
+// UpdateSubscriptionSchema - records the update of the subscription definition
+func (c *dryRunClient) UpdateSubscriptionSchema(subscriptionSchema apic.SubscriptionSchema) error {
+	c.record(DryRunOperation{Action: DryRunActionUpdate, Kind: v1alpha1.ConsumerSubscriptionDefinitionGVK().Kind, Name: subscriptionSchema.GetSubscriptionName()})
+	return nil
+}
+
+// RegisterSubscriptionWebhook - records the creation of the subscription webhook
+func (c *dryRunClient) RegisterSubscriptionWebhook() error {
+	c.record(DryRunOperation{Action: DryRunActionCreate, Kind: v1alpha1.WebhookGVK().Kind})
+	return nil
+}
+
+// UpdateConsumerInstanceSubscriptionDefinition - records the update of the consumer instance
+func (c *dryRunClient) UpdateConsumerInstanceSubscriptionDefinition(externalAPIID, subscriptionDefinitionName string) error {
+	c.record(DryRunOperation{Action: DryRunActionUpdate, Kind: v1alpha1.ConsumerInstanceGVK().Kind, Name: externalAPIID})
+	return nil
+}
+
+// dryRunSubscriptionManager - the subscription manager of the dry run client, the processors are registered but the
+// subscriptions are not processed, as the processors change their state in Central
+type dryRunSubscriptionManager struct {
+	apic.SubscriptionManager
+}
+
+// Start - does not start processing the subscriptions
+func (m *dryRunSubscriptionManager) Start() {
+	log.Debug("The subscriptions are not processed in dry run mode")
+}
+
+// GetSubscriptionManager - returns the subscription manager not processing the subscriptions
+func (c *dryRunClient) GetSubscriptionManager() apic.SubscriptionManager {
+	return &dryRunSubscriptionManager{SubscriptionManager: c.Client.GetSubscriptionManager()}
+}
+
+// ExecuteAPI - executes reads, records any other request
+func (c *dryRunClient) ExecuteAPI(method, url string, queryParam map[string]string, buffer []byte) ([]byte, error) {
+	switch method {
+	case http.MethodGet:
+		return c.Client.ExecuteAPI(method, url, queryParam, buffer)
+	case http.MethodPost:
+		c.record(DryRunOperation{Action: DryRunActionCreate, URL: url})
+	case http.MethodDelete:
+		c.record(DryRunOperation{Action: DryRunActionDelete, URL: url})
+	default:
+		c.record(DryRunOperation{Action: DryRunActionUpdate, URL: url})
+	}
+	return buffer, nil
+}
+
+// SetDryRunMode - in dry run mode the operations changing API server resources are recorded, not executed.
+// Set before Initialize is called.
+func SetDryRunMode(dryRun bool) {
+	agent.dryRun = dryRun
+}
+
+// IsDryRunMode - returns true when the agent is in dry run mode
+func IsDryRunMode() bool {
+	return agent.dryRun
+}
+
+// GetDryRunPlan - returns the operations recorded in dry run mode
+func GetDryRunPlan() []DryRunOperation {
+	if client, ok := agent.apicClient.(*dryRunClient); ok {
+		return client.getOperations()
+	}
+	return []DryRunOperation{}
+}
+
+// WriteDryRunPlan - writes the operations recorded in dry run mode, as a table or json
+func WriteDryRunPlan(w io.Writer, format string) error {
+	operations := GetDryRunPlan()
+	switch format {
+	case DryRunFormatJSON:
+		data, err := json.MarshalIndent(operations, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case DryRunFormatTable, "":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "\tACTION\tKIND\tNAME")
+		for _, op := range operations {
+			name := op.Name
+			if name == "" {
+				name = op.URL
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", dryRunActionSymbols[op.Action], op.Action, op.Kind, name)
+		}
+		fmt.Fprintf(tw, "\n%d operation(s) planned\n", len(operations))
+		return tw.Flush()
+	default:
+		return ErrDryRunPlanFormat.FormatError(format)
+	}
+}
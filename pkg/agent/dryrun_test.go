@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Axway/agent-sdk/pkg/apic"
+	apiV1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	var lock sync.Mutex
+	changes := 0
+	s := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if strings.Contains(req.RequestURI, "/auth") {
+			token := "{\"access_token\":\"somevalue\",\"expires_in\": 12235677}"
+			resp.Write([]byte(token))
+			return
+		}
+		if req.Method != http.MethodGet {
+			lock.Lock()
+			changes++
+			lock.Unlock()
+		}
+		resp.WriteHeader(http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	cfg := createCentralCfg(s.URL, "test")
+	resetResources()
+	SetDryRunMode(true)
+	agent.apicClient = nil
+	defer func() {
+		SetDryRunMode(false)
+		agent.apicClient = nil
+	}()
+	err := Initialize(cfg)
+	assert.Nil(t, err)
+	assert.True(t, IsDryRunMode())
+
+	serviceBody, err := apic.NewServiceBodyBuilder().
+		SetID("petstore").
+		SetTitle("Petstore").
+		SetAPIName("petstore").
+		SetURL("https://petstore.swagger.io/v2").
+		SetAPISpec([]byte(`{"swagger":"2.0","host":"petstore.swagger.io","basePath":"/v2","schemes":["https"]}`)).
+		Build()
+	assert.Nil(t, err)
+
+	err = PublishAPI(serviceBody)
+	assert.Nil(t, err)
+	assert.Nil(t, agent.apicClient.DeleteConsumerInstance("ci"))
+	assert.Nil(t, agent.apicClient.DeleteServiceByAPIID("petstore"))
+	_, err = agent.apicClient.ExecuteAPI(http.MethodPut, s.URL+"/status", nil, []byte("{}"))
+	assert.Nil(t, err)
+
+	// the subscriptions are not processed and the agent status is not updated
+	_, ok := agent.apicClient.GetSubscriptionManager().(*dryRunSubscriptionManager)
+	assert.True(t, ok)
+	cfg.AgentName = "agent"
+	agent.agentResource = &apiV1.ResourceInstance{}
+	assert.Nil(t, updateAgentStatus(AgentRunning, ""))
+	agent.agentResource = nil
+	cfg.AgentName = ""
+
+	lock.Lock()
+	assert.Equal(t, 0, changes, "no resource should be changed in dry run mode")
+	lock.Unlock()
+
+	plan := GetDryRunPlan()
+	assert.Len(t, plan, 7)
+	assert.Equal(t, DryRunActionCreate, plan[0].Action)
+	assert.Equal(t, v1alpha1.APIServiceGVK().Kind, plan[0].Kind)
+	assert.Equal(t, DryRunOperation{Action: DryRunActionDelete, Kind: v1alpha1.ConsumerInstanceGVK().Kind, Name: "ci"}, plan[4])
+	assert.Equal(t, DryRunOperation{Action: DryRunActionDelete, Kind: v1alpha1.APIServiceGVK().Kind, Name: plan[0].Name}, plan[5])
+	assert.Equal(t, DryRunOperation{Action: DryRunActionUpdate, URL: s.URL + "/status"}, plan[6])
+
+	// the service that would be published is cached
+	_, err = agent.apiMap.Get("petstore")
+	assert.Nil(t, err)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WriteDryRunPlan(buf, DryRunFormatTable))
+	assert.Contains(t, buf.String(), "+  create")
+	assert.Contains(t, buf.String(), "-  delete")
+	assert.Contains(t, buf.String(), "7 operation(s) planned")
+
+	buf.Reset()
+	assert.Nil(t, WriteDryRunPlan(buf, DryRunFormatJSON))
+	written := []DryRunOperation{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &written))
+	assert.Equal(t, plan, written)
+
+	assert.NotNil(t, WriteDryRunPlan(buf, "yaml"))
+}
@@ -5,6 +5,8 @@ import "github.com/Axway/agent-sdk/pkg/util/errors"
 // Errors hit when validating AMPLIFY Central connectivity
 var (
 	ErrUnsupportedAgentType = errors.New(1000, "unsupported agent type")
+	ErrDryRunPlanFormat     = errors.Newf(1005, "unknown dry run plan format %s, expected json or table")
+	ErrResourceProperty     = errors.Newf(1006, "invalid agent resource property %s: %s")
 
 	ErrDeletingService     = errors.Newf(1161, "error deleting API Service for catalog item %s in AMPLIFY Central")
 	ErrDeletingCatalogItem = errors.Newf(1162, "error deleting catalog item %s in AMPLIFY Central")
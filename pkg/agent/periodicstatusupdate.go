@@ -55,7 +55,11 @@ func (su *periodicStatusUpdate) Execute() error {
 func StartPeriodicStatusUpdate() {
 	interval := agent.cfg.GetReportActivityFrequency()
 	statusUpdate = &periodicStatusUpdate{}
-	_, err := jobs.RegisterIntervalJob(statusUpdate, interval)
+	if agent.dryRun {
+		// the agent resource is not changed in dry run mode
+		return
+	}
+	_, err := jobs.RegisterIntervalJob(statusUpdate, interval, jobs.WithJobName("status-update"), jobs.WithFailurePolicy(jobs.FailurePolicyIsolate))
 
 	if err != nil {
 		log.Error(errors.Wrap(errors.ErrStartingPeriodicStatusUpdate, err.Error()))
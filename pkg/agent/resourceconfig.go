@@ -0,0 +1,442 @@
+package agent
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	apiV1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+// Sources of the config values, the agent and dataplane resources take precedence over the environment, which takes
+// precedence over the config file
+const (
+	ConfigSourceResource  = "resource"
+	ConfigSourceDataplane = "dataplane"
+	ConfigSourceEnv       = "env"
+	ConfigSourceFile      = "file"
+)
+
+// redactedConfigValue - replaces the value of the sensitive properties, of the same length whatever the secret
+const redactedConfigValue = "*****"
+
+// ResourceProperty - a config property that can be driven by the agent resource
+type ResourceProperty struct {
+	// Name - the name of the config property, e.g. central.team, its environment variable is CENTRAL_TEAM
+	Name string
+	// Resource - returns the value of the agent resource, false when the resource does not set the property
+	Resource func(agentResource *apiV1.ResourceInstance) (interface{}, bool)
+	// Dataplane - returns the value of the dataplane resource of the agent, used when the agent resource does not set
+	// the property
+	Dataplane func(dataplaneResource *apiV1.ResourceInstance) (interface{}, bool)
+	// Get - returns the current value of the config
+	Get func() interface{}
+	// Set - sets the value of the config
+	Set func(value interface{}) error
+	// Sensitive - the value is a secret, it is redacted by GetConfigSources
+	Sensitive bool
+}
+
+// PropertyChangeHandler - Callback for the change of a config property by the agent resource
+type PropertyChangeHandler func(name string, oldValue, newValue interface{})
+
+// ConfigValueSource - the value of a config property and where it came from
+type ConfigValueSource struct {
+	Property string      `json:"property"`
+	Source   string      `json:"source"`
+	Value    interface{} `json:"value"`
+}
+
+type resourcePropertyState struct {
+	property ResourceProperty
+	// the value of the environment or config file, restored when the resource no longer sets the property
+	localValue interface{}
+	value      interface{}
+	source     string
+	handlers   []PropertyChangeHandler
+}
+
+type propertyChange struct {
+	state              *resourcePropertyState
+	oldValue, newValue interface{}
+}
+
+type resourceConfig struct {
+	lock       sync.Mutex
+	names      []string
+	properties map[string]*resourcePropertyState
+}
+
+var agentResourceConfig = newResourceConfig()
+
+// configValues - the values of the config properties the SDK holds for the agent, e.g. agent.filter
+var configValues = struct {
+	lock   sync.RWMutex
+	values map[string]interface{}
+}{values: make(map[string]interface{})}
+
+// SetConfigValue - Sets the local value, from the environment or config file of the agent, of a config property the
+// SDK holds for the agent, e.g. agent.filter, the value of the agent or dataplane resource takes precedence over it
+func SetConfigValue(name string, value interface{}) {
+	configValues.lock.Lock()
+	defer configValues.lock.Unlock()
+	configValues.values[name] = value
+}
+
+// GetConfigValue - Returns the value of a config property the SDK holds for the agent, nil when it is not set
+func GetConfigValue(name string) interface{} {
+	configValues.lock.RLock()
+	defer configValues.lock.RUnlock()
+	return configValues.values[name]
+}
+
+func newResourceConfig() *resourceConfig {
+	c := &resourceConfig{properties: make(map[string]*resourcePropertyState)}
+	for _, property := range defaultResourceProperties() {
+		c.register(property)
+	}
+	return c
+}
+
+// RegisterResourceProperty - Registers an agent config property that the agent resource can set, the value of the
+// agent resource replaces the value of the environment and of the config file
+func RegisterResourceProperty(property ResourceProperty) error {
+	if property.Name == "" || (property.Resource == nil && property.Dataplane == nil) || property.Get == nil || property.Set == nil {
+		return ErrResourceProperty.FormatError(property.Name, "the name, resource or dataplane, get and set are required")
+	}
+	agentResourceConfig.register(property)
+	return nil
+}
+
+// OnAgentResourcePropertyChange - Registers handler for the change of the config property by the agent resource
+func OnAgentResourcePropertyChange(name string, handler PropertyChangeHandler) {
+	agentResourceConfig.lock.Lock()
+	defer agentResourceConfig.lock.Unlock()
+	if state, ok := agentResourceConfig.properties[name]; ok {
+		state.handlers = append(state.handlers, handler)
+	}
+}
+
+// GetConfigSources - Returns the value of the config properties the agent resource can set and where it came from, the
+// values of the sensitive properties are redacted
+func GetConfigSources() []ConfigValueSource {
+	agentResourceConfig.lock.Lock()
+	defer agentResourceConfig.lock.Unlock()
+
+	sources := make([]ConfigValueSource, 0, len(agentResourceConfig.names))
+	for _, name := range agentResourceConfig.names {
+		state := agentResourceConfig.properties[name]
+		source := state.source
+		value := state.value
+		if source == "" {
+			source = localSource(name)
+			value = state.property.Get()
+		}
+		if state.property.Sensitive {
+			value = redactedConfigValue
+		}
+		sources = append(sources, ConfigValueSource{Property: name, Source: source, Value: value})
+	}
+	return sources
+}
+
+func (c *resourceConfig) register(property ResourceProperty) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if state, ok := c.properties[property.Name]; ok {
+		// registered again, e.g. on config change, keep the handlers
+		state.property = property
+		return
+	}
+	c.properties[property.Name] = &resourcePropertyState{property: property}
+	c.names = append(c.names, property.Name)
+	sort.Strings(c.names)
+}
+
+// apply - sets the config properties from the agent and dataplane resources, the properties the resources no longer
+// set are restored to their local value, and notifies the handlers of the properties that changed
+func (c *resourceConfig) apply(agentResource, dataplaneResource *apiV1.ResourceInstance) {
+	changes := make([]propertyChange, 0)
+	c.lock.Lock()
+	for _, name := range c.names {
+		state := c.properties[name]
+		oldValue := state.value
+		if state.source == "" {
+			oldValue = state.property.Get()
+		}
+		if err := state.apply(agentResource, dataplaneResource); err != nil {
+			log.Errorf("could not set the config %s from the agent resource: %s", name, err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(oldValue, state.value) {
+			changes = append(changes, propertyChange{state: state, oldValue: oldValue, newValue: state.value})
+		}
+	}
+	c.lock.Unlock()
+
+	// the handlers are called without the lock, they can read the config sources
+	for _, change := range changes {
+		log.Infof("config %s changed, the value is set from %s", change.state.property.Name, change.state.source)
+		for _, handler := range change.state.handlers {
+			handler(change.state.property.Name, change.oldValue, change.newValue)
+		}
+	}
+}
+
+func (s *resourcePropertyState) apply(agentResource, dataplaneResource *apiV1.ResourceInstance) error {
+	current := s.property.Get()
+	// the config was parsed again, or set by the agent, since the resource value was applied
+	if !s.fromResource() || !reflect.DeepEqual(current, s.value) {
+		s.localValue = current
+	}
+
+	value, ok, source := interface{}(nil), false, ConfigSourceResource
+	if agentResource != nil && s.property.Resource != nil {
+		value, ok = s.property.Resource(agentResource)
+	}
+	if !ok && dataplaneResource != nil && s.property.Dataplane != nil {
+		value, ok = s.property.Dataplane(dataplaneResource)
+		source = ConfigSourceDataplane
+	}
+	if !ok {
+		if s.fromResource() && !reflect.DeepEqual(current, s.localValue) {
+			if err := s.property.Set(s.localValue); err != nil {
+				return err
+			}
+		}
+		s.value = s.localValue
+		s.source = localSource(s.property.Name)
+		return nil
+	}
+
+	if !reflect.DeepEqual(current, value) {
+		if err := s.property.Set(value); err != nil {
+			return err
+		}
+	}
+	s.value = value
+	s.source = source
+	return nil
+}
+
+// fromResource - returns true when the value is set by the agent or dataplane resource
+func (s *resourcePropertyState) fromResource() bool {
+	return s.source == ConfigSourceResource || s.source == ConfigSourceDataplane
+}
+
+// localSource - returns env when the environment sets the property, file otherwise
+func localSource(name string) string {
+	if _, ok := os.LookupEnv(strings.ToUpper(strings.ReplaceAll(name, ".", "_"))); ok {
+		return ConfigSourceEnv
+	}
+	return ConfigSourceFile
+}
+
+// NewStringResourceProperty - Creates a string property, the resource does not set the property when its value is
+// empty
+func NewStringResourceProperty(name string, resource func(agentResource *apiV1.ResourceInstance) string, get func() string, set func(value string)) ResourceProperty {
+	return ResourceProperty{
+		Name: name,
+		Resource: func(agentResource *apiV1.ResourceInstance) (interface{}, bool) {
+			value := resource(agentResource)
+			return value, value != ""
+		},
+		Get: func() interface{} {
+			return get()
+		},
+		Set: func(value interface{}) error {
+			set(value.(string))
+			return nil
+		},
+	}
+}
+
+// NewBoolResourceProperty - Creates a bool property, the resource sets the property when it returns true
+func NewBoolResourceProperty(name string, resource func(agentResource *apiV1.ResourceInstance) (bool, bool), get func() bool, set func(value bool)) ResourceProperty {
+	return ResourceProperty{
+		Name: name,
+		Resource: func(agentResource *apiV1.ResourceInstance) (interface{}, bool) {
+			return resource(agentResource)
+		},
+		Get: func() interface{} {
+			return get()
+		},
+		Set: func(value interface{}) error {
+			set(value.(bool))
+			return nil
+		},
+	}
+}
+
+// configValueProperty - creates a property held by the SDK, set by the agent with SetConfigValue
+func configValueProperty(name string, resource, dataplane func(res *apiV1.ResourceInstance) (interface{}, bool)) ResourceProperty {
+	return ResourceProperty{
+		Name:      name,
+		Resource:  resource,
+		Dataplane: dataplane,
+		Get: func() interface{} {
+			return GetConfigValue(name)
+		},
+		Set: func(value interface{}) error {
+			SetConfigValue(name, value)
+			return nil
+		},
+	}
+}
+
+// nonEmpty - returns the value, and false when it is the zero value
+func nonEmpty(value interface{}) (interface{}, bool) {
+	return value, !reflect.ValueOf(value).IsZero()
+}
+
+// discoveryAgentConfig - returns the config of the discovery agent resource, nil for other resources
+func discoveryAgentConfig(agentResource *apiV1.ResourceInstance) *v1alpha1.DiscoveryAgentSpecConfig {
+	if agentResource.Kind != v1alpha1.DiscoveryAgentGVK().Kind {
+		return nil
+	}
+	return &discoveryAgent(agentResource).Spec.Config
+}
+
+// traceabilityAgentConfig - returns the config of the traceability agent resource, nil for other resources
+func traceabilityAgentConfig(agentResource *apiV1.ResourceInstance) *v1alpha1.TraceabilityAgentSpecConfig {
+	if agentResource.Kind != v1alpha1.TraceabilityAgentGVK().Kind {
+		return nil
+	}
+	return &traceabilityAgent(agentResource).Spec.Config
+}
+
+// edgeDataplaneSpec - returns the spec of an Edge dataplane resource
+func edgeDataplaneSpec(dataplaneResource *apiV1.ResourceInstance) *v1alpha1.EdgeDataplaneSpec {
+	spec := &v1alpha1.EdgeDataplaneSpec{}
+	decodeSpec(dataplaneResource, spec)
+	return spec
+}
+
+// awsDataplaneSpec - returns the spec of an AWS dataplane resource
+func awsDataplaneSpec(dataplaneResource *apiV1.ResourceInstance) *v1alpha1.AwsDataplaneSpec {
+	spec := &v1alpha1.AwsDataplaneSpec{}
+	decodeSpec(dataplaneResource, spec)
+	return spec
+}
+
+func decodeSpec(res *apiV1.ResourceInstance, spec interface{}) {
+	buf, err := json.Marshal(res.Spec)
+	if err != nil {
+		return
+	}
+	json.Unmarshal(buf, spec)
+}
+
+// agentConfigProperties - the properties of the agent config in the discovery and traceability agent resources, held
+// by the SDK for the agent
+func agentConfigProperties() []ResourceProperty {
+	return []ResourceProperty{
+		configValueProperty("agent.filter", func(res *apiV1.ResourceInstance) (interface{}, bool) {
+			if cfg := discoveryAgentConfig(res); cfg != nil {
+				return nonEmpty(cfg.Filter)
+			}
+			return nil, false
+		}, nil),
+		configValueProperty("agent.ignoreTags", func(res *apiV1.ResourceInstance) (interface{}, bool) {
+			if cfg := discoveryAgentConfig(res); cfg != nil {
+				return nonEmpty(strings.Join(cfg.IgnoreTags, ","))
+			}
+			return nil, false
+		}, nil),
+		configValueProperty("agent.processHeaders", func(res *apiV1.ResourceInstance) (interface{}, bool) {
+			if cfg := traceabilityAgentConfig(res); cfg != nil {
+				// false is omitted from the model, the resource sets the property when its spec has the field
+				specConfig, _ := res.Spec["config"].(map[string]interface{})
+				if _, ok := specConfig["processHeaders"]; ok || cfg.ProcessHeaders {
+					return cfg.ProcessHeaders, true
+				}
+			}
+			return nil, false
+		}, nil),
+		configValueProperty("agent.excludeHeaders", func(res *apiV1.ResourceInstance) (interface{}, bool) {
+			if cfg := traceabilityAgentConfig(res); cfg != nil {
+				return nonEmpty(strings.Join(cfg.ExcludeHeaders, ","))
+			}
+			return nil, false
+		}, nil),
+	}
+}
+
+// dataplaneProperties - the properties of the Edge and AWS dataplane resources, held by the SDK for the agent
+func dataplaneProperties() []ResourceProperty {
+	edge := func(value func(spec *v1alpha1.EdgeDataplaneSpec) interface{}) func(res *apiV1.ResourceInstance) (interface{}, bool) {
+		return func(res *apiV1.ResourceInstance) (interface{}, bool) {
+			return nonEmpty(value(edgeDataplaneSpec(res)))
+		}
+	}
+	aws := func(value func(spec *v1alpha1.AwsDataplaneSpec) interface{}) func(res *apiV1.ResourceInstance) (interface{}, bool) {
+		return func(res *apiV1.ResourceInstance) (interface{}, bool) {
+			return nonEmpty(value(awsDataplaneSpec(res)))
+		}
+	}
+
+	return []ResourceProperty{
+		configValueProperty("dataplane.apiManager.host", nil, edge(func(spec *v1alpha1.EdgeDataplaneSpec) interface{} { return spec.ApiManager.Host })),
+		configValueProperty("dataplane.apiManager.port", nil, edge(func(spec *v1alpha1.EdgeDataplaneSpec) interface{} { return spec.ApiManager.Port })),
+		configValueProperty("dataplane.apiManager.pollInterval", nil, edge(func(spec *v1alpha1.EdgeDataplaneSpec) interface{} { return spec.ApiManager.PollInterval })),
+		configValueProperty("dataplane.apiGatewayManager.host", nil, edge(func(spec *v1alpha1.EdgeDataplaneSpec) interface{} { return spec.ApiGatewayManager.Host })),
+		configValueProperty("dataplane.apiGatewayManager.port", nil, edge(func(spec *v1alpha1.EdgeDataplaneSpec) interface{} { return spec.ApiGatewayManager.Port })),
+		configValueProperty("dataplane.apiGatewayManager.pollInterval", nil, edge(func(spec *v1alpha1.EdgeDataplaneSpec) interface{} { return spec.ApiGatewayManager.PollInterval })),
+		configValueProperty("dataplane.region", nil, aws(func(spec *v1alpha1.AwsDataplaneSpec) interface{} { return spec.Region })),
+		configValueProperty("dataplane.resourceChangeEventQueue", nil, aws(func(spec *v1alpha1.AwsDataplaneSpec) interface{} { return spec.ResourceChangeEventQueue })),
+		configValueProperty("dataplane.transactionEventQueue", nil, aws(func(spec *v1alpha1.AwsDataplaneSpec) interface{} { return spec.TransactionEventQueue })),
+		configValueProperty("dataplane.transactionLogGroup", nil, aws(func(spec *v1alpha1.AwsDataplaneSpec) interface{} { return spec.TransactionLogGroup })),
+	}
+}
+
+// defaultResourceProperties - the central, log, agent and dataplane config properties of the discovery and
+// traceability agent resources and of their dataplane resource
+func defaultResourceProperties() []ResourceProperty {
+	properties := []ResourceProperty{
+		NewStringResourceProperty("central.team",
+			func(agentResource *apiV1.ResourceInstance) string {
+				switch agentResource.Kind {
+				case v1alpha1.DiscoveryAgentGVK().Kind:
+					return discoveryAgent(agentResource).Spec.Config.OwningTeam
+				case v1alpha1.TraceabilityAgentGVK().Kind:
+					return traceabilityAgent(agentResource).Spec.Config.OwningTeam
+				}
+				return ""
+			},
+			func() string { return agent.cfg.TeamName },
+			func(value string) { agent.cfg.TeamName = value },
+		),
+		NewStringResourceProperty("central.additionalTags",
+			func(agentResource *apiV1.ResourceInstance) string {
+				if agentResource.Kind != v1alpha1.DiscoveryAgentGVK().Kind {
+					return ""
+				}
+				return strings.Join(discoveryAgent(agentResource).Spec.Config.AdditionalTags, ",")
+			},
+			func() string { return agent.cfg.TagsToPublish },
+			func(value string) { agent.cfg.TagsToPublish = value },
+		),
+		NewStringResourceProperty("log.level",
+			func(agentResource *apiV1.ResourceInstance) string {
+				switch agentResource.Kind {
+				case v1alpha1.DiscoveryAgentGVK().Kind:
+					return discoveryAgent(agentResource).Spec.Logging.Level
+				case v1alpha1.TraceabilityAgentGVK().Kind:
+					return traceabilityAgent(agentResource).Spec.Logging.Level
+				}
+				return ""
+			},
+			func() string { return log.GetLevel().String() },
+			func(value string) {
+				log.GlobalLoggerConfig.Level(value).Apply()
+			},
+		),
+	}
+	properties = append(properties, agentConfigProperties()...)
+	return append(properties, dataplaneProperties()...)
+}
@@ -0,0 +1,207 @@
+package agent
+
+import (
+	"os"
+	"testing"
+
+	v1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func createDiscoveryAgentResWithConfig(team string, tags []string, filter string) *v1.ResourceInstance {
+	res := &v1alpha1.DiscoveryAgent{
+		ResourceMeta: v1.ResourceMeta{Name: "discovery"},
+		Spec: v1alpha1.DiscoveryAgentSpec{
+			Config: v1alpha1.DiscoveryAgentSpecConfig{
+				OwningTeam:     team,
+				AdditionalTags: tags,
+				Filter:         filter,
+			},
+		},
+	}
+	instance, _ := res.AsInstance()
+	return instance
+}
+
+func TestResourceConfig(t *testing.T) {
+	agent.cfg = createCentralCfg("http://localhost", "env")
+	agent.cfg.TeamName = "localTeam"
+	resCfg := newResourceConfig()
+
+	changes := make(map[string][]interface{})
+	resCfg.properties["central.team"].handlers = []PropertyChangeHandler{
+		func(name string, oldValue, newValue interface{}) {
+			changes[name] = []interface{}{oldValue, newValue}
+		},
+	}
+
+	// the resource takes precedence over the local config
+	resCfg.apply(createDiscoveryAgentResWithConfig("resourceTeam", []string{"tag1", "tag2"}, ""), nil)
+	assert.Equal(t, "resourceTeam", agent.cfg.TeamName)
+	assert.Equal(t, "tag1,tag2", agent.cfg.TagsToPublish)
+	assert.Equal(t, []interface{}{"localTeam", "resourceTeam"}, changes["central.team"])
+	assert.Equal(t, ConfigSourceResource, resCfg.properties["central.team"].source)
+
+	// the config is parsed again, the resource value is applied without a change
+	agent.cfg = createCentralCfg("http://localhost", "env")
+	agent.cfg.TeamName = "newLocalTeam"
+	changes = make(map[string][]interface{})
+	resCfg.apply(createDiscoveryAgentResWithConfig("resourceTeam", nil, ""), nil)
+	assert.Equal(t, "resourceTeam", agent.cfg.TeamName)
+	assert.Equal(t, "", agent.cfg.TagsToPublish)
+	assert.Len(t, changes, 0)
+
+	// the resource no longer sets the team, the local value is restored
+	os.Setenv("CENTRAL_TEAM", "newLocalTeam")
+	defer os.Unsetenv("CENTRAL_TEAM")
+	resCfg.apply(createDiscoveryAgentResWithConfig("", nil, ""), nil)
+	assert.Equal(t, "newLocalTeam", agent.cfg.TeamName)
+	assert.Equal(t, []interface{}{"resourceTeam", "newLocalTeam"}, changes["central.team"])
+	assert.Equal(t, ConfigSourceEnv, resCfg.properties["central.team"].source)
+	assert.Equal(t, ConfigSourceFile, resCfg.properties["central.additionalTags"].source)
+
+	// the properties of the traceability agent resource
+	traceabilityRes := &v1alpha1.TraceabilityAgent{
+		ResourceMeta: v1.ResourceMeta{Name: "traceability"},
+		Spec: v1alpha1.TraceabilityAgentSpec{
+			Config: v1alpha1.TraceabilityAgentSpecConfig{OwningTeam: "traceabilityTeam"},
+		},
+	}
+	instance, _ := traceabilityRes.AsInstance()
+	resCfg.apply(instance, nil)
+	assert.Equal(t, "traceabilityTeam", agent.cfg.TeamName)
+}
+
+func TestRegisterResourceProperty(t *testing.T) {
+	agent.cfg = createCentralCfg("http://localhost", "env")
+	defer func() {
+		agentResourceConfig = newResourceConfig()
+	}()
+
+	err := RegisterResourceProperty(ResourceProperty{Name: "discovery.filter"})
+	assert.NotNil(t, err)
+
+	filter := "tag.Any() == \"local\""
+	err = RegisterResourceProperty(NewStringResourceProperty("discovery.filter",
+		func(res *v1.ResourceInstance) string {
+			return discoveryAgent(res).Spec.Config.Filter
+		},
+		func() string { return filter },
+		func(value string) { filter = value },
+	))
+	assert.Nil(t, err)
+
+	var newFilter interface{}
+	OnAgentResourcePropertyChange("discovery.filter", func(name string, oldValue, newValue interface{}) {
+		newFilter = newValue
+	})
+
+	agentResourceConfig.apply(createDiscoveryAgentResWithConfig("", nil, "tag.Any() == \"resource\""), nil)
+	assert.Equal(t, "tag.Any() == \"resource\"", filter)
+	assert.Equal(t, filter, newFilter)
+
+	sources := make(map[string]ConfigValueSource)
+	for _, source := range GetConfigSources() {
+		sources[source.Property] = source
+	}
+	assert.Len(t, sources, len(defaultResourceProperties())+1)
+	assert.Equal(t, ConfigValueSource{Property: "discovery.filter", Source: ConfigSourceResource, Value: filter}, sources["discovery.filter"])
+	assert.Equal(t, ConfigSourceFile, sources["central.team"].Source)
+
+	// the value of a sensitive property is redacted
+	password := "localPassword"
+	passwordProperty := NewStringResourceProperty("discovery.password",
+		func(res *v1.ResourceInstance) string { return "" },
+		func() string { return password },
+		func(value string) { password = value },
+	)
+	passwordProperty.Sensitive = true
+	assert.Nil(t, RegisterResourceProperty(passwordProperty))
+	for _, source := range GetConfigSources() {
+		if source.Property == "discovery.password" {
+			assert.Equal(t, ConfigValueSource{Property: "discovery.password", Source: ConfigSourceFile, Value: redactedConfigValue}, source)
+		}
+	}
+	assert.Equal(t, "localPassword", password)
+}
+
+func TestAgentConfigProperties(t *testing.T) {
+	agent.cfg = createCentralCfg("http://localhost", "env")
+	defer func() {
+		configValues.values = make(map[string]interface{})
+	}()
+	resCfg := newResourceConfig()
+
+	// the local values of the agent
+	SetConfigValue("agent.filter", "tag.Any() == \"local\"")
+	SetConfigValue("agent.processHeaders", true)
+
+	res := &v1alpha1.DiscoveryAgent{
+		ResourceMeta: v1.ResourceMeta{Name: "discovery"},
+		Spec: v1alpha1.DiscoveryAgentSpec{
+			Config: v1alpha1.DiscoveryAgentSpecConfig{
+				Filter:     "tag.Any() == \"resource\"",
+				IgnoreTags: []string{"tag1", "tag2"},
+			},
+		},
+	}
+	instance, _ := res.AsInstance()
+	resCfg.apply(instance, nil)
+	assert.Equal(t, "tag.Any() == \"resource\"", GetConfigValue("agent.filter"))
+	assert.Equal(t, "tag1,tag2", GetConfigValue("agent.ignoreTags"))
+	assert.Equal(t, true, GetConfigValue("agent.processHeaders"))
+
+	// the resource no longer sets the filter, the local value is restored
+	res.Spec.Config = v1alpha1.DiscoveryAgentSpecConfig{}
+	instance, _ = res.AsInstance()
+	resCfg.apply(instance, nil)
+	assert.Equal(t, "tag.Any() == \"local\"", GetConfigValue("agent.filter"))
+	assert.Nil(t, GetConfigValue("agent.ignoreTags"))
+
+	// the traceability agent resource sets process headers to false
+	traceabilityRes := &v1alpha1.TraceabilityAgent{
+		ResourceMeta: v1.ResourceMeta{Name: "traceability"},
+		Spec: v1alpha1.TraceabilityAgentSpec{
+			Config: v1alpha1.TraceabilityAgentSpecConfig{ExcludeHeaders: []string{"Authorization", "Cookie"}},
+		},
+	}
+	instance, _ = traceabilityRes.AsInstance()
+	resCfg.apply(instance, nil)
+	assert.Equal(t, true, GetConfigValue("agent.processHeaders"))
+	assert.Equal(t, "Authorization,Cookie", GetConfigValue("agent.excludeHeaders"))
+
+	instance.Spec["config"].(map[string]interface{})["processHeaders"] = false
+	resCfg.apply(instance, nil)
+	assert.Equal(t, false, GetConfigValue("agent.processHeaders"))
+	assert.Equal(t, ConfigSourceResource, resCfg.properties["agent.processHeaders"].source)
+}
+
+func TestDataplaneProperties(t *testing.T) {
+	agent.cfg = createCentralCfg("http://localhost", "env")
+	defer func() {
+		configValues.values = make(map[string]interface{})
+	}()
+	resCfg := newResourceConfig()
+	SetConfigValue("dataplane.apiManager.host", "localhost")
+
+	dataplane := &v1.ResourceInstance{
+		ResourceMeta: v1.ResourceMeta{Name: "dataplane"},
+		Spec: map[string]interface{}{
+			"apiManager": map[string]interface{}{"host": "apimanager", "port": 8075},
+		},
+	}
+	resCfg.apply(createDiscoveryAgentResWithConfig("", nil, ""), dataplane)
+	assert.Equal(t, "apimanager", GetConfigValue("dataplane.apiManager.host"))
+	assert.Equal(t, int32(8075), GetConfigValue("dataplane.apiManager.port"))
+	assert.Equal(t, ConfigSourceDataplane, resCfg.properties["dataplane.apiManager.host"].source)
+	assert.Nil(t, GetConfigValue("dataplane.region"))
+
+	dataplane = &v1.ResourceInstance{
+		ResourceMeta: v1.ResourceMeta{Name: "dataplane"},
+		Spec:         map[string]interface{}{"region": "eu-west-1"},
+	}
+	resCfg.apply(createDiscoveryAgentResWithConfig("", nil, ""), dataplane)
+	assert.Equal(t, "localhost", GetConfigValue("dataplane.apiManager.host"))
+	assert.Equal(t, "eu-west-1", GetConfigValue("dataplane.region"))
+}
@@ -0,0 +1,273 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	coreapi "github.com/Axway/agent-sdk/pkg/api"
+	"github.com/Axway/agent-sdk/pkg/apic"
+	apiclient "github.com/Axway/agent-sdk/pkg/apic/apiserver/clients/api/v1"
+	apiV1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
+	"github.com/Axway/agent-sdk/pkg/config"
+	"github.com/Axway/agent-sdk/pkg/util"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+type watchClient interface {
+	Watch(ctx context.Context, handler apiclient.EventHandler, options ...apiclient.WatchOption) (int64, error)
+}
+
+// resourceWatcher - streams the API server events for the resources the agent caches,
+// replacing the periodic full synchronization of the discovery cache while connected
+type resourceWatcher struct {
+	client        watchClient
+	sequence      int64
+	streaming     bool
+	streamingLock sync.RWMutex
+	retryInterval time.Duration
+	cancel        context.CancelFunc
+	done          chan struct{}
+}
+
+func newResourceWatcher(client watchClient, retryInterval time.Duration) *resourceWatcher {
+	return &resourceWatcher{
+		client:        client,
+		retryInterval: retryInterval,
+	}
+}
+
+// newAPIServerWatchClient - creates the API server client used for watching, authenticated with the agent token
+func newAPIServerWatchClient(cfg *config.CentralConfiguration) watchClient {
+	httpClient := &http.Client{}
+	if cfg.GetTLSConfig() != nil {
+		proxyURL, err := url.Parse(cfg.GetProxyURL())
+		if err != nil {
+			log.Errorf("Error parsing proxyURL from config; creating a non-proxy client: %s", err.Error())
+		}
+		httpClient.Transport = &http.Transport{
+			TLSClientConfig: cfg.GetTLSConfig().BuildTLSConfig(),
+			Proxy:           util.GetProxyURL(proxyURL),
+		}
+	}
+
+	return apiclient.NewClient(
+		cfg.GetURL()+"/apis",
+		apiclient.HTTPClient(httpClient),
+		apiclient.TokenGetterAuth(cfg.GetTenantID(), agent.tokenRequester),
+		apiclient.UserAgent(config.AgentTypeName+"/"+config.AgentVersion),
+	)
+}
+
+func (w *resourceWatcher) isStreaming() bool {
+	w.streamingLock.RLock()
+	defer w.streamingLock.RUnlock()
+	return w.streaming
+}
+
+func (w *resourceWatcher) setStreaming(streaming bool) {
+	w.streamingLock.Lock()
+	defer w.streamingLock.Unlock()
+	w.streaming = streaming
+}
+
+// start - synchronizes the cache and then watches for changes until stopped, or until the agent is shut down
+func (w *resourceWatcher) start() {
+	ctx, cancel := context.WithCancel(GetContext())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+	go func() {
+		defer close(w.done)
+		w.run(ctx)
+	}()
+	RegisterShutdownHook("stopping the resource watcher", w.shutdown)
+}
+
+func (w *resourceWatcher) stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+}
+
+// shutdown - stops the watch and waits for the stream to be closed, until the context is done
+func (w *resourceWatcher) shutdown(ctx context.Context) error {
+	w.stop()
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *resourceWatcher) run(ctx context.Context) {
+	synchronizeResources()
+	for {
+		w.setStreaming(true)
+		lastSequence := w.sequence
+		sequence, err := w.client.Watch(ctx, w, w.watchOptions()...)
+		w.setStreaming(false)
+		w.sequence = sequence
+		if ctx.Err() != nil {
+			return
+		}
+
+		switch watchErr := err.(type) {
+		case apiclient.GoneError:
+			// the stream can not be resumed, resynchronize and watch from the current events
+			log.Debugf("Unable to resume watching API server resources from sequence %d, resynchronizing", sequence)
+			w.sequence = 0
+			synchronizeResources()
+			continue
+		case apiclient.GapError:
+			// resynchronize the missed changes, then resume with the event received after the gap
+			log.Debugf("Missed API server resource events: %s, resynchronizing", watchErr.Error())
+			synchronizeResources()
+			w.sequence = watchErr.Received - 1
+			continue
+		case nil:
+			if sequence != lastSequence {
+				log.Trace("API server watch stream closed, reconnecting")
+				continue
+			}
+			log.Debugf("API server watch stream closed without events, retrying in %s", w.retryInterval)
+		default:
+			log.Debugf("Error watching API server resources, retrying in %s: %s", w.retryInterval, err.Error())
+		}
+
+		// the discovery cache job polls while the watch is disconnected
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.retryInterval):
+		}
+	}
+}
+
+func (w *resourceWatcher) watchOptions() []apiclient.WatchOption {
+	kinds := []apiV1.GroupKind{
+		v1alpha1.APIServiceGVK().GroupKind,
+		v1alpha1.SecretGVK().GroupKind,
+	}
+	if agent.cfg.GetAgentType() == config.DiscoveryAgent {
+		kinds = append(kinds, v1alpha1.ConsumerInstanceGVK().GroupKind)
+	}
+	if agentResourceKind := getAgentResourceKind(); agentResourceKind != nil {
+		kinds = append(kinds, *agentResourceKind)
+	}
+
+	return []apiclient.WatchOption{
+		apiclient.WithSequence(w.sequence),
+		apiclient.WithKinds(kinds...),
+		apiclient.WithWatchScope(agent.cfg.GetEnvironmentName()),
+	}
+}
+
+func getAgentResourceKind() *apiV1.GroupKind {
+	if agent.cfg.GetAgentName() == "" {
+		return nil
+	}
+
+	var gvk apiV1.GroupVersionKind
+	switch getAgentResourceType() {
+	case v1alpha1.DiscoveryAgentResource:
+		gvk = v1alpha1.DiscoveryAgentGVK()
+	case v1alpha1.TraceabilityAgentResource:
+		gvk = v1alpha1.TraceabilityAgentGVK()
+	default:
+		return nil
+	}
+	return &gvk.GroupKind
+}
+
+// Handle - applies the API server event to the agent cache and configuration
+func (w *resourceWatcher) Handle(event *apiV1.Event) {
+	syncLock.Lock()
+	defer syncLock.Unlock()
+
+	log.Tracef("received %s for %s %s", event.Type, event.Payload.Kind, event.Payload.Name)
+	switch event.Payload.Kind {
+	case v1alpha1.APIServiceGVK().Kind:
+		handleAPIServiceEvent(event)
+	case v1alpha1.ConsumerInstanceGVK().Kind:
+		handleConsumerInstanceEvent(event)
+	case v1alpha1.SecretGVK().Kind:
+		handleSecretEvent(event)
+	case v1alpha1.DiscoveryAgentGVK().Kind, v1alpha1.TraceabilityAgentGVK().Kind:
+		handleAgentResourceEvent(event)
+	}
+}
+
+func resourceInstanceFromEvent(event *apiV1.Event) apiV1.ResourceInstance {
+	return apiV1.ResourceInstance{
+		ResourceMeta: apiV1.ResourceMeta{
+			GroupVersionKind: apiV1.GroupVersionKind{GroupKind: event.Payload.GroupKind},
+			Name:             event.Payload.Name,
+			Metadata: apiV1.Metadata{
+				ID:         event.Payload.ID,
+				Scope:      event.Payload.Scope,
+				References: event.Payload.References,
+			},
+			Attributes: event.Payload.Attributes,
+			Tags:       event.Payload.Tags,
+		},
+	}
+}
+
+func handleAPIServiceEvent(event *apiV1.Event) {
+	if _, found := event.Payload.Attributes[apic.AttrExternalAPIID]; !found {
+		return
+	}
+
+	if event.Type == apiV1.ResourceEntryDeletedEvent {
+		key := event.Payload.Attributes[apic.AttrExternalAPIID]
+		if externalAPIPrimaryKey, found := event.Payload.Attributes[apic.AttrExternalAPIPrimaryKey]; found {
+			key = externalAPIPrimaryKey
+		}
+		agent.apiMap.Delete(key)
+		log.Tracef("removed api id %s from API cache", key)
+		return
+	}
+
+	// the event carries the attributes only, fetch the resource for the cached details
+	apiService := resourceInstanceFromEvent(event)
+	response, err := agent.apicClient.ExecuteAPI(coreapi.GET, agent.cfg.GetServicesURL()+"/"+event.Payload.Name, nil, nil)
+	if err == nil {
+		json.Unmarshal(response, &apiService)
+	} else {
+		log.Debugf("Error fetching API service %s, caching the event details: %s", event.Payload.Name, err.Error())
+	}
+	addItemToAPICache(apiService)
+}
+
+func handleConsumerInstanceEvent(event *apiV1.Event) {
+	if event.Type == apiV1.ResourceEntryDeletedEvent || agent.cfg.GetAgentType() != config.DiscoveryAgent {
+		return
+	}
+	if agent.apiValidator == nil {
+		return
+	}
+	validateAPIOnDataplane([]apiV1.ResourceInstance{resourceInstanceFromEvent(event)})
+}
+
+func handleSecretEvent(event *apiV1.Event) {
+	// secrets may be referenced by the agent configuration, let the agent re-resolve it
+	if agent.configChangeHandler != nil {
+		agent.configChangeHandler()
+	}
+}
+
+func handleAgentResourceEvent(event *apiV1.Event) {
+	if event.Payload.Name != agent.cfg.GetAgentName() {
+		return
+	}
+	if event.Type == apiV1.ResourceEntryDeletedEvent {
+		log.Warnf("The agent resource %s was deleted from Amplify Central", event.Payload.Name)
+		return
+	}
+	fetchConfig()
+}
@@ -0,0 +1,167 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/apic"
+	apiclient "github.com/Axway/agent-sdk/pkg/apic/apiserver/clients/api/v1"
+	v1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func apiServiceEvent(eventType v1.EventType, name, externalAPIID string) *v1.Event {
+	return &v1.Event{
+		Type: eventType,
+		Payload: v1.EventPayload{
+			GroupKind: v1alpha1.APIServiceGVK().GroupKind,
+			Scope:     v1.MetadataScope{Name: "test"},
+			Name:      name,
+			Attributes: map[string]string{
+				apic.AttrExternalAPIID:   externalAPIID,
+				apic.AttrExternalAPIName: name,
+			},
+		},
+	}
+}
+
+func isInAPICache(key string) func() bool {
+	return func() bool {
+		syncLock.Lock()
+		defer syncLock.Unlock()
+		_, err := agent.apiMap.Get(key)
+		return err == nil
+	}
+}
+
+func TestResourceWatcher(t *testing.T) {
+	var lock sync.Mutex
+	apiServices := `[{"name":"svc1","attributes":{"externalAPIID":"ext1","externalAPIName":"svc1"}}]`
+	s := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if strings.Contains(req.RequestURI, "/auth") {
+			token := "{\"access_token\":\"somevalue\",\"expires_in\": 12235677}"
+			resp.Write([]byte(token))
+		}
+		if strings.HasSuffix(req.URL.Path, "/apis/management/v1alpha1/environments/test/apiservices") {
+			resp.Write([]byte(apiServices))
+		}
+		if strings.HasSuffix(req.URL.Path, "/apis/management/v1alpha1/environments/test/apiservices/svc2") {
+			resp.Write([]byte(`{"name":"svc2","title":"Service 2","attributes":{"externalAPIID":"ext2","externalAPIName":"svc2"}}`))
+		}
+	}))
+	defer s.Close()
+
+	cfg := createCentralCfg(s.URL, "test")
+	resetResources()
+	err := Initialize(cfg)
+	assert.Nil(t, err)
+
+	secretChanges := 0
+	OnConfigChange(func() {
+		secretChanges++
+	})
+	defer OnConfigChange(nil)
+
+	fws := apiclient.NewFakeWatchServer()
+	defer fws.Close()
+
+	watcher := newResourceWatcher(apiclient.NewClient(fws.URL), 10*time.Millisecond)
+	watcher.start()
+	defer watcher.stop()
+
+	// initial synchronization
+	assert.Eventually(t, isInAPICache("ext1"), time.Second, 10*time.Millisecond)
+	assert.Eventually(t, watcher.isStreaming, time.Second, 10*time.Millisecond)
+	discoveryJob := &discoveryCache{watcher: watcher}
+	assert.Nil(t, discoveryJob.Execute())
+
+	// created service is fetched and cached, give the watch time to connect
+	time.Sleep(100 * time.Millisecond)
+	fws.Handle(apiServiceEvent(v1.ResourceEntryCreatedEvent, "svc2", "ext2"))
+	assert.Eventually(t, isInAPICache("ext2"), time.Second, 10*time.Millisecond)
+	syncLock.Lock()
+	cached, _ := agent.apiMap.Get("ext2")
+	syncLock.Unlock()
+	assert.Equal(t, "Service 2", cached.(v1.ResourceInstance).Title)
+
+	// deleted service is removed from the cache
+	fws.Handle(apiServiceEvent(v1.ResourceEntryDeletedEvent, "svc1", "ext1"))
+	assert.Eventually(t, func() bool { return !isInAPICache("ext1")() }, time.Second, 10*time.Millisecond)
+
+	// secret changes are handed to the config change handler
+	fws.Handle(&v1.Event{
+		Type: v1.ResourceEntryUpdatedEvent,
+		Payload: v1.EventPayload{
+			GroupKind: v1alpha1.SecretGVK().GroupKind,
+			Scope:     v1.MetadataScope{Name: "test"},
+			Name:      "secret",
+		},
+	})
+	assert.Eventually(t, func() bool {
+		syncLock.Lock()
+		defer syncLock.Unlock()
+		return secretChanges == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// a gap in the stream triggers a full resynchronization
+	lock.Lock()
+	apiServices = `[{"name":"svc3","attributes":{"externalAPIID":"ext3","externalAPIName":"svc3"}}]`
+	lock.Unlock()
+	fws.Skip(1)
+	fws.Handle(apiServiceEvent(v1.ResourceEntryUpdatedEvent, "svc2", "ext2"))
+	assert.Eventually(t, isInAPICache("ext3"), time.Second, 10*time.Millisecond)
+	assert.Eventually(t, isInAPICache("ext2"), time.Second, 10*time.Millisecond)
+	assert.Eventually(t, watcher.isStreaming, time.Second, 10*time.Millisecond)
+}
+
+func TestResourceWatcherShutdown(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if strings.Contains(req.RequestURI, "/auth") {
+			resp.Write([]byte("{\"access_token\":\"somevalue\",\"expires_in\": 12235677}"))
+		}
+	}))
+	defer s.Close()
+
+	cfg := createCentralCfg(s.URL, "test")
+	resetResources()
+	assert.Nil(t, Initialize(cfg))
+
+	prevClient, prevJobs := agent.apicClient, shutdownJobs
+	shutdownJobs = func(ctx context.Context) error { return nil }
+	shutdownLock.Lock()
+	shutdownStarted, shutdownHooks = false, make([]namedShutdownHook, 0)
+	shutdownLock.Unlock()
+	SetContext(context.Background())
+	defer func() {
+		agent.apicClient, shutdownJobs = prevClient, prevJobs
+		shutdownLock.Lock()
+		shutdownStarted, shutdownHooks = false, make([]namedShutdownHook, 0)
+		shutdownLock.Unlock()
+		SetContext(context.Background())
+	}()
+
+	fws := apiclient.NewFakeWatchServer()
+	defer fws.Close()
+	watcher := newResourceWatcher(apiclient.NewClient(fws.URL), 10*time.Millisecond)
+	watcher.start()
+	assert.Eventually(t, watcher.isStreaming, time.Second, 10*time.Millisecond)
+
+	// the shutdown stops the watch stream
+	steps := make([]string, 0)
+	agent.apicClient = &shutdownSvcClient{steps: &steps}
+	assert.Nil(t, Shutdown(context.Background()))
+	select {
+	case <-watcher.done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "the resource watcher did not exit on shutdown")
+	}
+	assert.False(t, watcher.isStreaming())
+}
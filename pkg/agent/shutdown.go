@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/jobs"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+// defaultShutdownTimeout - the time given to the shutdown when the central config does not set it
+const defaultShutdownTimeout = 30 * time.Second
+
+// ShutdownHook - Callback for the agent specific cleanup on shutdown, it should return once the context is done
+type ShutdownHook func(ctx context.Context) error
+
+type namedShutdownHook struct {
+	name string
+	hook ShutdownHook
+}
+
+// shutdownJobs - stops the jobs, replaced in the tests
+var shutdownJobs = jobs.Shutdown
+
+var (
+	shutdownHooks     = make([]namedShutdownHook, 0)
+	shutdownLock      sync.Mutex
+	shutdownStarted   bool
+	rootContextLock   sync.RWMutex
+	rootContext       context.Context
+	cancelRootContext context.CancelFunc
+)
+
+// RegisterShutdownHook - Registers a cleanup run on shutdown, once the jobs are stopped and the in-flight publishes
+// have ended. The hooks are run in the order they were registered
+func RegisterShutdownHook(name string, hook ShutdownHook) {
+	shutdownLock.Lock()
+	defer shutdownLock.Unlock()
+	shutdownHooks = append(shutdownHooks, namedShutdownHook{name: name, hook: hook})
+}
+
+// SetContext - Sets the root context of the agent, the context given to the jobs and to the AMPLIFY Central client
+// derives from it and is canceled at the end of the shutdown
+func SetContext(ctx context.Context) {
+	rootContextLock.Lock()
+	rootContext, cancelRootContext = context.WithCancel(ctx)
+	rootContextLock.Unlock()
+
+	jobs.SetContext(GetContext())
+	if agent.apicClient != nil {
+		agent.apicClient.SetContext(GetContext())
+	}
+}
+
+// GetContext - Returns the root context of the agent, canceled at the end of the shutdown
+func GetContext() context.Context {
+	rootContextLock.RLock()
+	defer rootContextLock.RUnlock()
+	if rootContext == nil {
+		return context.Background()
+	}
+	return rootContext
+}
+
+// getShutdownTimeout - returns the time given to the shutdown
+func getShutdownTimeout() time.Duration {
+	if agent.cfg != nil && agent.cfg.GetShutdownTimeout() > 0 {
+		return agent.cfg.GetShutdownTimeout()
+	}
+	return defaultShutdownTimeout
+}
+
+// Shutdown - Shuts the agent down, each step is given until the context is done
+//   - stops the jobs, waiting for their running executions
+//   - stops processing the subscriptions and publishing the APIs, waiting for the in-flight publishes
+//   - runs the registered shutdown hooks, flushing the metric cache
+//   - sets the agent status to stopped
+//   - cancels the root context, ending the requests still running
+//
+// The agent is shut down once, the next calls return nil
+func Shutdown(ctx context.Context) error {
+	shutdownLock.Lock()
+	if shutdownStarted {
+		shutdownLock.Unlock()
+		return nil
+	}
+	shutdownStarted = true
+	hooks := make([]namedShutdownHook, len(shutdownHooks))
+	copy(hooks, shutdownHooks)
+	shutdownLock.Unlock()
+
+	log.Info("Shutting down the agent")
+	var shutdownErr error
+	runStep := func(name string, step func(ctx context.Context) error) {
+		if err := step(ctx); err != nil {
+			log.Errorf("error on shutdown, %s: %s", name, err.Error())
+			if shutdownErr == nil {
+				shutdownErr = err
+			}
+		}
+	}
+
+	runStep("stopping the jobs", shutdownJobs)
+	if agent.apicClient != nil {
+		runStep("ending the in-flight publishes", agent.apicClient.Shutdown)
+	}
+	for _, h := range hooks {
+		runStep(h.name, h.hook)
+	}
+	cleanUp()
+
+	rootContextLock.RLock()
+	if cancelRootContext != nil {
+		cancelRootContext()
+	}
+	rootContextLock.RUnlock()
+	log.Info("The agent is shut down")
+	return shutdownErr
+}
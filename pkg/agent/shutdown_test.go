@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type shutdownSvcClient struct {
+	mockSvcClient
+	steps *[]string
+}
+
+func (m *shutdownSvcClient) Shutdown(ctx context.Context) error {
+	*m.steps = append(*m.steps, "publishes")
+	return nil
+}
+
+func TestShutdown(t *testing.T) {
+	steps := make([]string, 0)
+	shutdownJobs = func(ctx context.Context) error {
+		steps = append(steps, "jobs")
+		return nil
+	}
+	prevCfg, prevClient := agent.cfg, agent.apicClient
+	agent.cfg = nil
+	InitializeForTest(&shutdownSvcClient{steps: &steps})
+	defer func() {
+		agent.cfg = prevCfg
+		agent.apicClient = prevClient
+	}()
+
+	parent, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	SetContext(parent)
+	RegisterShutdownHook("first", func(ctx context.Context) error {
+		steps = append(steps, "first")
+		return fmt.Errorf("first failed")
+	})
+	RegisterShutdownHook("second", func(ctx context.Context) error {
+		// the root context is canceled after the hooks
+		assert.Nil(t, GetContext().Err())
+		steps = append(steps, "second")
+		return nil
+	})
+
+	// the steps run in order, a failed step does not stop the shutdown
+	err := Shutdown(context.Background())
+	assert.NotNil(t, err)
+	assert.Equal(t, "first failed", err.Error())
+	assert.Equal(t, []string{"jobs", "publishes", "first", "second"}, steps)
+	assert.Equal(t, context.Canceled, GetContext().Err())
+	assert.Nil(t, parent.Err())
+
+	// the agent is shut down once
+	assert.Nil(t, Shutdown(context.Background()))
+	assert.Len(t, steps, 4)
+}
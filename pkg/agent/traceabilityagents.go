@@ -23,10 +23,3 @@ func createTraceabilityAgentStatusResource(status, message string) *v1alpha1.Tra
 
 	return &agentRes
 }
-
-func mergeTraceabilityAgentWithConfig(cfg *config.CentralConfiguration) {
-	ta := traceabilityAgent(GetAgentResource())
-	resCfgTeamName := ta.Spec.Config.OwningTeam
-	resCfgLogLevel := ta.Spec.Logging.Level
-	applyResConfigToCentralConfig(cfg, "", resCfgTeamName, resCfgLogLevel)
-}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitBreaker - tracks the consecutive failed requests per host, a request counts once after its retries and only
+// the transport errors and 5xx responses are failures. The circuit of a host opens when the failure threshold is
+// reached and requests fail fast until the open duration passed.
+// A single trial request is then let through, its result closes or reopens the circuit.
+type circuitBreaker struct {
+	failureThreshold int
+	openDuration     time.Duration
+	circuits         map[string]*circuit
+	lock             sync.Mutex
+}
+
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+func newCircuitBreaker(failureThreshold int, openDuration time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		failureThreshold: failureThreshold,
+		openDuration:     openDuration,
+		circuits:         make(map[string]*circuit),
+	}
+}
+
+// allow - returns true when a request may be sent to the host
+func (cb *circuitBreaker) allow(host string) bool {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	c, found := cb.circuits[host]
+	if !found || c.openUntil.IsZero() {
+		return true
+	}
+	if time.Now().Before(c.openUntil) || c.trial {
+		return false
+	}
+	c.trial = true
+	return true
+}
+
+// release - lets another trial request through when the trial request was canceled before its result
+func (cb *circuitBreaker) release(host string) {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+	if c, found := cb.circuits[host]; found {
+		c.trial = false
+	}
+}
+
+// record - records the result of a request sent to the host
+func (cb *circuitBreaker) record(host string, success bool) {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	c, found := cb.circuits[host]
+	if !found {
+		if success {
+			return
+		}
+		c = &circuit{}
+		cb.circuits[host] = c
+	}
+
+	if success {
+		delete(cb.circuits, host)
+		return
+	}
+
+	c.failures++
+	c.trial = false
+	if c.failures >= cb.failureThreshold || !c.openUntil.IsZero() {
+		c.openUntil = time.Now().Add(cb.openDuration)
+	}
+}
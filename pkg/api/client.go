@@ -8,12 +8,14 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Axway/agent-sdk/pkg/config"
 	"github.com/Axway/agent-sdk/pkg/util"
 	log "github.com/Axway/agent-sdk/pkg/util/log"
+	"github.com/Axway/agent-sdk/pkg/util/metrics"
 )
 
 // HTTP const definitions
@@ -23,8 +25,9 @@ const (
 	PUT    string = http.MethodPut
 	DELETE string = http.MethodDelete
 
-	defaultTimeout     = time.Second * 60
-	responseBufferSize = 2048
+	defaultTimeout      = time.Second * 60
+	responseBufferSize  = 2048
+	maxIdleConnsPerHost = 10
 )
 
 // Request - the request object used when communicating to an API
@@ -46,40 +49,78 @@ type Response struct {
 // Client -
 type Client interface {
 	Send(request Request) (*Response, error)
+	SendWithContext(ctx context.Context, request Request) (*Response, error)
+}
+
+// ClientOpt - options for the HTTP client
+type ClientOpt func(*httpClient)
+
+// WithRetryPolicy - retries the failed requests allowed by the policy
+func WithRetryPolicy(policy RetryPolicy) ClientOpt {
+	return func(c *httpClient) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithCircuitBreaker - fails the requests to a host fast for the open duration,
+// after the threshold of consecutive failed requests to that host is reached
+func WithCircuitBreaker(failureThreshold int, openDuration time.Duration) ClientOpt {
+	return func(c *httpClient) {
+		c.breaker = newCircuitBreaker(failureThreshold, openDuration)
+	}
+}
+
+// WithContext - sets the context of the requests sent with Send, they are canceled when the context is done
+func WithContext(ctx context.Context) ClientOpt {
+	return func(c *httpClient) {
+		c.ctx = ctx
+	}
 }
 
 type httpClient struct {
 	Client
-	httpClient *http.Client
-	timeout    time.Duration
+	httpClient  *http.Client
+	timeout     time.Duration
+	retryPolicy RetryPolicy
+	breaker     *circuitBreaker
+	ctx         context.Context
 }
 
 // NewClient - creates a new HTTP client
-func NewClient(cfg config.TLSConfig, proxyURL string) Client {
+func NewClient(cfg config.TLSConfig, proxyURL string, opts ...ClientOpt) Client {
 	timeout := getTimeoutFromEnvironment()
-	return NewClientWithTimeout(cfg, proxyURL, timeout)
+	return NewClientWithTimeout(cfg, proxyURL, timeout, opts...)
 }
 
 // NewClientWithTimeout - creates a new HTTP client, with a timeout
-func NewClientWithTimeout(cfg config.TLSConfig, proxyURL string, timeout time.Duration) Client {
-	httpCli := http.DefaultClient
+func NewClientWithTimeout(cfg config.TLSConfig, proxyURL string, timeout time.Duration, opts ...ClientOpt) Client {
+	c := &httpClient{
+		ctx:     context.Background(),
+		timeout: timeout,
+		httpClient: &http.Client{
+			Transport: newTransport(cfg, proxyURL),
+			Timeout:   timeout,
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// newTransport - creates the transport pooling the connections to the hosts, the idle connections are reused by the requests
+func newTransport(cfg config.TLSConfig, proxyURL string) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
 	if cfg != nil {
 		url, err := url.Parse(proxyURL)
 		if err != nil {
 			log.Errorf("Error parsing proxyURL from config; creating a non-proxy client: %s", err.Error())
 		}
-		httpCli = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: cfg.BuildTLSConfig(),
-				Proxy:           util.GetProxyURL(url),
-			},
-		}
-	}
-	httpCli.Timeout = timeout
-	return &httpClient{
-		timeout:    timeout,
-		httpClient: httpCli,
+		transport.TLSClientConfig = cfg.BuildTLSConfig()
+		transport.Proxy = util.GetProxyURL(url)
 	}
+	return transport
 }
 
 func getTimeoutFromEnvironment() time.Duration {
@@ -153,10 +194,60 @@ func (c *httpClient) prepareAPIResponse(res *http.Response, timer *time.Timer) (
 	return &response, err
 }
 
-// Send - send the http request and returns the API Response
+// Send - send the http request and returns the API Response, the request is canceled when the context of the client is done
 func (c *httpClient) Send(request Request) (*Response, error) {
+	return c.SendWithContext(c.ctx, request)
+}
+
+// SendWithContext - send the http request and returns the API Response, the request is canceled when the context is done.
+// Requests failing with a retryable error are retried with backoff as allowed by the retry policy of the client, the
+// circuit breaker records the result of the request once the retries are done
+func (c *httpClient) SendWithContext(ctx context.Context, request Request) (*Response, error) {
+	host := getHost(request.URL)
+	if c.breaker == nil {
+		return c.sendWithRetries(ctx, request)
+	}
+	if !c.breaker.allow(host) {
+		return nil, ErrCircuitOpen.FormatError(host)
+	}
+
+	response, err := c.sendWithRetries(ctx, request)
+	if ctx.Err() != nil {
+		// canceled by the caller, the host did not fail
+		c.breaker.release(host)
+	} else {
+		c.breaker.record(host, !isServerFailure(response, err))
+	}
+	return response, err
+}
+
+// isServerFailure - returns true for the transport errors and the 5xx responses, a 4xx is an answer of the server
+func isServerFailure(response *Response, err error) bool {
+	return err != nil || response == nil || response.Code >= http.StatusInternalServerError
+}
+
+// sendWithRetries - sends the request, retried with backoff as allowed by the retry policy of the client
+func (c *httpClient) sendWithRetries(ctx context.Context, request Request) (*Response, error) {
+	for attempt := 0; ; attempt++ {
+		response, err := c.send(ctx, request)
+		if attempt >= c.retryPolicy.MaxRetries || ctx.Err() != nil || !isRetryable(request.Method, response, err) {
+			return response, err
+		}
+
+		delay := c.retryPolicy.backoff(attempt, response)
+		log.Tracef("retrying %s %s in %s, attempt %d of %d", request.Method, request.URL, delay, attempt+1, c.retryPolicy.MaxRetries)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *httpClient) send(ctx context.Context, request Request) (*Response, error) {
 	startTime := time.Now()
-	ctx := context.Background()
 	cancelCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -165,6 +256,7 @@ func (c *httpClient) Send(request Request) (*Response, error) {
 	statusCode := 0
 	defer func() {
 		duration := time.Now().Sub(startTime)
+		recordRequest(request.Method, getHost(request.URL), statusCode, duration)
 		if err != nil {
 			log.Tracef("%s [%dms] - ERR - %s - %s", req.Method, duration.Milliseconds(), req.URL.String(), err.Error())
 		} else {
@@ -180,9 +272,7 @@ func (c *httpClient) Send(request Request) (*Response, error) {
 	timer := time.AfterFunc(c.timeout, func() {
 		cancel()
 	})
-
-	// Prevent reuse of the tcp connection to the same host
-	req.Close = true
+	defer timer.Stop()
 
 	res, err := c.httpClient.Do(req)
 	if err != nil {
@@ -195,3 +285,20 @@ func (c *httpClient) Send(request Request) (*Response, error) {
 
 	return parseResponse, err
 }
+
+// recordRequest - records the request in the metrics of the API clients, the status code is 0 when no response was
+// received
+func recordRequest(method, host string, statusCode int, duration time.Duration) {
+	metrics.IncCounter("agent_api_requests", "The requests sent by the API clients, by status code",
+		metrics.Labels{"method": method, "host": host, "code": strconv.Itoa(statusCode)}, 1)
+	metrics.ObserveHistogram("agent_api_request_duration_seconds", "The durations of the requests sent by the API clients",
+		nil, metrics.Labels{"method": method, "host": host}, duration.Seconds())
+}
+
+func getHost(requestURL string) string {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return requestURL
+	}
+	return u.Host
+}
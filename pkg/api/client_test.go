@@ -0,0 +1,238 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/util/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+type testServer struct {
+	*httptest.Server
+	lock      sync.Mutex
+	requests  int
+	responses []int
+	remotes   map[string]struct{}
+	header    http.Header
+}
+
+func newTestServer(responses ...int) *testServer {
+	ts := &testServer{responses: responses, remotes: map[string]struct{}{}}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		ts.lock.Lock()
+		defer ts.lock.Unlock()
+		ts.remotes[req.RemoteAddr] = struct{}{}
+		code := http.StatusOK
+		if ts.requests < len(ts.responses) {
+			code = ts.responses[ts.requests]
+		}
+		ts.requests++
+		for key, values := range ts.header {
+			resp.Header()[key] = values
+		}
+		resp.WriteHeader(code)
+		resp.Write([]byte("response"))
+	}))
+	return ts
+}
+
+func (ts *testServer) requestCount() int {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	return ts.requests
+}
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+}
+
+func TestSendReusesConnections(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	client := NewClient(nil, "")
+	for i := 0; i < 3; i++ {
+		response, err := client.Send(Request{Method: GET, URL: server.URL})
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "response", string(response.Body))
+	}
+	assert.Len(t, server.remotes, 1)
+}
+
+func TestSendRetries(t *testing.T) {
+	testCases := []struct {
+		name      string
+		method    string
+		responses []int
+		expCode   int
+		expCount  int
+	}{
+		{name: "retry 5xx", method: GET, responses: []int{503, 500}, expCode: 200, expCount: 3},
+		{name: "retries exhausted", method: PUT, responses: []int{503, 502, 504, 500}, expCode: 500, expCount: 4},
+		{name: "no retry of not implemented", method: GET, responses: []int{501}, expCode: 501, expCount: 1},
+		{name: "no retry of client error", method: DELETE, responses: []int{404}, expCode: 404, expCount: 1},
+		{name: "no retry of non idempotent 5xx", method: POST, responses: []int{503}, expCode: 503, expCount: 1},
+		{name: "retry of non idempotent 429", method: POST, responses: []int{429}, expCode: 200, expCount: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(tc.responses...)
+			defer server.Close()
+
+			client := NewClient(nil, "", WithRetryPolicy(testRetryPolicy()))
+			response, err := client.Send(Request{Method: tc.method, URL: server.URL, Body: []byte("body")})
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expCode, response.Code)
+			assert.Equal(t, tc.expCount, server.requestCount())
+		})
+	}
+
+	// no retries without a policy
+	server := newTestServer(503)
+	defer server.Close()
+	response, err := NewClient(nil, "").Send(Request{Method: GET, URL: server.URL})
+	assert.Nil(t, err)
+	assert.Equal(t, 503, response.Code)
+	assert.Equal(t, 1, server.requestCount())
+}
+
+func TestSendMetrics(t *testing.T) {
+	server := newTestServer(http.StatusServiceUnavailable)
+	defer server.Close()
+
+	client := NewClient(nil, "", WithRetryPolicy(testRetryPolicy()))
+	response, err := client.Send(Request{Method: GET, URL: server.URL})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// each attempt is recorded
+	host := getHost(server.URL)
+	for _, code := range []string{"503", "200"} {
+		counter, err := metrics.GetOrRegisterCounter("agent_api_requests", "", metrics.Labels{"method": GET, "host": host, "code": code})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), counter.Count())
+	}
+	histogram, err := metrics.GetOrRegisterHistogram("agent_api_request_duration_seconds", "", nil, metrics.Labels{"method": GET, "host": host})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), histogram.Snapshot().Count)
+}
+
+func TestSendWithContext(t *testing.T) {
+	server := newTestServer(429, 429)
+	defer server.Close()
+	server.header = http.Header{"Retry-After": []string{"1"}}
+
+	policy := testRetryPolicy()
+	policy.MaxBackoff = time.Minute
+	client := NewClient(nil, "", WithRetryPolicy(policy))
+
+	// the Retry-After delay is honored, the context ends the retries
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := client.SendWithContext(ctx, Request{Method: GET, URL: server.URL})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 1, server.requestCount())
+
+	// a canceled context does not send the request
+	_, err = client.SendWithContext(ctx, Request{Method: GET, URL: server.URL})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, server.requestCount())
+
+	// the requests sent with Send are canceled with the context of the client
+	client = NewClient(nil, "", WithContext(ctx))
+	_, err = client.Send(Request{Method: GET, URL: server.URL})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, server.requestCount())
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	server := newTestServer(500, 500, 500)
+	defer server.Close()
+
+	openDuration := 50 * time.Millisecond
+	client := NewClient(nil, "", WithCircuitBreaker(2, openDuration))
+	request := Request{Method: GET, URL: server.URL}
+
+	for i := 0; i < 2; i++ {
+		response, err := client.Send(request)
+		assert.Nil(t, err)
+		assert.Equal(t, 500, response.Code)
+	}
+
+	// open, the request fails fast
+	_, err := client.Send(request)
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, server.requestCount())
+
+	// a failed trial request reopens the circuit
+	time.Sleep(openDuration)
+	response, err := client.Send(request)
+	assert.Nil(t, err)
+	assert.Equal(t, 500, response.Code)
+	_, err = client.Send(request)
+	assert.NotNil(t, err)
+
+	// a successful trial request closes the circuit
+	time.Sleep(openDuration)
+	for i := 0; i < 3; i++ {
+		response, err = client.Send(request)
+		assert.Nil(t, err)
+		assert.Equal(t, 200, response.Code)
+	}
+	assert.Equal(t, 6, server.requestCount())
+}
+
+func TestCircuitBreakerCountsRequests(t *testing.T) {
+	// two requests failing on all their attempts, then the server recovers
+	server := newTestServer(503, 503, 503, 503, 503, 503, 503, 503)
+	defer server.Close()
+
+	client := NewClient(nil, "", WithRetryPolicy(testRetryPolicy()), WithCircuitBreaker(3, time.Minute))
+	request := Request{Method: GET, URL: server.URL}
+	for i := 0; i < 2; i++ {
+		response, err := client.Send(request)
+		assert.Nil(t, err)
+		assert.Equal(t, 503, response.Code)
+	}
+	assert.Equal(t, 8, server.requestCount())
+
+	// the retries of the two transient failures do not open the circuit
+	response, err := client.Send(request)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, response.Code)
+
+	// the 4xx responses are not failures
+	server = newTestServer(404, 404, 404, 404)
+	defer server.Close()
+	client = NewClient(nil, "", WithCircuitBreaker(2, time.Minute))
+	for i := 0; i < 4; i++ {
+		response, err = client.Send(Request{Method: GET, URL: server.URL})
+		assert.Nil(t, err)
+		assert.Equal(t, 404, response.Code)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt := 0; attempt < 5; attempt++ {
+		delay := policy.backoff(attempt, nil)
+		assert.True(t, delay > 0)
+		assert.True(t, delay <= policy.MaxBackoff)
+	}
+	assert.True(t, policy.backoff(1, &Response{}) <= 200*time.Millisecond)
+
+	delay := policy.backoff(0, &Response{Headers: map[string][]string{"Retry-After": {"1"}}})
+	assert.Equal(t, time.Second, delay)
+	delay = policy.backoff(0, &Response{Headers: map[string][]string{"Retry-After": {"30"}}})
+	assert.Equal(t, time.Second, delay)
+	date := time.Now().Add(500 * time.Millisecond).UTC().Format(http.TimeFormat)
+	delay = policy.backoff(0, &Response{Headers: map[string][]string{"Retry-After": {date}}})
+	assert.True(t, delay <= 500*time.Millisecond)
+}
@@ -0,0 +1,8 @@
+package api
+
+import "github.com/Axway/agent-sdk/pkg/util/errors"
+
+// Errors hit when sending requests
+var (
+	ErrCircuitOpen = errors.Newf(1010, "request not sent, too many requests to %s failed. Check network configuration and the availability of the host")
+)
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -80,6 +81,11 @@ func (c *MockHTTPClient) Send(request Request) (*Response, error) {
 	return nil, nil
 }
 
+// SendWithContext -
+func (c *MockHTTPClient) SendWithContext(ctx context.Context, request Request) (*Response, error) {
+	return c.Send(request)
+}
+
 func (c *MockHTTPClient) sendMultiple(request Request) (*Response, error) {
 	responseFile, _ := os.Open(c.Responses[c.RespCount].FileName) // APIC Environments
 	dat, _ := ioutil.ReadAll(responseFile)
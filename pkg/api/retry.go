@@ -0,0 +1,97 @@
+package api
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+)
+
+// RetryPolicy - the policy for retrying failed requests, requests are not retried with the zero value.
+// Idempotent requests are retried on connection errors and 5xx responses, all requests are retried on 429 responses.
+type RetryPolicy struct {
+	MaxRetries     int           // the number of retries after the first attempt
+	InitialBackoff time.Duration // the backoff before the first retry, doubled for each retry
+	MaxBackoff     time.Duration // the limit of the backoff, including the delay requested by a Retry-After header
+}
+
+// DefaultRetryPolicy - returns the retry policy used for the Amplify Central requests
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     defaultMaxRetries,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+	}
+}
+
+// isRetryable - returns true when the failed request may be sent again
+func isRetryable(method string, response *Response, err error) bool {
+	if response != nil && response.Code == http.StatusTooManyRequests {
+		return true
+	}
+	if !isIdempotent(method) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return response.Code >= http.StatusInternalServerError && response.Code != http.StatusNotImplemented
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// backoff - returns the delay before the retry, the delay requested by the server is honored
+// otherwise the exponential backoff of the attempt is jittered
+func (p RetryPolicy) backoff(attempt int, response *Response) time.Duration {
+	if response != nil {
+		if delay, ok := getRetryAfter(response.Headers); ok {
+			return p.limit(delay)
+		}
+	}
+
+	backoff := p.limit(p.InitialBackoff << uint(attempt))
+	if backoff <= 0 {
+		return 0
+	}
+	// full jitter, spreads the retries of the agents calling the same host
+	return time.Duration(rand.Int63n(int64(backoff))) + 1
+}
+
+func (p RetryPolicy) limit(delay time.Duration) time.Duration {
+	// a negative delay is an overflow of the exponential backoff
+	if p.MaxBackoff > 0 && (delay > p.MaxBackoff || delay < 0) {
+		return p.MaxBackoff
+	}
+	return delay
+}
+
+// getRetryAfter - parses the Retry-After header, as either a number of seconds or an http date
+func getRetryAfter(headers map[string][]string) (time.Duration, bool) {
+	value := http.Header(headers).Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
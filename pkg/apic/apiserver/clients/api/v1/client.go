@@ -76,6 +76,16 @@ func JWTAuth(tenantID, privKey, pubKey, password, url, aud, clientID string, tim
 	}
 }
 
+// TokenGetterAuth auth with token retrieved by an existing token getter
+func TokenGetterAuth(tenantID string, tokenGetter auth.PlatformTokenGetter) Options {
+	return func(c *ClientBase) {
+		c.auth = &jwtAuth{
+			tenantID:    tenantID,
+			tokenGetter: tokenGetter,
+		}
+	}
+}
+
 type Logger interface {
 	Log(kv ...interface{}) error
 }
@@ -202,6 +212,8 @@ func handleError(res *http.Response) error {
 		return NotFoundError{errors}
 	case 409:
 		return ConflictError{errors}
+	case 410:
+		return GoneError{errors}
 	case 500:
 		return InternalServerError{errors}
 	default:
@@ -87,6 +87,16 @@ func (nf BadRequestError) Error() string {
 	return fmt.Sprintf("bad request: %s", nf.Errors)
 }
 
+// GoneError -
+type GoneError struct {
+	Errors
+}
+
+// Error -
+func (nf GoneError) Error() string {
+	return fmt.Sprintf("gone: %s", nf.Errors)
+}
+
 // UnexpectedError -
 type UnexpectedError struct {
 	code int
@@ -0,0 +1,199 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	apiv1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+)
+
+// FakeWatchServer is a local stand-in for the api server watch endpoint.
+// Events handed to it are sequenced, retained and streamed to the connected watchers.
+// It implements EventHandler so it can be set as the handler of a fake client.
+type FakeWatchServer struct {
+	*httptest.Server
+	lock     *sync.Mutex
+	events   []*apiv1.Event
+	sequence int64
+	oldest   int64
+	watchers map[chan *apiv1.Event]struct{}
+}
+
+// NewFakeWatchServer starts a watch server, the client base url is the server URL
+func NewFakeWatchServer() *FakeWatchServer {
+	fws := &FakeWatchServer{
+		lock:     &sync.Mutex{},
+		events:   []*apiv1.Event{},
+		oldest:   1,
+		watchers: map[chan *apiv1.Event]struct{}{},
+	}
+	fws.Server = httptest.NewServer(http.HandlerFunc(fws.serveWatch))
+	return fws
+}
+
+// Handle - sequences the event and streams it to the watchers
+func (fws *FakeWatchServer) Handle(e *apiv1.Event) {
+	fws.lock.Lock()
+	defer fws.lock.Unlock()
+
+	fws.sequence++
+	sequenced := *e
+	sequenced.Sequence = fws.sequence
+	fws.events = append(fws.events, &sequenced)
+
+	for watcher := range fws.watchers {
+		select {
+		case watcher <- &sequenced:
+		default:
+			// slow watcher, end its stream so it resumes from its last sequence
+			close(watcher)
+			delete(fws.watchers, watcher)
+		}
+	}
+}
+
+// Skip - consumes sequences without emitting events, watchers will see a gap in the stream
+func (fws *FakeWatchServer) Skip(count int64) {
+	fws.lock.Lock()
+	defer fws.lock.Unlock()
+	fws.sequence += count
+}
+
+// Compact - drops the retained events up to and including the sequence,
+// watchers resuming from an older sequence get a 410 Gone response
+func (fws *FakeWatchServer) Compact(sequence int64) {
+	fws.lock.Lock()
+	defer fws.lock.Unlock()
+
+	retained := []*apiv1.Event{}
+	for _, e := range fws.events {
+		if e.Sequence > sequence {
+			retained = append(retained, e)
+		}
+	}
+	fws.events = retained
+	fws.oldest = sequence + 1
+}
+
+// Sequence - returns the sequence of the last event
+func (fws *FakeWatchServer) Sequence() int64 {
+	fws.lock.Lock()
+	defer fws.lock.Unlock()
+	return fws.sequence
+}
+
+// CloseWatchers - ends the streams of all connected watchers
+func (fws *FakeWatchServer) CloseWatchers() {
+	fws.lock.Lock()
+	defer fws.lock.Unlock()
+	for watcher := range fws.watchers {
+		close(watcher)
+		delete(fws.watchers, watcher)
+	}
+}
+
+func (fws *FakeWatchServer) serveWatch(w http.ResponseWriter, req *http.Request) {
+	if !strings.HasSuffix(req.URL.Path, watchPath) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	query := req.URL.Query()
+	sequence, _ := strconv.ParseInt(query.Get("sequence"), 10, 64)
+	filter := fakeWatchFilter{kinds: query["kind"], scope: query.Get("scope")}
+
+	fws.lock.Lock()
+	if sequence > 0 && sequence+1 < fws.oldest {
+		fws.lock.Unlock()
+		w.WriteHeader(http.StatusGone)
+		json.NewEncoder(w).Encode(apiv1.ErrorResponse{
+			Errors: []apiv1.Error{{Status: http.StatusGone, Detail: "sequence " + strconv.FormatInt(sequence, 10) + " is no longer available"}},
+		})
+		return
+	}
+
+	replay := []*apiv1.Event{}
+	if sequence > 0 {
+		for _, e := range fws.events {
+			if e.Sequence > sequence {
+				replay = append(replay, e)
+			}
+		}
+	}
+	watcher := make(chan *apiv1.Event, 100)
+	fws.watchers[watcher] = struct{}{}
+	fws.lock.Unlock()
+
+	defer func() {
+		fws.lock.Lock()
+		defer fws.lock.Unlock()
+		if _, ok := fws.watchers[watcher]; ok {
+			delete(fws.watchers, watcher)
+		}
+	}()
+
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	enc := json.NewEncoder(w)
+	for _, e := range replay {
+		enc.Encode(filter.apply(e))
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case e, ok := <-watcher:
+			if !ok {
+				return
+			}
+			enc.Encode(filter.apply(e))
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+type fakeWatchFilter struct {
+	kinds []string
+	scope string
+}
+
+// apply - replaces the events not matching the filter with bookmarks to keep the stream sequence contiguous
+func (f fakeWatchFilter) apply(e *apiv1.Event) *apiv1.Event {
+	if f.matches(e) {
+		return e
+	}
+	return &apiv1.Event{
+		Type:     apiv1.BookmarkEvent,
+		Sequence: e.Sequence,
+	}
+}
+
+func (f fakeWatchFilter) matches(e *apiv1.Event) bool {
+	if f.scope != "" && e.Payload.Scope.Name != f.scope && e.Payload.Name != f.scope {
+		return false
+	}
+	if len(f.kinds) == 0 {
+		return true
+	}
+	for _, kind := range f.kinds {
+		if kind == e.Payload.Group+"/"+e.Payload.Kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	apiv1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+)
+
+const watchPath = "/watch"
+
+// WatchOption -
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	sequence int64
+	kinds    []apiv1.GroupKind
+	scope    string
+}
+
+// WithSequence resumes the watch with the events following the given sequence
+func WithSequence(sequence int64) WatchOption {
+	return func(wo *watchOptions) {
+		wo.sequence = sequence
+	}
+}
+
+// WithKinds restricts the watch to the events of the given kinds
+func WithKinds(kinds ...apiv1.GroupKind) WatchOption {
+	return func(wo *watchOptions) {
+		wo.kinds = append(wo.kinds, kinds...)
+	}
+}
+
+// WithWatchScope restricts the watch to the events of resources within the named scope
+func WithWatchScope(scope string) WatchOption {
+	return func(wo *watchOptions) {
+		wo.scope = scope
+	}
+}
+
+// GapError - returned when the watch stream skipped events following the last handled sequence
+type GapError struct {
+	Expected int64
+	Received int64
+}
+
+// Error -
+func (ge GapError) Error() string {
+	return fmt.Sprintf("gap in watch stream: expected sequence %d, received %d", ge.Expected, ge.Received)
+}
+
+// Watch streams the api server events to the handler until the context is done or the server closes the stream.
+// The server sends bookmarks in place of the events filtered out by the options, so the stream sequence stays contiguous.
+// It returns the sequence of the last handled event, which can be used with WithSequence to resume the watch.
+// A GoneError is returned when the server can no longer resume from the requested sequence and a GapError
+// when events are missing from the stream, in both cases the caller should resynchronize its state.
+func (cb *ClientBase) Watch(ctx context.Context, handler EventHandler, options ...WatchOption) (int64, error) {
+	opts := watchOptions{}
+	for _, o := range options {
+		o(&opts)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", cb.url+watchPath, nil)
+	if err != nil {
+		return opts.sequence, err
+	}
+
+	err = cb.intercept(req)
+	if err != nil {
+		return opts.sequence, err
+	}
+
+	q := req.URL.Query()
+	if opts.sequence > 0 {
+		q.Add("sequence", strconv.FormatInt(opts.sequence, 10))
+	}
+	for _, kind := range opts.kinds {
+		q.Add("kind", kind.Group+"/"+kind.Kind)
+	}
+	if opts.scope != "" {
+		q.Add("scope", opts.scope)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	res, err := cb.client.Do(req)
+	if err != nil {
+		return opts.sequence, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return opts.sequence, handleError(res)
+	}
+
+	return readEvents(ctx, res, handler, opts.sequence)
+}
+
+// readEvents decodes the newline delimited events from the response and hands them to the handler in order
+func readEvents(ctx context.Context, res *http.Response, handler EventHandler, sequence int64) (int64, error) {
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			// keep alive
+			continue
+		}
+
+		event := &apiv1.Event{}
+		err := json.Unmarshal(line, event)
+		if err != nil {
+			return sequence, err
+		}
+
+		// events already handled are replayed when the server resumes from an older sequence
+		if event.Sequence <= sequence {
+			continue
+		}
+		if sequence > 0 && event.Sequence != sequence+1 {
+			return sequence, GapError{Expected: sequence + 1, Received: event.Sequence}
+		}
+
+		if event.Type != apiv1.BookmarkEvent {
+			handler.Handle(event)
+		}
+		sequence = event.Sequence
+	}
+
+	if ctx.Err() != nil {
+		return sequence, ctx.Err()
+	}
+	return sequence, scanner.Err()
+}
@@ -0,0 +1,158 @@
+package v1_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/clients/api/v1"
+	apiv1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
+	management "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingHandler struct {
+	lock   sync.Mutex
+	events []*apiv1.Event
+}
+
+func (rh *recordingHandler) Handle(e *apiv1.Event) {
+	rh.lock.Lock()
+	defer rh.lock.Unlock()
+	rh.events = append(rh.events, e)
+}
+
+func (rh *recordingHandler) count() int {
+	rh.lock.Lock()
+	defer rh.lock.Unlock()
+	return len(rh.events)
+}
+
+func (rh *recordingHandler) last() *apiv1.Event {
+	rh.lock.Lock()
+	defer rh.lock.Unlock()
+	return rh.events[len(rh.events)-1]
+}
+
+func serviceEvent(eType apiv1.EventType, name string) *apiv1.Event {
+	return &apiv1.Event{
+		Type: eType,
+		Payload: apiv1.EventPayload{
+			GroupKind: management.APIServiceGVK().GroupKind,
+			Scope:     apiv1.MetadataScope{Name: "env"},
+			Name:      name,
+		},
+	}
+}
+
+func startWatch(cb *v1.ClientBase, handler v1.EventHandler, opts ...v1.WatchOption) (context.CancelFunc, chan error, *int64) {
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	sequence := new(int64)
+	go func() {
+		seq, err := cb.Watch(ctx, handler, opts...)
+		*sequence = seq
+		errCh <- err
+	}()
+	return cancel, errCh, sequence
+}
+
+func TestWatch(t *testing.T) {
+	server := v1.NewFakeWatchServer()
+	defer server.Close()
+
+	fc, err := v1.NewFakeClient(&apiv1.ResourceInstance{
+		ResourceMeta: apiv1.ResourceMeta{
+			GroupVersionKind: management.EnvironmentGVK(),
+			Name:             "env",
+		},
+		Spec: map[string]interface{}{},
+	})
+	assert.Nil(t, err)
+	fc.SetHandler(server)
+
+	cb := v1.NewClient(server.URL)
+	handler := &recordingHandler{}
+	cancel, errCh, sequence := startWatch(cb, handler,
+		v1.WithKinds(management.APIServiceGVK().GroupKind),
+		v1.WithWatchScope("env"),
+	)
+
+	// give the watch time to connect, events emitted before are not replayed without a sequence
+	time.Sleep(100 * time.Millisecond)
+
+	svcClient, err := fc.ForKind(management.APIServiceGVK())
+	assert.Nil(t, err)
+	_, err = svcClient.WithScope("env").Create(&apiv1.ResourceInstance{
+		ResourceMeta: apiv1.ResourceMeta{
+			GroupVersionKind: management.APIServiceGVK(),
+			Name:             "svc1",
+		},
+		Spec: map[string]interface{}{},
+	})
+	assert.Nil(t, err)
+	// the fake client hands the events to its handler asynchronously
+	assert.Eventually(t, func() bool { return handler.count() == 1 }, time.Second, 10*time.Millisecond)
+
+	// filtered out, delivered as a bookmark
+	server.Handle(&apiv1.Event{
+		Type: apiv1.ResourceEntryCreatedEvent,
+		Payload: apiv1.EventPayload{
+			GroupKind: management.SecretGVK().GroupKind,
+			Scope:     apiv1.MetadataScope{Name: "env"},
+			Name:      "secret",
+		},
+	})
+	server.Handle(serviceEvent(apiv1.ResourceEntryUpdatedEvent, "svc1"))
+
+	assert.Eventually(t, func() bool { return handler.count() == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, apiv1.ResourceEntryCreatedEvent, handler.events[0].Type)
+	assert.Equal(t, "svc1", handler.events[0].Payload.Name)
+	assert.Equal(t, apiv1.ResourceEntryUpdatedEvent, handler.last().Type)
+
+	cancel()
+	err = <-errCh
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, server.Sequence(), *sequence)
+
+	// resume from the returned sequence, missed events are replayed
+	server.Handle(serviceEvent(apiv1.ResourceEntryDeletedEvent, "svc1"))
+	cancel, errCh, _ = startWatch(cb, handler, v1.WithSequence(*sequence))
+	assert.Eventually(t, func() bool { return handler.count() == 3 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, apiv1.ResourceEntryDeletedEvent, handler.last().Type)
+
+	// a gap in the stream ends the watch
+	server.Skip(1)
+	server.Handle(serviceEvent(apiv1.ResourceEntryCreatedEvent, "svc2"))
+	err = <-errCh
+	assert.IsType(t, v1.GapError{}, err)
+	assert.Equal(t, 3, handler.count())
+	cancel()
+}
+
+func TestWatchGone(t *testing.T) {
+	server := v1.NewFakeWatchServer()
+	defer server.Close()
+
+	server.Handle(serviceEvent(apiv1.ResourceEntryCreatedEvent, "svc1"))
+	server.Handle(serviceEvent(apiv1.ResourceEntryCreatedEvent, "svc2"))
+	server.Handle(serviceEvent(apiv1.ResourceEntryCreatedEvent, "svc3"))
+	server.Compact(2)
+
+	cb := v1.NewClient(server.URL)
+	handler := &recordingHandler{}
+	seq, err := cb.Watch(context.Background(), handler, v1.WithSequence(1))
+	assert.IsType(t, v1.GoneError{}, err)
+	assert.Equal(t, int64(1), seq)
+	assert.Equal(t, 0, handler.count())
+
+	cancel, errCh, _ := startWatch(cb, handler, v1.WithSequence(2))
+	assert.Eventually(t, func() bool { return handler.count() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "svc3", handler.last().Payload.Name)
+
+	// the server ending the stream returns the last sequence without error
+	server.CloseWatchers()
+	assert.Nil(t, <-errCh)
+	cancel()
+}
@@ -97,13 +97,17 @@ const (
 	ResourceEntryDeletedEvent EventType = "ResourceEntryDeletedEvent"
 	// ResourceEntryUpdatedEvent -
 	ResourceEntryUpdatedEvent EventType = "ResourceEntryUpdatedEvent"
+	// BookmarkEvent - marks a sequence in the watch stream that carries no event for the watcher
+	BookmarkEvent EventType = "BookmarkEvent"
 )
 
 // Event is an API Server event concerning a resource
 type Event struct {
-	ID      string
-	Type    EventType
-	Payload EventPayload
+	ID   string    `json:"id"`
+	Type EventType `json:"type"`
+	// Sequence of the event in the api server event stream, used to resume watching after the last handled event.
+	Sequence int64        `json:"sequence,omitempty"`
+	Payload  EventPayload `json:"payload"`
 }
 
 // Finalizer Finalizer on the API server resource.
@@ -54,43 +54,50 @@ func (c *ServiceClient) updateAPIServiceResource(apiSvc *v1alpha1.APIService, se
 	}
 }
 
-//processService -
-func (c *ServiceClient) processService(serviceBody *ServiceBody) (*v1alpha1.APIService, error) {
+//prepareAPIService - looks up the service, to update it when it exists or create it
+func (c *ServiceClient) prepareAPIService(serviceBody *ServiceBody, stage *publishStage) error {
 	uuid, _ := uuid.NewUUID()
 	serviceName := uuid.String()
 
 	// Default action to create service
-	serviceURL := c.cfg.GetServicesURL()
-	httpMethod := http.MethodPost
+	stage.url = c.cfg.GetServicesURL()
+	stage.method = http.MethodPost
 	serviceBody.serviceContext.serviceAction = addAPI
 
 	// If service exists, update existing service
 	apiService, err := c.getAPIServiceByExternalAPIID(serviceBody)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if apiService != nil {
 		serviceName = apiService.Name
 		serviceBody.serviceContext.serviceAction = updateAPI
-		httpMethod = http.MethodPut
-		serviceURL += "/" + serviceName
+		stage.method = http.MethodPut
+		stage.url += "/" + serviceName
+		apiService.ResourceMeta.Metadata.ResourceVersion = ""
+		stage.previous, err = json.Marshal(apiService)
+		if err != nil {
+			return err
+		}
 		c.updateAPIServiceResource(apiService, serviceBody)
 	} else {
 		apiService = c.buildAPIServiceResource(serviceBody, serviceName)
+		stage.deleteURL = c.cfg.DeleteServicesURL() + "/" + serviceName
 	}
 
 	// spec needs to adhere to environment schema
 
-	buffer, err := json.Marshal(apiService)
+	stage.buffer, err = json.Marshal(apiService)
 	if err != nil {
-		return nil, err
-	}
-	_, err = c.apiServiceDeployAPI(httpMethod, serviceURL, buffer)
-	if err == nil {
-		serviceBody.serviceContext.serviceName = serviceName
+		return err
 	}
-	return apiService, err
+	serviceBody.serviceContext.serviceName = serviceName
+	stage.setAction(serviceName, serviceBody.serviceContext.serviceAction, apiService)
+
+	// Update description title after building the APIService to include the stage name if it exists
+	c.postAPIServiceUpdate(serviceBody)
+	return nil
 }
 
 // deleteService
@@ -158,8 +165,3 @@ func (c *ServiceClient) getAPIServiceByAttribute(externalAPIID, primaryKey strin
 	}
 	return nil, nil
 }
-
-// rollbackAPIService - if the process to add api/revision/instance fails, delete the api that was created
-func (c *ServiceClient) rollbackAPIService(serviceBody ServiceBody, name string) (string, error) {
-	return c.apiServiceDeployAPI(http.MethodDelete, c.cfg.DeleteServicesURL()+"/"+name, nil)
-}
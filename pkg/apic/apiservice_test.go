@@ -317,11 +317,11 @@ func TestRevision(t *testing.T) {
 	cloneServiceBody := serviceBody
 	//Alt Revision
 	cloneServiceBody.AltRevisionPrefix = "1.1.1"
-	client.processRevision(&cloneServiceBody)
+	client.prepareRevision(&cloneServiceBody, &publishStage{step: &PublishStep{}})
 	assert.Contains(t, cloneServiceBody.serviceContext.currentRevision, "1.1.1")
 	// Normal Revision
 	cloneServiceBody.AltRevisionPrefix = ""
-	client.processRevision(&cloneServiceBody)
+	client.prepareRevision(&cloneServiceBody, &publishStage{step: &PublishStep{}})
 	assert.NotEqual(t, "", cloneServiceBody.serviceContext.currentRevision)
 }
 func TestDeleteConsumerInstance(t *testing.T) {
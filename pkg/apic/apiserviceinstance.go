@@ -2,7 +2,6 @@ package apic
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -44,23 +43,28 @@ func (c *ServiceClient) updateInstanceResource(instance *v1alpha1.APIServiceInst
 	instance.Spec = c.buildAPIServiceInstanceSpec(serviceBody, endpoints)
 }
 
-//processInstance -
-func (c *ServiceClient) processInstance(serviceBody *ServiceBody) error {
+//prepareInstance - looks up the instances of the previous revision, to update the instance with the same endpoints or create an instance
+func (c *ServiceClient) prepareInstance(serviceBody *ServiceBody, stage *publishStage) error {
 	endPoints, err := c.processEndPoints(serviceBody)
 	if err != nil {
 		return err
 	}
 
-	httpMethod := http.MethodPost
-	instanceURL := c.cfg.GetInstancesURL()
+	stage.method = http.MethodPost
+	stage.url = c.cfg.GetInstancesURL()
 	instancePrefix := c.getRevisionPrefix(serviceBody)
 	instanceName := instancePrefix + "." + strconv.Itoa(serviceBody.serviceContext.instanceCount+1)
 	apiInstance := serviceBody.serviceContext.previousInstance
 
 	if serviceBody.serviceContext.instanceAction == updateAPI {
 		instanceName = serviceBody.serviceContext.previousInstance.Name
-		httpMethod = http.MethodPut
-		instanceURL += "/" + instanceName
+		stage.method = http.MethodPut
+		stage.url += "/" + instanceName
+		apiInstance.ResourceMeta.Metadata.ResourceVersion = ""
+		stage.previous, err = json.Marshal(apiInstance)
+		if err != nil {
+			return err
+		}
 		c.updateInstanceResource(apiInstance, serviceBody, endPoints)
 	} else {
 		instanceAttributes := make(map[string]string)
@@ -68,27 +72,18 @@ func (c *ServiceClient) processInstance(serviceBody *ServiceBody) error {
 			instanceAttributes[AttrPreviousAPIServiceInstanceID] = serviceBody.serviceContext.previousInstance.Metadata.ID
 		}
 		apiInstance = c.buildAPIServiceInstanceResource(serviceBody, instanceName, instanceAttributes, endPoints)
+		stage.deleteURL = c.cfg.GetInstancesURL() + "/" + instanceName
 	}
 
-	buffer, err := json.Marshal(apiInstance)
-	if err != nil {
-		return err
-	}
-
-	_, err = c.apiServiceDeployAPI(httpMethod, instanceURL, buffer)
+	stage.buffer, err = json.Marshal(apiInstance)
 	if err != nil {
-		if serviceBody.serviceContext.serviceAction == addAPI {
-			_, rollbackErr := c.rollbackAPIService(*serviceBody, serviceBody.serviceContext.serviceName)
-			if rollbackErr != nil {
-				return errors.New(err.Error() + rollbackErr.Error())
-			}
-		}
 		return err
 	}
 
 	serviceBody.serviceContext.currentInstance = instanceName
+	stage.setAction(instanceName, serviceBody.serviceContext.instanceAction, apiInstance)
 
-	return err
+	return nil
 }
 
 func (c *ServiceClient) processEndPoints(serviceBody *ServiceBody) ([]v1alpha1.ApiServiceInstanceSpecEndpoint, error) {
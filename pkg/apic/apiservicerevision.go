@@ -3,7 +3,6 @@ package apic
 import (
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -46,15 +45,15 @@ func (c *ServiceClient) updateRevisionResource(revision *v1alpha1.APIServiceRevi
 	revision.Spec = c.buildAPIServiceRevisionSpec(serviceBody)
 }
 
-//processRevision -
-func (c *ServiceClient) processRevision(serviceBody *ServiceBody) error {
+//prepareRevision - looks up the revisions of the service, to update the latest on minor changes or create a revision
+func (c *ServiceClient) prepareRevision(serviceBody *ServiceBody, stage *publishStage) error {
 	err := c.setRevisionAction(serviceBody)
 	if err != nil {
 		return err
 	}
 
-	httpMethod := http.MethodPost
-	revisionURL := c.cfg.GetRevisionsURL()
+	stage.method = http.MethodPost
+	stage.url = c.cfg.GetRevisionsURL()
 	var revAttributes map[string]string
 
 	var revisionName string
@@ -68,8 +67,13 @@ func (c *ServiceClient) processRevision(serviceBody *ServiceBody) error {
 
 	if serviceBody.serviceContext.revisionAction == updateAPI {
 		revisionName = serviceBody.serviceContext.previousRevision.Name
-		httpMethod = http.MethodPut
-		revisionURL += "/" + revisionName
+		stage.method = http.MethodPut
+		stage.url += "/" + revisionName
+		revision.ResourceMeta.Metadata.ResourceVersion = ""
+		stage.previous, err = json.Marshal(revision)
+		if err != nil {
+			return err
+		}
 		c.updateRevisionResource(revision, serviceBody)
 	} else {
 		revAttributes = make(map[string]string)
@@ -77,25 +81,16 @@ func (c *ServiceClient) processRevision(serviceBody *ServiceBody) error {
 			revAttributes[AttrPreviousAPIServiceRevisionID] = serviceBody.serviceContext.previousRevision.Metadata.ID
 		}
 		revision = c.buildAPIServiceRevisionResource(serviceBody, revAttributes, revisionName)
+		stage.deleteURL = c.cfg.GetRevisionsURL() + "/" + revisionName
 	}
 
-	buffer, err := json.Marshal(revision)
+	stage.buffer, err = json.Marshal(revision)
 	if err != nil {
 		return err
 	}
 
-	_, err = c.apiServiceDeployAPI(httpMethod, revisionURL, buffer)
-	if err != nil {
-		if serviceBody.serviceContext.serviceAction == addAPI {
-			_, rollbackErr := c.rollbackAPIService(*serviceBody, serviceBody.serviceContext.serviceName)
-			if rollbackErr != nil {
-				return errors.New(err.Error() + rollbackErr.Error())
-			}
-		}
-		return err
-	}
-
 	serviceBody.serviceContext.currentRevision = revisionName
+	stage.setAction(revisionName, serviceBody.serviceContext.revisionAction, revision)
 
 	return nil
 }
@@ -100,21 +100,34 @@ func NewPlatformTokenGetter(privKey, publicKey, password, url, aud, clientID str
 	}
 }
 
-// NewPlatformTokenGetterWithCentralConfig returns a token getter for axway ID
+// NewPlatformTokenGetterWithCentralConfig returns a token getter for axway ID, for the authentication mode of the
+// central config
 func NewPlatformTokenGetterWithCentralConfig(centralCfg config.CentralConfig) PlatformTokenGetter {
+	authCfg := centralCfg.GetAuthConfig()
+	generator := &platformTokenGenerator{
+		url:       authCfg.GetTokenURL(),
+		timeout:   authCfg.GetTimeout(),
+		tlsConfig: centralCfg.GetTLSConfig(),
+		proxyURL:  centralCfg.GetProxyURL(),
+	}
+
+	switch authCfg.GetAuthType() {
+	case config.AuthTypeClientSecret:
+		return newClientSecretTokenGetter(authCfg.GetClientID(), authCfg.GetClientSecret(), generator)
+	case config.AuthTypeMTLS:
+		return newMTLSTokenGetter(authCfg.GetClientID(), authCfg.GetClientCertificate(), authCfg.GetClientKey(), generator)
+	case config.AuthTypeTokenFile:
+		return newFileTokenGetter(authCfg.GetTokenFile())
+	}
+
 	return &platformTokenGetter{
-		centralCfg.GetAuthConfig().GetAudience(),
-		centralCfg.GetAuthConfig().GetClientID(),
-		&platformTokenGenerator{
-			url:       centralCfg.GetAuthConfig().GetTokenURL(),
-			timeout:   centralCfg.GetAuthConfig().GetTimeout(),
-			tlsConfig: centralCfg.GetTLSConfig(),
-			proxyURL:  centralCfg.GetProxyURL(),
-		},
+		authCfg.GetAudience(),
+		authCfg.GetClientID(),
+		generator,
 		&keyReader{
-			privKey:   centralCfg.GetAuthConfig().GetPrivateKey(),
-			publicKey: centralCfg.GetAuthConfig().GetPublicKey(),
-			password:  centralCfg.GetAuthConfig().GetKeyPassword(),
+			privKey:   authCfg.GetPrivateKey(),
+			publicKey: authCfg.GetPublicKey(),
+			password:  authCfg.GetKeyPassword(),
 		},
 		&tokenHolder{},
 	}
@@ -309,14 +322,18 @@ func (ptg *platformTokenGenerator) getHTTPClient() http.Client {
 }
 
 func (ptg *platformTokenGenerator) getPlatformTokens(requestToken string) (*axwayTokenResponse, error) {
-	startTime := time.Now()
-	client := ptg.getHTTPClient()
 	log.Debugf("token to be used: %s", requestToken)
-	resp, err := client.PostForm(ptg.url, url.Values{
+	return ptg.postTokenRequest(ptg.getHTTPClient(), url.Values{
 		"grant_type":            []string{"client_credentials"},
 		"client_assertion_type": []string{"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
 		"client_assertion":      []string{requestToken},
 	})
+}
+
+// postTokenRequest posts the token request form to the token url
+func (ptg *platformTokenGenerator) postTokenRequest(client http.Client, form url.Values) (*axwayTokenResponse, error) {
+	startTime := time.Now()
+	resp, err := client.PostForm(ptg.url, form)
 
 	duration := time.Now().Sub(startTime)
 	if err != nil {
@@ -351,6 +368,15 @@ type tokenHolder struct {
 	expiry *time.Timer
 }
 
+// newTokenHolder caches the tokens until they almost expire
+func newTokenHolder(tokens *axwayTokenResponse) *tokenHolder {
+	almostExpires := (tokens.ExpiresIn * 4) / 5
+	return &tokenHolder{
+		tokens,
+		time.NewTimer(time.Duration(almostExpires) * time.Second),
+	}
+}
+
 func (th *tokenHolder) getCachedToken() string {
 	if th.tokens != nil {
 		select {
@@ -382,10 +408,21 @@ func (ptp *platformTokenGetter) Close() error {
 // fetchNewToken fetches a new token from the platform and updates the token cache.
 func (ptp *platformTokenGetter) fetchNewToken() (string, error) {
 	log.Trace("Get cached token is empty.  Try and fetch a new token")
-	privateKey, err := ptp.getPrivateKey()
+	tokens, err := ptp.fetchTokens()
 	if err != nil {
 		return "", err
 	}
+
+	ptp.tokenHolder = newTokenHolder(tokens)
+	return tokens.AccessToken, nil
+}
+
+// fetchTokens fetches new tokens from the platform, with the client assertion signed with the private key
+func (ptp *platformTokenGetter) fetchTokens() (*axwayTokenResponse, error) {
+	privateKey, err := ptp.getPrivateKey()
+	if err != nil {
+		return nil, err
+	}
 	// cleanup memory used by decoded privatekey in a (futile) attempt to prevent heartbleed like attaks
 	defer func() {
 		for i := range privateKey.Primes {
@@ -399,32 +436,20 @@ func (ptp *platformTokenGetter) fetchNewToken() (string, error) {
 
 	publicKey, err := ptp.getPublicKey()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	kid, err := computeKIDFromDER(publicKey)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	requestToken, err := prepareInitialToken(privateKey, kid, ptp.clientID, ptp.aud)
 	if err != nil {
-		return "", err
-	}
-
-	tokens, err := ptp.getPlatformTokens(requestToken)
-	if err != nil {
-		return "", err
-	}
-
-	almostExpires := (tokens.ExpiresIn * 4) / 5
-
-	ptp.tokenHolder = &tokenHolder{
-		tokens,
-		time.NewTimer(time.Duration(almostExpires) * time.Second),
+		return nil, err
 	}
 
-	return tokens.AccessToken, nil
+	return ptp.getPlatformTokens(requestToken)
 }
 
 // GetToken returns a token from cache if not expired or fetches a new token
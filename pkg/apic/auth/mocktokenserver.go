@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// MockTokenServer - a local token server for the tests of the authentication modes, it accepts the client assertion
+// of the keypair mode, the client secret and the client certificate of the mtls mode
+type MockTokenServer struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+	ExpiresIn    int64
+	lock         sync.Mutex
+	requests     int
+}
+
+// NewMockTokenServer - starts a token server accepting the client assertions and the client secret
+func NewMockTokenServer(clientID, clientSecret string) *MockTokenServer {
+	m := &MockTokenServer{ClientID: clientID, ClientSecret: clientSecret, ExpiresIn: 300}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.handle))
+	return m
+}
+
+// NewMockTLSTokenServer - starts a TLS token server requiring the client certificates signed by the clientCAs
+func NewMockTLSTokenServer(clientID string, clientCAs *x509.CertPool) *MockTokenServer {
+	m := &MockTokenServer{ClientID: clientID, ExpiresIn: 300}
+	m.Server = httptest.NewUnstartedServer(http.HandlerFunc(m.handle))
+	m.Server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	m.Server.StartTLS()
+	return m
+}
+
+// GetRequestCount - returns the number of tokens issued
+func (m *MockTokenServer) GetRequestCount() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.requests
+}
+
+func (m *MockTokenServer) authenticated(r *http.Request) bool {
+	switch {
+	case r.PostForm.Get("client_assertion") != "":
+		return r.PostForm.Get("client_assertion_type") == "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	case r.PostForm.Get("client_secret") != "":
+		return r.PostForm.Get("client_id") == m.ClientID && r.PostForm.Get("client_secret") == m.ClientSecret
+	case r.TLS != nil && len(r.TLS.PeerCertificates) > 0:
+		return r.PostForm.Get("client_id") == m.ClientID
+	}
+	return false
+}
+
+func (m *MockTokenServer) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if !m.authenticated(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	m.lock.Lock()
+	m.requests++
+	tokens := axwayTokenResponse{
+		AccessToken: fmt.Sprintf("token-%d", m.requests),
+		ExpiresIn:   m.ExpiresIn,
+	}
+	m.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tokens)
+}
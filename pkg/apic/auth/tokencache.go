@@ -0,0 +1,252 @@
+package auth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+const (
+	// DefaultRefreshPercentage - the percentage of the token lifetime after which the token is renewed
+	DefaultRefreshPercentage = 80
+
+	minRefreshBackoff = time.Second
+	maxRefreshBackoff = time.Minute
+)
+
+// tokenFetcher fetches new tokens, with their lifetime, from the token endpoint
+type tokenFetcher interface {
+	fetchTokens() (*axwayTokenResponse, error)
+}
+
+// getterFetcher fetches the token of a token getter that does not report the token lifetime, the token is kept until
+// the cache is closed
+type getterFetcher struct {
+	TokenGetter
+}
+
+func (g getterFetcher) fetchTokens() (*axwayTokenResponse, error) {
+	token, err := g.GetToken()
+	if err != nil {
+		return nil, err
+	}
+	return &axwayTokenResponse{AccessToken: token}, nil
+}
+
+// TokenChangeHandler - Callback for the renewal of the token, e.g. for the long running connections sending the token
+type TokenChangeHandler func(token string)
+
+// TokenStats - the metrics of the token cache
+type TokenStats struct {
+	Valid               bool          `json:"valid"`
+	Age                 time.Duration `json:"age"`
+	ExpiresIn           time.Duration `json:"expiresIn"`
+	Refreshes           int           `json:"refreshes"`
+	Failures            int           `json:"failures"`
+	ConsecutiveFailures int           `json:"consecutiveFailures"`
+	LastError           string        `json:"lastError,omitempty"`
+}
+
+// TokenCache - a token getter shared by the agent components, the token is renewed in the background before it
+// expires, the concurrent requests for a new token share the same token request
+type TokenCache interface {
+	PlatformTokenGetter
+	// OnTokenChange - registers a handler called with the new token, after each renewal
+	OnTokenChange(handler TokenChangeHandler)
+	// GetStats - returns the age of the token and the count of renewals and failures
+	GetStats() TokenStats
+}
+
+// tokenCall is a token request shared by the concurrent callers
+type tokenCall struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+type tokenCache struct {
+	lock              sync.Mutex
+	fetcher           tokenFetcher
+	closer            tokenGetterCloser
+	refreshPercentage int
+	token             string
+	fetchedAt         time.Time
+	expiresAt         time.Time
+	call              *tokenCall
+	timer             *time.Timer
+	retryAt           time.Time
+	lastErr           error
+	stats             TokenStats
+	handlers          []TokenChangeHandler
+	closed            bool
+}
+
+// NewTokenCache - creates the token cache of the token getter, the token is renewed after refreshPercentage of its
+// lifetime
+func NewTokenCache(getter PlatformTokenGetter, refreshPercentage int) TokenCache {
+	if refreshPercentage <= 0 || refreshPercentage >= 100 {
+		refreshPercentage = DefaultRefreshPercentage
+	}
+	fetcher, ok := getter.(tokenFetcher)
+	if !ok {
+		fetcher = getterFetcher{getter}
+	}
+	return &tokenCache{
+		fetcher:           fetcher,
+		closer:            getter,
+		refreshPercentage: refreshPercentage,
+	}
+}
+
+// valid returns true when the token has not expired, it is renewed before then, the margin covers the clock skew
+func (c *tokenCache) valid(now time.Time) bool {
+	if c.token == "" {
+		return false
+	}
+	if c.expiresAt.IsZero() {
+		return true
+	}
+	margin := c.expiresAt.Sub(c.fetchedAt) / 10
+	if margin > 30*time.Second {
+		margin = 30 * time.Second
+	}
+	return now.Before(c.expiresAt.Add(-margin))
+}
+
+// GetToken returns the cached token, or fetches a new token when it expired
+func (c *tokenCache) GetToken() (string, error) {
+	c.lock.Lock()
+	now := time.Now()
+	if c.valid(now) {
+		defer c.lock.Unlock()
+		return c.token, nil
+	}
+	if c.lastErr != nil && now.Before(c.retryAt) {
+		// backoff, do not call the token endpoint again until the retry time
+		defer c.lock.Unlock()
+		return "", c.lastErr
+	}
+	c.lock.Unlock()
+	return c.fetch()
+}
+
+// fetch fetches a new token, the concurrent callers wait for the same token request
+func (c *tokenCache) fetch() (string, error) {
+	c.lock.Lock()
+	if call := c.call; call != nil {
+		c.lock.Unlock()
+		<-call.done
+		return call.token, call.err
+	}
+	call := &tokenCall{done: make(chan struct{})}
+	c.call = call
+	c.lock.Unlock()
+
+	tokens, err := c.fetcher.fetchTokens()
+
+	c.lock.Lock()
+	c.call = nil
+	changed := false
+	var handlers []TokenChangeHandler
+	if err != nil {
+		c.stats.Failures++
+		c.stats.ConsecutiveFailures++
+		c.stats.LastError = err.Error()
+		c.lastErr = err
+		backoff := c.backoff()
+		c.retryAt = time.Now().Add(backoff)
+		log.Warnf("could not renew the token, retry in %s: %s", backoff, err.Error())
+		call.err = err
+		if c.valid(time.Now()) {
+			// the current token is still valid, retry the renewal in the background
+			c.schedule(backoff)
+			call.token, call.err = c.token, nil
+		}
+	} else {
+		changed = tokens.AccessToken != c.token
+		c.token = tokens.AccessToken
+		c.fetchedAt = time.Now()
+		c.expiresAt = time.Time{}
+		if tokens.ExpiresIn > 0 {
+			lifetime := time.Duration(tokens.ExpiresIn) * time.Second
+			c.expiresAt = c.fetchedAt.Add(lifetime)
+			c.schedule(lifetime * time.Duration(c.refreshPercentage) / 100)
+		}
+		c.stats.Refreshes++
+		c.stats.ConsecutiveFailures = 0
+		c.stats.LastError = ""
+		c.lastErr = nil
+		handlers = append(handlers, c.handlers...)
+		call.token = c.token
+	}
+	c.lock.Unlock()
+	close(call.done)
+
+	if changed {
+		for _, handler := range handlers {
+			handler(call.token)
+		}
+	}
+	return call.token, call.err
+}
+
+// backoff returns the wait before the next token request, doubled on each consecutive failure
+func (c *tokenCache) backoff() time.Duration {
+	backoff := minRefreshBackoff
+	for i := 1; i < c.stats.ConsecutiveFailures && backoff < maxRefreshBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRefreshBackoff {
+		backoff = maxRefreshBackoff
+	}
+	return backoff
+}
+
+// schedule renews the token in the background after the wait, the lock is held by the caller
+func (c *tokenCache) schedule(wait time.Duration) {
+	if c.closed {
+		return
+	}
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.timer = time.AfterFunc(wait, func() {
+		log.Trace("renewing the token before it expires")
+		c.fetch()
+	})
+}
+
+// OnTokenChange registers a handler called with the new token
+func (c *tokenCache) OnTokenChange(handler TokenChangeHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.handlers = append(c.handlers, handler)
+}
+
+// GetStats returns the metrics of the token cache
+func (c *tokenCache) GetStats() TokenStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	stats := c.stats
+	stats.Valid = c.valid(now)
+	if c.token != "" {
+		stats.Age = now.Sub(c.fetchedAt)
+		if !c.expiresAt.IsZero() {
+			stats.ExpiresIn = c.expiresAt.Sub(now)
+		}
+	}
+	return stats
+}
+
+// Close stops the background renewal of the token
+func (c *tokenCache) Close() error {
+	c.lock.Lock()
+	c.closed = true
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.lock.Unlock()
+	return c.closer.Close()
+}
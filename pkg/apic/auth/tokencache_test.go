@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testFetcher struct {
+	lock      sync.Mutex
+	calls     int
+	expiresIn int64
+	delay     time.Duration
+	err       error
+}
+
+func (f *testFetcher) fetchTokens() (*axwayTokenResponse, error) {
+	time.Sleep(f.delay)
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &axwayTokenResponse{AccessToken: fmt.Sprintf("token-%d", f.calls), ExpiresIn: f.expiresIn}, nil
+}
+
+func (f *testFetcher) getCalls() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.calls
+}
+
+func (f *testFetcher) GetToken() (string, error) {
+	return "", nil
+}
+
+func (f *testFetcher) Close() error {
+	return nil
+}
+
+func TestTokenCacheSingleFlight(t *testing.T) {
+	fetcher := &testFetcher{expiresIn: 300, delay: 50 * time.Millisecond}
+	tc := NewTokenCache(fetcher, 0)
+	defer tc.Close()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := tc.GetToken()
+			assert.Nil(t, err)
+			assert.Equal(t, "token-1", token)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, fetcher.getCalls())
+
+	stats := tc.GetStats()
+	assert.True(t, stats.Valid)
+	assert.Equal(t, 1, stats.Refreshes)
+	assert.Equal(t, 0, stats.Failures)
+	assert.True(t, stats.ExpiresIn > 299*time.Second)
+}
+
+func TestTokenCacheProactiveRefresh(t *testing.T) {
+	fetcher := &testFetcher{expiresIn: 1}
+	tc := NewTokenCache(fetcher, 20)
+	defer tc.Close()
+
+	changes := make(chan string, 10)
+	tc.OnTokenChange(func(token string) {
+		changes <- token
+	})
+
+	token, err := tc.GetToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token)
+	assert.Equal(t, "token-1", <-changes)
+
+	// renewed in the background after 20% of the lifetime
+	select {
+	case token = <-changes:
+		assert.Equal(t, "token-2", token)
+	case <-time.After(time.Second):
+		t.Error("expected the token to be renewed")
+	}
+	token, _ = tc.GetToken()
+	assert.Equal(t, "token-2", token)
+
+	// stopped on close
+	tc.Close()
+	calls := fetcher.getCalls()
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(t, calls, fetcher.getCalls())
+}
+
+func TestTokenCacheBackoff(t *testing.T) {
+	fetcher := &testFetcher{expiresIn: 300, err: fmt.Errorf("token endpoint down")}
+	tc := NewTokenCache(fetcher, 0)
+	defer tc.Close()
+
+	_, err := tc.GetToken()
+	assert.NotNil(t, err)
+
+	// the token endpoint is not called again before the backoff
+	_, err = tc.GetToken()
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, fetcher.getCalls())
+
+	stats := tc.GetStats()
+	assert.False(t, stats.Valid)
+	assert.Equal(t, 1, stats.Failures)
+	assert.Equal(t, "token endpoint down", stats.LastError)
+
+	cache := tc.(*tokenCache)
+	cache.stats.ConsecutiveFailures = 3
+	assert.Equal(t, 4*time.Second, cache.backoff())
+	cache.stats.ConsecutiveFailures = 20
+	assert.Equal(t, maxRefreshBackoff, cache.backoff())
+	cache.stats.ConsecutiveFailures = 1
+
+	// recovered after the backoff
+	fetcher.lock.Lock()
+	fetcher.err = nil
+	fetcher.lock.Unlock()
+	time.Sleep(minRefreshBackoff)
+	token, err := tc.GetToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "token-2", token)
+	stats = tc.GetStats()
+	assert.True(t, stats.Valid)
+	assert.Equal(t, 0, stats.ConsecutiveFailures)
+	assert.Equal(t, "", stats.LastError)
+}
+
+func TestTokenCacheStaticGetter(t *testing.T) {
+	tc := NewTokenCache(staticTokenGetter("static"), 0)
+	defer tc.Close()
+
+	token, err := tc.GetToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "static", token)
+	assert.True(t, tc.GetStats().Valid)
+}
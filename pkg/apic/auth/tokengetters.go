@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/util/log"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+const fileTokenCheckInterval = time.Minute
+
+// clientCredentialsTokenGetter gets the tokens with the client_credentials grant, the client is authenticated with
+// its client secret, or with the client certificate of the TLS connection
+type clientCredentialsTokenGetter struct {
+	lock         sync.Mutex
+	clientID     string
+	clientSecret string // client secret, for the client_secret authentication
+	clientCert   string // path to the client certificate, for the mtls authentication
+	clientKey    string // path to the client key, for the mtls authentication
+	*platformTokenGenerator
+	*tokenHolder
+}
+
+// newClientSecretTokenGetter returns a token getter authenticated with the client secret
+func newClientSecretTokenGetter(clientID, clientSecret string, generator *platformTokenGenerator) *clientCredentialsTokenGetter {
+	return &clientCredentialsTokenGetter{
+		clientID:               clientID,
+		clientSecret:           clientSecret,
+		platformTokenGenerator: generator,
+		tokenHolder:            &tokenHolder{},
+	}
+}
+
+// newMTLSTokenGetter returns a token getter authenticated with the client certificate, the certificate files are read
+// on each token request so that a renewed certificate is used
+func newMTLSTokenGetter(clientID, clientCert, clientKey string, generator *platformTokenGenerator) *clientCredentialsTokenGetter {
+	return &clientCredentialsTokenGetter{
+		clientID:               clientID,
+		clientCert:             clientCert,
+		clientKey:              clientKey,
+		platformTokenGenerator: generator,
+		tokenHolder:            &tokenHolder{},
+	}
+}
+
+// getClient returns the http client, with the client certificate for the mtls authentication
+func (ctg *clientCredentialsTokenGetter) getClient() (http.Client, error) {
+	client := ctg.getHTTPClient()
+	if ctg.clientCert == "" {
+		return client, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(ctg.clientCert, ctg.clientKey)
+	if err != nil {
+		return client, fmt.Errorf("[apicauth] could not load the client certificate: %s", err.Error())
+	}
+	transport := client.Transport.(*http.Transport)
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	return client, nil
+}
+
+// Close a clientCredentialsTokenGetter
+func (ctg *clientCredentialsTokenGetter) Close() error {
+	return nil
+}
+
+// GetToken returns a token from cache if not expired or fetches a new token
+func (ctg *clientCredentialsTokenGetter) GetToken() (string, error) {
+	ctg.lock.Lock()
+	defer ctg.lock.Unlock()
+	if token := ctg.getCachedToken(); token != "" {
+		return token, nil
+	}
+
+	log.Trace("Get cached token is empty.  Try and fetch a new token")
+	tokens, err := ctg.fetchTokens()
+	if err != nil {
+		return "", err
+	}
+	ctg.tokenHolder = newTokenHolder(tokens)
+	return tokens.AccessToken, nil
+}
+
+// fetchTokens fetches new tokens with the client_credentials grant
+func (ctg *clientCredentialsTokenGetter) fetchTokens() (*axwayTokenResponse, error) {
+	client, err := ctg.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type": []string{"client_credentials"},
+		"client_id":  []string{ctg.clientID},
+	}
+	if ctg.clientSecret != "" {
+		form.Set("client_secret", ctg.clientSecret)
+	}
+	return ctg.postTokenRequest(client, form)
+}
+
+// fileTokenGetter returns the token of a file supplied by the platform, e.g. a projected service account token,
+// the file is read again when it changes
+type fileTokenGetter struct {
+	lock    sync.Mutex
+	path    string
+	modTime time.Time
+	token   string
+}
+
+// newFileTokenGetter returns a token getter reading the token file
+func newFileTokenGetter(path string) *fileTokenGetter {
+	return &fileTokenGetter{path: path}
+}
+
+// Close a fileTokenGetter
+func (ftg *fileTokenGetter) Close() error {
+	return nil
+}
+
+// GetToken returns the token of the file, read again when the file changed
+func (ftg *fileTokenGetter) GetToken() (string, error) {
+	ftg.lock.Lock()
+	defer ftg.lock.Unlock()
+	return ftg.readToken()
+}
+
+func (ftg *fileTokenGetter) readToken() (string, error) {
+	info, err := os.Stat(ftg.path)
+	if err != nil {
+		return "", err
+	}
+	if ftg.token != "" && info.ModTime().Equal(ftg.modTime) {
+		return ftg.token, nil
+	}
+
+	data, err := ioutil.ReadFile(ftg.path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("[apicauth] the token file %s is empty", ftg.path)
+	}
+	if ftg.token != "" {
+		log.Debugf("the token file %s changed, using the new token", ftg.path)
+	}
+	ftg.token = token
+	ftg.modTime = info.ModTime()
+	return ftg.token, nil
+}
+
+// fetchTokens returns the token of the file, its lifetime is a minute, so that the file changes are read, or less when
+// the exp claim of the JWT expires before
+func (ftg *fileTokenGetter) fetchTokens() (*axwayTokenResponse, error) {
+	ftg.lock.Lock()
+	defer ftg.lock.Unlock()
+	token, err := ftg.readToken()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := &axwayTokenResponse{AccessToken: token, ExpiresIn: int64(fileTokenCheckInterval.Seconds())}
+	claims := jwt.StandardClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, &claims); err == nil && claims.ExpiresAt > 0 {
+		if expiresIn := claims.ExpiresAt - time.Now().Unix(); expiresIn > 0 && expiresIn < tokens.ExpiresIn {
+			tokens.ExpiresIn = expiresIn
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCentralConfig(authCfg *config.AuthConfiguration) config.CentralConfig {
+	authCfg.Realm = "Broker"
+	authCfg.Timeout = time.Second
+	return &config.CentralConfiguration{
+		Auth: authCfg,
+		TLS:  &config.TLSConfiguration{InsecureSkipVerify: true},
+	}
+}
+
+// writeClientCertificate - writes a CA signed client certificate and its key, returns the CA pool
+func writeClientCertificate(t *testing.T, dir string) *x509.CertPool {
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.Nil(t, err)
+	caCert, _ := x509.ParseCertificate(caDER)
+
+	clientKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "agent"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caCert, &clientKey.PublicKey, caKey)
+	assert.Nil(t, err)
+	keyDER, _ := x509.MarshalECPrivateKey(clientKey)
+
+	ioutil.WriteFile(filepath.Join(dir, "client.crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER}), 0600)
+	ioutil.WriteFile(filepath.Join(dir, "client.key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	return pool
+}
+
+func TestKeyPairTokenGetter(t *testing.T) {
+	s := NewMockTokenServer("serviceaccount", "")
+	defer s.Close()
+
+	tg := NewPlatformTokenGetterWithCentralConfig(newTestCentralConfig(&config.AuthConfiguration{
+		URL:        s.URL,
+		ClientID:   "serviceaccount",
+		PrivateKey: "testdata/private_key.pem",
+		PublicKey:  "testdata/public_key",
+	}))
+	assert.IsType(t, &platformTokenGetter{}, tg)
+
+	token, err := tg.GetToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token)
+}
+
+func TestClientSecretTokenGetter(t *testing.T) {
+	s := NewMockTokenServer("serviceaccount", "secret")
+	defer s.Close()
+
+	authCfg := &config.AuthConfiguration{
+		Type:         config.AuthTypeClientSecret,
+		URL:          s.URL,
+		ClientID:     "serviceaccount",
+		ClientSecret: "secret",
+	}
+	tg := NewPlatformTokenGetterWithCentralConfig(newTestCentralConfig(authCfg))
+
+	token, err := tg.GetToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token)
+
+	// cached
+	token, err = tg.GetToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token)
+	assert.Equal(t, 1, s.GetRequestCount())
+
+	// expired
+	s.ExpiresIn = 0
+	tg = NewPlatformTokenGetterWithCentralConfig(newTestCentralConfig(authCfg))
+	tg.GetToken()
+	time.Sleep(time.Millisecond)
+	token, err = tg.GetToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "token-3", token)
+
+	// bad secret
+	authCfg.ClientSecret = "bad"
+	tg = NewPlatformTokenGetterWithCentralConfig(newTestCentralConfig(authCfg))
+	_, err = tg.GetToken()
+	assert.NotNil(t, err)
+}
+
+func TestMTLSTokenGetter(t *testing.T) {
+	dir := t.TempDir()
+	s := NewMockTLSTokenServer("serviceaccount", writeClientCertificate(t, dir))
+	defer s.Close()
+
+	authCfg := &config.AuthConfiguration{
+		Type:       config.AuthTypeMTLS,
+		URL:        s.URL,
+		ClientID:   "serviceaccount",
+		ClientCert: filepath.Join(dir, "client.crt"),
+		ClientKey:  filepath.Join(dir, "client.key"),
+	}
+	tg := NewPlatformTokenGetterWithCentralConfig(newTestCentralConfig(authCfg))
+
+	token, err := tg.GetToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token)
+
+	// no client certificate
+	authCfg.ClientCert = filepath.Join(dir, "missing.crt")
+	tg = NewPlatformTokenGetterWithCentralConfig(newTestCentralConfig(authCfg))
+	_, err = tg.GetToken()
+	assert.NotNil(t, err)
+
+	tg = newClientSecretTokenGetter("serviceaccount", "", &platformTokenGenerator{url: s.URL, timeout: time.Second})
+	_, err = tg.GetToken()
+	assert.NotNil(t, err)
+}
+
+func TestFileTokenGetter(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	tg := NewPlatformTokenGetterWithCentralConfig(newTestCentralConfig(&config.AuthConfiguration{
+		Type:      config.AuthTypeTokenFile,
+		TokenFile: tokenFile,
+	}))
+
+	// no file
+	_, err := tg.GetToken()
+	assert.NotNil(t, err)
+
+	// empty file
+	ioutil.WriteFile(tokenFile, []byte(""), 0600)
+	_, err = tg.GetToken()
+	assert.NotNil(t, err)
+
+	ioutil.WriteFile(tokenFile, []byte("first\n"), 0600)
+	token, err := tg.GetToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "first", token)
+
+	// refreshed on change
+	ioutil.WriteFile(tokenFile, []byte("second\n"), 0600)
+	os.Chtimes(tokenFile, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	token, err = tg.GetToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "second", token)
+}
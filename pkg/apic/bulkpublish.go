@@ -0,0 +1,202 @@
+package apic
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
+	hc "github.com/Axway/agent-sdk/pkg/util/healthcheck"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+const (
+	defaultPublishConcurrency = 5
+	bulkPublishEndpoint       = "bulkpublish"
+)
+
+// PublishServicesOptions - the options for publishing services in bulk
+type PublishServicesOptions struct {
+	Concurrency int                            // the number of services published in parallel, defaults to 5
+	Rate        float64                        // the maximum services published per second for the tenant, 0 for no limit
+	Progress    func(progress PublishProgress) // called each time a service was published or failed
+}
+
+// PublishProgress - the progress of a bulk publish
+type PublishProgress struct {
+	Total     int `json:"total"`
+	Published int `json:"published"`
+	Failed    int `json:"failed"`
+}
+
+// Done - returns true when all services were published or failed
+func (p PublishProgress) Done() bool {
+	return p.Published+p.Failed >= p.Total
+}
+
+func (p *PublishProgress) add(err error) {
+	if err != nil {
+		p.Failed++
+	} else {
+		p.Published++
+	}
+}
+
+// PublishServiceResult - the outcome of publishing one service of a bulk publish
+type PublishServiceResult struct {
+	ID         string
+	APIName    string
+	APIService *v1alpha1.APIService // the published service, nil when the publish failed
+	Err        error
+}
+
+// bulkPublishStatus - the progress of the bulk publishes, reported through the healthcheck
+type bulkPublishStatus struct {
+	lock     sync.Mutex
+	progress PublishProgress
+	once     sync.Once
+}
+
+var publishStatus = &bulkPublishStatus{}
+
+func (s *bulkPublishStatus) start(total int) {
+	s.once.Do(func() {
+		_, err := hc.RegisterHealthcheck("Bulk Publish", bulkPublishEndpoint, s.healthcheck)
+		if err != nil {
+			log.Debugf("could not register the bulk publish healthcheck: %s", err.Error())
+		}
+	})
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	// add to the progress of a publish that is still running
+	if s.progress.Done() {
+		s.progress = PublishProgress{}
+	}
+	s.progress.Total += total
+}
+
+func (s *bulkPublishStatus) update(err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.progress.add(err)
+}
+
+func (s *bulkPublishStatus) healthcheck(name string) *hc.Status {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return &hc.Status{
+		Result:  hc.OK,
+		Details: fmt.Sprintf("%s: %d of %d services published, %d failed", name, s.progress.Published, s.progress.Total, s.progress.Failed),
+	}
+}
+
+// rateLimiter - spaces the publishes of a tenant evenly
+type rateLimiter struct {
+	lock     sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+var (
+	tenantLimitersLock sync.Mutex
+	tenantLimiters     = make(map[string]*rateLimiter)
+)
+
+// getTenantLimiter - returns the limiter shared by the bulk publishes of the tenant
+func getTenantLimiter(tenantID string, rate float64) *rateLimiter {
+	tenantLimitersLock.Lock()
+	defer tenantLimitersLock.Unlock()
+	limiter, ok := tenantLimiters[tenantID]
+	if !ok {
+		limiter = &rateLimiter{}
+		tenantLimiters[tenantID] = limiter
+	}
+	limiter.lock.Lock()
+	limiter.interval = time.Duration(float64(time.Second) / rate)
+	limiter.lock.Unlock()
+	return limiter
+}
+
+// wait - blocks until the next publish is allowed
+func (l *rateLimiter) wait() {
+	l.lock.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.lock.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// PublishServices - publishes the service bodies with a pool of workers, returns a result for each service body
+// in the order of the service bodies.  The progress is reported through the healthcheck status.
+func (c *ServiceClient) PublishServices(serviceBodies []ServiceBody, opts PublishServicesOptions) []PublishServiceResult {
+	results := make([]PublishServiceResult, len(serviceBodies))
+	if len(serviceBodies) == 0 {
+		return results
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultPublishConcurrency
+	}
+	if concurrency > len(serviceBodies) {
+		concurrency = len(serviceBodies)
+	}
+
+	var limiter *rateLimiter
+	if opts.Rate > 0 {
+		limiter = getTenantLimiter(c.cfg.GetTenantID(), opts.Rate)
+	}
+
+	publishStatus.start(len(serviceBodies))
+	progressLock := sync.Mutex{}
+	progress := PublishProgress{Total: len(serviceBodies)}
+	indexes := make(chan int)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				if limiter != nil {
+					limiter.wait()
+				}
+				results[index] = c.publishServiceResult(serviceBodies[index])
+
+				publishStatus.update(results[index].Err)
+				progressLock.Lock()
+				progress.add(results[index].Err)
+				if opts.Progress != nil {
+					opts.Progress(progress)
+				}
+				progressLock.Unlock()
+			}
+		}()
+	}
+
+	for i := range serviceBodies {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+func (c *ServiceClient) publishServiceResult(serviceBody ServiceBody) PublishServiceResult {
+	result := PublishServiceResult{
+		ID:      serviceBody.RestAPIID,
+		APIName: serviceBody.APIName,
+	}
+	result.APIService, result.Err = c.PublishService(serviceBody)
+	if result.Err != nil {
+		log.Errorf("error publishing %s: %s", serviceBody.APIName, result.Err.Error())
+	}
+	return result
+}
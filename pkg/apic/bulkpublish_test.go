@@ -0,0 +1,102 @@
+package apic
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/api"
+	corecfg "github.com/Axway/agent-sdk/pkg/config"
+	hc "github.com/Axway/agent-sdk/pkg/util/healthcheck"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishServices(t *testing.T) {
+	var lock sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	s := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		lock.Unlock()
+		defer func() {
+			lock.Lock()
+			inFlight--
+			lock.Unlock()
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		switch req.Method {
+		case http.MethodGet:
+			resp.WriteHeader(http.StatusNotFound)
+		case http.MethodPost:
+			body, _ := ioutil.ReadAll(req.Body)
+			if strings.Contains(string(body), `"title":"fail"`) {
+				resp.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			resp.WriteHeader(http.StatusCreated)
+			resp.Write(body)
+		default:
+			resp.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer s.Close()
+
+	client, _ := GetTestServiceClient()
+	client.apiClient = api.NewClient(nil, "")
+	cfg := GetTestServiceClientCentralConfiguration(client)
+	cfg.URL = s.URL
+	cfg.Mode = corecfg.PublishToEnvironment
+
+	serviceBodies := []ServiceBody{}
+	for i := 0; i < 10; i++ {
+		body := serviceBody
+		body.RestAPIID = fmt.Sprintf("id%d", i)
+		body.APIName = fmt.Sprintf("api%d", i)
+		body.NameToPush = body.APIName
+		if i == 3 {
+			body.NameToPush = "fail"
+		}
+		serviceBodies = append(serviceBodies, body)
+	}
+
+	progress := []PublishProgress{}
+	results := client.PublishServices(serviceBodies, PublishServicesOptions{
+		Concurrency: 4,
+		Progress: func(p PublishProgress) {
+			progress = append(progress, p)
+		},
+	})
+	assert.Len(t, results, 10)
+	for i, result := range results {
+		assert.Equal(t, serviceBodies[i].RestAPIID, result.ID)
+		if i == 3 {
+			assert.NotNil(t, result.Err)
+			assert.Nil(t, result.APIService)
+			continue
+		}
+		assert.Nil(t, result.Err)
+		assert.NotNil(t, result.APIService)
+	}
+	assert.Len(t, progress, 10)
+	assert.Equal(t, PublishProgress{Total: 10, Published: 9, Failed: 1}, progress[9])
+	assert.True(t, progress[9].Done())
+	assert.True(t, maxInFlight > 1, "services should be published in parallel")
+	assert.True(t, maxInFlight <= 4, "no more than 4 services should be published in parallel")
+	assert.Equal(t, hc.OK, hc.RunChecks())
+	assert.Contains(t, publishStatus.healthcheck("Bulk Publish").Details, "9 of 10 services published, 1 failed")
+
+	// the publishes of the tenant are rate limited
+	start := time.Now()
+	results = client.PublishServices(serviceBodies[:5], PublishServicesOptions{Concurrency: 5, Rate: 50})
+	assert.Len(t, results, 5)
+	assert.True(t, time.Since(start) >= 80*time.Millisecond, "5 publishes at 50 per second take at least 80ms")
+}
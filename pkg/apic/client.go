@@ -1,11 +1,13 @@
 package apic
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	coreapi "github.com/Axway/agent-sdk/pkg/api"
 	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
@@ -26,6 +28,13 @@ const (
 
 const serverName = "AMPLIFY Central"
 
+// requests to AMPLIFY Central fail fast for a while after consecutive failed requests, each counted once after its
+// retries
+const (
+	circuitFailureThreshold = 10
+	circuitOpenDuration     = 30 * time.Second
+)
+
 // ValidPolicies - list of valid auth policies supported by Central.  Add to this list as more policies are supported.
 var ValidPolicies = []string{Apikey, Passthrough, Oauth}
 
@@ -39,6 +48,8 @@ type SubscriptionValidator func(subscription Subscription) bool
 type Client interface {
 	SetTokenGetter(tokenRequester auth.PlatformTokenGetter)
 	PublishService(serviceBody ServiceBody) (*v1alpha1.APIService, error)
+	PlanPublishService(serviceBody ServiceBody) (*PublishPlan, error)
+	PublishServices(serviceBodies []ServiceBody, opts PublishServicesOptions) []PublishServiceResult
 	RegisterSubscriptionWebhook() error
 	RegisterSubscriptionSchema(subscriptionSchema SubscriptionSchema, update bool) error
 	UpdateSubscriptionSchema(subscriptionSchema SubscriptionSchema) error
@@ -59,12 +70,14 @@ type Client interface {
 	OnConfigChange(cfg corecfg.CentralConfig)
 	Healthcheck(name string) *hc.Status
 	GetAPIRevisions(queryParams map[string]string, stage string) ([]v1alpha1.APIServiceRevision, error)
+	SetContext(ctx context.Context)
+	Shutdown(ctx context.Context) error
 }
 
 // New -
 func New(cfg corecfg.CentralConfig, tokenRequester auth.PlatformTokenGetter) Client {
 
-	serviceClient := &ServiceClient{}
+	serviceClient := &ServiceClient{publishes: newPublishTracker()}
 	serviceClient.SetTokenGetter(tokenRequester)
 
 	serviceClient.OnConfigChange(cfg)
@@ -75,7 +88,7 @@ func New(cfg corecfg.CentralConfig, tokenRequester auth.PlatformTokenGetter) Cli
 // OnConfigChange - config change handler
 func (c *ServiceClient) OnConfigChange(cfg corecfg.CentralConfig) {
 	c.cfg = cfg
-	c.apiClient = coreapi.NewClientWithTimeout(cfg.GetTLSConfig(), cfg.GetProxyURL(), cfg.GetClientTimeout())
+	c.apiClient = c.newAPIClient(cfg)
 	c.DefaultSubscriptionSchema = NewSubscriptionSchema(cfg.GetEnvironmentName() + SubscriptionSchemaNameSuffix)
 
 	// set the default webhook if one has been configured
@@ -93,6 +106,15 @@ func (c *ServiceClient) OnConfigChange(cfg corecfg.CentralConfig) {
 	}
 }
 
+// newAPIClient - creates the http client of the requests to AMPLIFY Central
+func (c *ServiceClient) newAPIClient(cfg corecfg.CentralConfig) coreapi.Client {
+	return coreapi.NewClientWithTimeout(cfg.GetTLSConfig(), cfg.GetProxyURL(), cfg.GetClientTimeout(),
+		coreapi.WithRetryPolicy(coreapi.DefaultRetryPolicy()),
+		coreapi.WithCircuitBreaker(circuitFailureThreshold, circuitOpenDuration),
+		coreapi.WithContext(c.getContext()),
+	)
+}
+
 // SetTokenGetter - sets the token getter
 func (c *ServiceClient) SetTokenGetter(tokenRequester auth.PlatformTokenGetter) {
 	c.tokenRequester = tokenRequester
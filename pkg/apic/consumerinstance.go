@@ -130,8 +130,8 @@ func (c *ServiceClient) updateConsumerInstanceResource(consumerInstance *v1alpha
 	consumerInstance.Spec = c.buildConsumerInstanceSpec(serviceBody, doc)
 }
 
-//processConsumerInstance - deal with either a create or update of a consumerInstance
-func (c *ServiceClient) processConsumerInstance(serviceBody *ServiceBody) error {
+//prepareConsumerInstance - looks up the consumer instance of an updated service, to update it when it exists or create it
+func (c *ServiceClient) prepareConsumerInstance(serviceBody *ServiceBody, stage *publishStage) error {
 
 	// Allow catalog asset to be created.  However, set to pass-through so subscriptions aren't enabled
 	if !isValidAuthPolicy(serviceBody.AuthPolicy) {
@@ -154,8 +154,8 @@ func (c *ServiceClient) processConsumerInstance(serviceBody *ServiceBody) error
 		consumerInstanceName = sanitizeAPIName(fmt.Sprintf("%s-%s", serviceBody.serviceContext.serviceName, serviceBody.Stage))
 	}
 
-	httpMethod := http.MethodPost
-	consumerInstanceURL := c.cfg.GetConsumerInstancesURL()
+	stage.method = http.MethodPost
+	stage.url = c.cfg.GetConsumerInstancesURL()
 
 	var consumerInstance *v1alpha1.ConsumerInstance
 	var err error
@@ -166,33 +166,31 @@ func (c *ServiceClient) processConsumerInstance(serviceBody *ServiceBody) error
 		}
 	}
 
+	var action actionType = addAPI
 	if consumerInstance != nil {
-		httpMethod = http.MethodPut
-		consumerInstanceURL += "/" + consumerInstanceName
+		action = updateAPI
+		stage.method = http.MethodPut
+		stage.url += "/" + consumerInstanceName
+		consumerInstance.ResourceMeta.Metadata.ResourceVersion = ""
+		stage.previous, err = json.Marshal(consumerInstance)
+		if err != nil {
+			return err
+		}
 		c.updateConsumerInstanceResource(consumerInstance, serviceBody, doc)
 	} else {
 		consumerInstance = c.buildConsumerInstance(serviceBody, consumerInstanceName, doc)
+		stage.deleteURL = c.cfg.GetConsumerInstancesURL() + "/" + consumerInstanceName
 	}
 
-	buffer, err := json.Marshal(consumerInstance)
+	stage.buffer, err = json.Marshal(consumerInstance)
 	if err != nil {
 		return err
 	}
 
-	_, err = c.apiServiceDeployAPI(httpMethod, consumerInstanceURL, buffer)
-	if err != nil {
-		if serviceBody.serviceContext.serviceAction == addAPI {
-			_, rollbackErr := c.rollbackAPIService(*serviceBody, serviceBody.serviceContext.serviceName)
-			if rollbackErr != nil {
-				return errors.New(err.Error() + rollbackErr.Error())
-			}
-		}
-		return err
-	}
-
 	serviceBody.serviceContext.consumerInstance = consumerInstanceName
+	stage.setAction(consumerInstanceName, action, consumerInstance)
 
-	return err
+	return nil
 }
 
 // getAPIServerConsumerInstance -
@@ -1,6 +1,8 @@
 package apic
 
 import (
+	"context"
+
 	coreapi "github.com/Axway/agent-sdk/pkg/api"
 	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
 	"github.com/Axway/agent-sdk/pkg/apic/auth"
@@ -16,6 +18,7 @@ const (
 	Oas3          = "oas3"
 	Protobuf      = "protobuf"
 	AsyncAPI      = "asyncapi"
+	GraphQL       = "graphql"
 	Unstructured  = "unstructured"
 	Specification = "specification"
 	Swagger       = "swagger"
@@ -144,6 +147,8 @@ type ServiceClient struct {
 	RegisteredSubscriptionSchema       SubscriptionSchema
 	subscriptionMgr                    SubscriptionManager
 	DefaultSubscriptionApprovalWebhook corecfg.WebhookConfig
+	ctx                                context.Context
+	publishes                          *publishTracker
 }
 
 // APIServerInfoProperty -
@@ -30,7 +30,18 @@ var (
 	ErrUpdateSubscriptionDefProperties    = errors.New(1156, "error updating subscription definition properties in AMPLIFY Central")
 	ErrGetCatalogItemServerInfoProperties = errors.New(1157, "error getting catalog item API server info properties")
 	ErrSubscriptionManagerDown            = errors.New(1158, "subscription manager is not running")
+	ErrPublishServiceRollback             = errors.Newf(1159, "error rolling back the publish of %s, resources may be left in AMPLIFY Central")
 
 	// Service body builer
 	ErrSetSpecEndPoints = errors.New(1160, "error getting endpoints for the API specification")	
+	ErrSpecTransform    = errors.Newf(1163, "error normalizing the API specification, %s step: %s")
+	ErrInvalidSpec      = errors.New(1164, "the normalized API specification is not valid")
+
+	// Subscription lifecycle
+	ErrSubscriptionStateFile  = errors.Newf(1165, "could not open the subscription state file %s, the subscription transitions are kept in memory")
+	ErrSubscriptionProcessing = errors.Newf(1166, "processing of the subscription failed after %d attempts: %s")
+
+	// Shutdown
+	ErrShuttingDown   = errors.New(1167, "the agent is shutting down, the API can not be published")
+	ErrPublishTimeout = errors.New(1168, "the in-flight publishes did not end before the shutdown deadline")
 )
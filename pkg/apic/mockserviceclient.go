@@ -54,6 +54,7 @@ func GetTestServiceClient() (*ServiceClient, *api.MockHTTPClient) {
 		apiClient:                          apiClient,
 		DefaultSubscriptionApprovalWebhook: webhook,
 		DefaultSubscriptionSchema:          NewSubscriptionSchema(cfg.GetEnvironmentName() + SubscriptionSchemaNameSuffix),
+		publishes:                          newPublishTracker(),
 	}
 	svcClient.subscriptionMgr = newSubscriptionManager(svcClient)
 	return svcClient, apiClient
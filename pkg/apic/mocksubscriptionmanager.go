@@ -3,10 +3,11 @@ package apic
 // MockSubscriptionManager - used for unit tests to bypass the normal SubscriptionManager
 type MockSubscriptionManager struct {
 	SubscriptionManager
-	RegisterProcessorCalled int
-	RegisterValidatorCalled int
-	StartCalled             int
-	StopCalled              int
+	RegisterProcessorCalled          int
+	RegisterLifecycleProcessorCalled int
+	RegisterValidatorCalled          int
+	StartCalled                      int
+	StopCalled                       int
 }
 
 // NewMockSubscriptionManager -
@@ -33,3 +34,8 @@ func (m *MockSubscriptionManager) Start() {
 func (m *MockSubscriptionManager) Stop() {
 	m.StopCalled++
 }
+
+// RegisterLifecycleProcessor -
+func (m *MockSubscriptionManager) RegisterLifecycleProcessor(state SubscriptionState, processor SubscriptionLifecycleProcessor) {
+	m.RegisterLifecycleProcessorCalled++
+}
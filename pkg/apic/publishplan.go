@@ -0,0 +1,239 @@
+package apic
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+// PublishAction - the action a publish step takes on its resource
+type PublishAction string
+
+// Publish actions
+const (
+	PublishActionCreate PublishAction = "create"
+	PublishActionUpdate PublishAction = "update"
+)
+
+// PublishStepStatus - the status of a publish step
+type PublishStepStatus string
+
+// Publish step statuses
+const (
+	PublishStepPlanned            PublishStepStatus = "planned"            // the step was not applied
+	PublishStepApplied            PublishStepStatus = "applied"            // the resource was created or updated
+	PublishStepFailed             PublishStepStatus = "failed"             // preparing or applying the step failed
+	PublishStepCompensated        PublishStepStatus = "compensated"        // the created resource was deleted or the previous resource restored
+	PublishStepCompensationFailed PublishStepStatus = "compensationFailed" // the applied step could not be compensated
+)
+
+// PublishStep - a step of the publish plan, creating or updating one resource
+type PublishStep struct {
+	Kind     string
+	Name     string
+	Action   PublishAction
+	Status   PublishStepStatus
+	Resource interface{} // the resource sent to AMPLIFY Central
+	Err      error
+}
+
+// PublishResult - the outcome of executing a publish plan
+type PublishResult struct {
+	APIService *v1alpha1.APIService // the published service, nil when the plan failed
+	Steps      []PublishStep
+	Err        error // the error of the failed step, nil when all steps were applied
+	RolledBack bool  // true when the plan failed and all applied steps were compensated
+}
+
+// PublishError - the error returned by PublishService when the publish plan failed
+type PublishError struct {
+	Result *PublishResult
+}
+
+// Error -
+func (e *PublishError) Error() string {
+	failed := ""
+	compensationErrs := []string{}
+	for _, step := range e.Result.Steps {
+		switch step.Status {
+		case PublishStepFailed:
+			failed = strings.TrimSpace(step.Kind + " " + step.Name)
+		case PublishStepCompensationFailed:
+			compensationErrs = append(compensationErrs, step.Err.Error())
+		}
+	}
+
+	msg := fmt.Sprintf("error publishing %s: %s", failed, e.Result.Err.Error())
+	if len(compensationErrs) > 0 {
+		msg += fmt.Sprintf(", rollback failed: %s", strings.Join(compensationErrs, ", "))
+	}
+	return msg
+}
+
+// Unwrap - returns the error of the failed step
+func (e *PublishError) Unwrap() error {
+	return e.Result.Err
+}
+
+// publishStage - a stage of the publish plan.  The stage is prepared by looking up the existing resource, which
+// decides the action, and building the resource to send.  It is then applied and, on failure of a later stage,
+// compensated by deleting the created resource or restoring the previous resource.
+type publishStage struct {
+	step      *PublishStep
+	prepare   func(serviceBody *ServiceBody, stage *publishStage) error
+	prepared  bool
+	method    string
+	url       string
+	buffer    []byte
+	deleteURL string // the url to delete the created resource
+	previous  []byte // the resource before the update
+}
+
+// setAction - sets the action of the stage from the action of the service context
+func (s *publishStage) setAction(name string, action actionType, resource interface{}) {
+	s.step.Name = name
+	s.step.Action = PublishActionCreate
+	if action == updateAPI {
+		s.step.Action = PublishActionUpdate
+	}
+	s.step.Resource = resource
+}
+
+// PublishPlan - the staged plan publishing a service body, creating or updating the APIService, revision,
+// instance and consumer instance in order.  A failed plan compensates the steps it applied.
+type PublishPlan struct {
+	client      *ServiceClient
+	serviceBody *ServiceBody
+	stages      []*publishStage
+	executed    bool
+}
+
+// newPublishPlan - creates the plan for the service body, the stages are prepared when executed
+func (c *ServiceClient) newPublishPlan(serviceBody *ServiceBody) *PublishPlan {
+	plan := &PublishPlan{
+		client:      c,
+		serviceBody: serviceBody,
+	}
+	plan.addStage(v1alpha1.APIServiceGVK().Kind, c.prepareAPIService)
+	plan.addStage(v1alpha1.APIServiceRevisionGVK().Kind, c.prepareRevision)
+	plan.addStage(v1alpha1.APIServiceInstanceGVK().Kind, c.prepareInstance)
+	if c.cfg.IsPublishToEnvironmentAndCatalogMode() {
+		plan.addStage(v1alpha1.ConsumerInstanceGVK().Kind, c.prepareConsumerInstance)
+	}
+	return plan
+}
+
+func (p *PublishPlan) addStage(kind string, prepare func(serviceBody *ServiceBody, stage *publishStage) error) {
+	p.stages = append(p.stages, &publishStage{
+		step:    &PublishStep{Kind: kind, Status: PublishStepPlanned},
+		prepare: prepare,
+	})
+}
+
+// PlanPublishService - computes the plan publishing the service body, without changing any resource.
+// The steps of the plan can be previewed before executing it.
+func (c *ServiceClient) PlanPublishService(serviceBody ServiceBody) (*PublishPlan, error) {
+	plan := c.newPublishPlan(&serviceBody)
+	for _, stage := range plan.stages {
+		if err := plan.prepareStage(stage); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// Steps - returns the steps of the plan
+func (p *PublishPlan) Steps() []PublishStep {
+	steps := make([]PublishStep, 0, len(p.stages))
+	for _, stage := range p.stages {
+		steps = append(steps, *stage.step)
+	}
+	return steps
+}
+
+func (p *PublishPlan) prepareStage(stage *publishStage) error {
+	if stage.prepared {
+		return nil
+	}
+	err := stage.prepare(p.serviceBody, stage)
+	if err != nil {
+		return err
+	}
+	stage.prepared = true
+	return nil
+}
+
+// Execute - applies the steps of the plan in order, a plan is executed once.  When a step fails the applied steps
+// are compensated in reverse order.
+func (p *PublishPlan) Execute() *PublishResult {
+	if p.executed {
+		return &PublishResult{Steps: p.Steps(), Err: fmt.Errorf("the plan publishing %s was already executed", p.serviceBody.APIName)}
+	}
+	p.executed = true
+
+	result := &PublishResult{}
+	applied := []*publishStage{}
+	for _, stage := range p.stages {
+		err := p.prepareStage(stage)
+		if err == nil {
+			_, err = p.client.apiServiceDeployAPI(stage.method, stage.url, stage.buffer)
+		}
+		if err != nil {
+			stage.step.Status = PublishStepFailed
+			stage.step.Err = err
+			result.Err = err
+			result.RolledBack = p.compensate(applied)
+			break
+		}
+		stage.step.Status = PublishStepApplied
+		applied = append(applied, stage)
+	}
+
+	result.Steps = p.Steps()
+	if result.Err == nil {
+		result.APIService, _ = p.stages[0].step.Resource.(*v1alpha1.APIService)
+	}
+	return result
+}
+
+// compensate - undoes the applied stages in reverse order, returns true when all were compensated
+func (p *PublishPlan) compensate(applied []*publishStage) bool {
+	if len(applied) == 0 {
+		return true
+	}
+
+	// the resources of a created service are removed with it
+	serviceCreated := applied[0].step.Action == PublishActionCreate
+	rolledBack := true
+	for i := len(applied) - 1; i >= 0; i-- {
+		stage := applied[i]
+		if serviceCreated && i > 0 {
+			stage.step.Status = PublishStepCompensated
+			continue
+		}
+
+		err := p.client.compensateStage(stage)
+		if err != nil {
+			log.Errorf("%s: %s", ErrPublishServiceRollback.FormatError(p.serviceBody.APIName), err.Error())
+			stage.step.Status = PublishStepCompensationFailed
+			stage.step.Err = err
+			rolledBack = false
+			continue
+		}
+		stage.step.Status = PublishStepCompensated
+	}
+	return rolledBack
+}
+
+// compensateStage - deletes the created resource or restores the previous resource
+func (c *ServiceClient) compensateStage(stage *publishStage) error {
+	if stage.step.Action == PublishActionCreate {
+		_, err := c.apiServiceDeployAPI(http.MethodDelete, stage.deleteURL, nil)
+		return err
+	}
+	_, err := c.apiServiceDeployAPI(http.MethodPut, stage.url, stage.previous)
+	return err
+}
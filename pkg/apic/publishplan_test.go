@@ -0,0 +1,130 @@
+package apic
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Axway/agent-sdk/pkg/api"
+	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanPublishService(t *testing.T) {
+	client, httpClient := GetTestServiceClient()
+	httpClient.SetResponses([]api.MockResponse{
+		{
+			RespCode: http.StatusNotFound, // for call to get the service
+		},
+	})
+
+	cloneServiceBody := serviceBody
+	cloneServiceBody.AuthPolicy = Passthrough
+	plan, err := client.PlanPublishService(cloneServiceBody)
+	assert.Nil(t, err)
+	assert.Len(t, httpClient.Requests, 1, "Planning should only look up the existing resources")
+
+	steps := plan.Steps()
+	assert.Len(t, steps, 4)
+	kinds := []string{v1alpha1.APIServiceGVK().Kind, v1alpha1.APIServiceRevisionGVK().Kind, v1alpha1.APIServiceInstanceGVK().Kind, v1alpha1.ConsumerInstanceGVK().Kind}
+	for i, step := range steps {
+		assert.Equal(t, kinds[i], step.Kind)
+		assert.Equal(t, PublishActionCreate, step.Action)
+		assert.Equal(t, PublishStepPlanned, step.Status)
+		assert.NotEmpty(t, step.Name)
+		assert.NotNil(t, step.Resource)
+	}
+	assert.Equal(t, steps[0].Name, steps[1].Resource.(*v1alpha1.APIServiceRevision).Spec.ApiService)
+	assert.Equal(t, steps[1].Name, steps[2].Resource.(*v1alpha1.APIServiceInstance).Spec.ApiServiceRevision)
+	assert.Equal(t, steps[2].Name, steps[3].Resource.(*v1alpha1.ConsumerInstance).Spec.ApiServiceInstance)
+
+	// execute the previewed plan
+	httpClient.SetResponses([]api.MockResponse{
+		{FileName: "./testdata/apiservice.json", RespCode: http.StatusCreated},
+		{FileName: "./testdata/servicerevision.json", RespCode: http.StatusCreated},
+		{FileName: "./testdata/serviceinstance.json", RespCode: http.StatusCreated},
+		{FileName: "./testdata/consumerinstance.json", RespCode: http.StatusCreated},
+	})
+	result := plan.Execute()
+	assert.Nil(t, result.Err)
+	assert.NotNil(t, result.APIService)
+	assert.Equal(t, steps[0].Name, result.APIService.Name)
+	for _, step := range result.Steps {
+		assert.Equal(t, PublishStepApplied, step.Status)
+	}
+
+	result = plan.Execute()
+	assert.NotNil(t, result.Err, "A plan should not be executed twice")
+}
+
+func TestPublishServiceRollback(t *testing.T) {
+	client, httpClient := GetTestServiceClient()
+
+	updateResponses := []api.MockResponse{
+		{FileName: "./testdata/apiservice-list.json", RespCode: http.StatusOK},             // get the service
+		{FileName: "./testdata/apiservice.json", RespCode: http.StatusOK},                  // update the service
+		{FileName: "./testdata/existingservicerevisions.json", RespCode: http.StatusOK},    // get the revisions
+		{FileName: "./testdata/servicerevision.json", RespCode: http.StatusOK},             // update the revision
+		{FileName: "./testdata/existingserviceinstances.json", RespCode: http.StatusOK},    // get the instances
+		{FileName: "./testdata/serviceinstance.json", RespCode: http.StatusRequestTimeout}, // create the instance
+	}
+
+	cloneServiceBody := serviceBody
+	cloneServiceBody.APIUpdateSeverity = MinorChange
+
+	// the updated revision and service are restored
+	httpClient.SetResponses(append(updateResponses,
+		api.MockResponse{RespCode: http.StatusOK}, // restore the revision
+		api.MockResponse{RespCode: http.StatusOK}, // restore the service
+	))
+	apiSvc, err := client.PublishService(cloneServiceBody)
+	assert.Nil(t, apiSvc)
+	assert.NotNil(t, err)
+
+	publishErr := &PublishError{}
+	assert.True(t, errors.As(err, &publishErr))
+	result := publishErr.Result
+	assert.True(t, result.RolledBack)
+	assert.Equal(t, result.Err, errors.Unwrap(err))
+	assert.Len(t, result.Steps, 4)
+	assert.Equal(t, PublishStepCompensated, result.Steps[0].Status)
+	assert.Equal(t, PublishActionUpdate, result.Steps[1].Action)
+	assert.Equal(t, PublishStepCompensated, result.Steps[1].Status)
+	assert.Equal(t, PublishStepFailed, result.Steps[2].Status)
+	assert.Equal(t, PublishStepPlanned, result.Steps[3].Status)
+
+	requests := httpClient.Requests[len(httpClient.Requests)-2:]
+	assert.Equal(t, http.MethodPut, requests[0].Method)
+	assert.Equal(t, client.cfg.GetRevisionsURL()+"/daleapi", requests[0].URL)
+	assert.Equal(t, http.MethodPut, requests[1].Method)
+	assert.Equal(t, client.cfg.GetServicesURL()+"/fooapi", requests[1].URL)
+
+	// a failed restore is reported
+	httpClient.Requests = nil
+	httpClient.SetResponses(append(updateResponses,
+		api.MockResponse{RespCode: http.StatusConflict}, // restore the revision
+		api.MockResponse{RespCode: http.StatusOK},       // restore the service
+	))
+	_, err = client.PublishService(cloneServiceBody)
+	assert.True(t, errors.As(err, &publishErr))
+	assert.False(t, publishErr.Result.RolledBack)
+	assert.Equal(t, PublishStepCompensationFailed, publishErr.Result.Steps[1].Status)
+	assert.Equal(t, PublishStepCompensated, publishErr.Result.Steps[0].Status)
+	assert.Contains(t, err.Error(), "rollback failed")
+
+	// only the created service is deleted, its resources are removed with it
+	httpClient.Requests = nil
+	httpClient.SetResponses([]api.MockResponse{
+		{RespCode: http.StatusNotFound},                                                    // get the service
+		{FileName: "./testdata/apiservice.json", RespCode: http.StatusCreated},             // create the service
+		{FileName: "./testdata/servicerevision.json", RespCode: http.StatusCreated},        // create the revision
+		{FileName: "./testdata/serviceinstance.json", RespCode: http.StatusRequestTimeout}, // create the instance
+		{RespCode: http.StatusNoContent},                                                   // delete the service
+	})
+	_, err = client.PublishService(serviceBody)
+	assert.True(t, errors.As(err, &publishErr))
+	assert.True(t, publishErr.Result.RolledBack)
+	last := httpClient.Requests[len(httpClient.Requests)-1]
+	assert.Equal(t, http.MethodDelete, last.Method)
+	assert.Equal(t, client.cfg.DeleteServicesURL()+"/"+publishErr.Result.Steps[0].Name, last.URL)
+}
@@ -25,29 +25,20 @@ const (
 	deleteAPI            = iota
 )
 
-// PublishService - processes the API to create/update apiservice, revision, instance and consumer instance
+// PublishService - processes the API to create/update apiservice, revision, instance and consumer instance.
+// When publishing fails the created resources are deleted and the updated resources restored, the returned
+// error is a PublishError holding the result of each step. Once the client is shut down the API is not published
 func (c *ServiceClient) PublishService(serviceBody ServiceBody) (*v1alpha1.APIService, error) {
-	apiSvc, err := c.processService(&serviceBody)
-	if err != nil {
-		return nil, err
-	}
-	// Update description title after creating APIService to inlcude the stage name if it exists
-	c.postAPIServiceUpdate(&serviceBody)
-	err = c.processRevision(&serviceBody)
-	if err != nil {
-		return nil, err
+	if !c.publishes.start() {
+		return nil, ErrShuttingDown
 	}
-	err = c.processInstance(&serviceBody)
-	if err != nil {
-		return nil, err
-	}
-	if c.cfg.IsPublishToEnvironmentAndCatalogMode() {
-		err = c.processConsumerInstance(&serviceBody)
-		if err != nil {
-			return nil, err
-		}
+	defer c.publishes.done()
+
+	result := c.newPublishPlan(&serviceBody).Execute()
+	if result.Err != nil {
+		return nil, &PublishError{Result: result}
 	}
-	return apiSvc, nil
+	return result.APIService, nil
 }
 
 // DeleteServiceByAPIID -
@@ -36,6 +36,7 @@ type ServiceBuilder interface {
 	SetServiceAttribute(serviceAttribute map[string]string) ServiceBuilder
 	SetServiceEndpoints(endpoints []EndpointDefinition) ServiceBuilder
 	AddServiceEndpoint(protocol, host string, port int32, basePath string) ServiceBuilder
+	SetSpecTransformers(transformers ...SpecTransformer) ServiceBuilder
 
 	SetUnstructuredType(assetType string) ServiceBuilder
 	SetUnstructuredContentType(contentType string) ServiceBuilder
@@ -46,8 +47,9 @@ type ServiceBuilder interface {
 }
 
 type serviceBodyBuilder struct {
-	err         error
-	serviceBody ServiceBody
+	err              error
+	serviceBody      ServiceBody
+	specTransformers []SpecTransformer
 }
 
 // NewServiceBodyBuilder - Creates a new service body builder
@@ -184,6 +186,11 @@ func (b *serviceBodyBuilder) AddServiceEndpoint(protocol, host string, port int3
 	return b
 }
 
+func (b *serviceBodyBuilder) SetSpecTransformers(transformers ...SpecTransformer) ServiceBuilder {
+	b.specTransformers = transformers
+	return b
+}
+
 func (b *serviceBodyBuilder) SetUnstructuredType(assetType string) ServiceBuilder {
 	b.serviceBody.UnstructuredProps.AssetType = assetType
 	return b
@@ -220,6 +227,12 @@ func (b *serviceBodyBuilder) Build() (ServiceBody, error) {
 	}
 	specProcessor := specParser.getSpecProcessor()
 	b.serviceBody.ResourceType = specProcessor.getResourceType()
+	switch processor := specProcessor.(type) {
+	case *graphQLProcessor:
+		processor.url = b.serviceBody.URL
+	case *protobufProcessor:
+		processor.url = b.serviceBody.URL
+	}
 
 	// Check if the type is unstructured to gather more info
 
@@ -230,5 +243,10 @@ func (b *serviceBodyBuilder) Build() (ServiceBody, error) {
 		}
 		b.serviceBody.Endpoints = endPoints
 	}
+
+	err = transformSpec(&b.serviceBody, b.specTransformers)
+	if err != nil {
+		return b.serviceBody, fmt.Errorf("failed to normalize the specification for '%s': %s", b.serviceBody.APIName, err)
+	}
 	return b.serviceBody, nil
 }
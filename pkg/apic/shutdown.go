@@ -0,0 +1,101 @@
+package apic
+
+import (
+	"context"
+	"sync"
+)
+
+// publishTracker - counts the in-flight publishes, no publish starts once the tracker is closed
+type publishTracker struct {
+	lock     sync.Mutex
+	inFlight int
+	closed   bool
+	drained  chan bool
+}
+
+func newPublishTracker() *publishTracker {
+	return &publishTracker{drained: make(chan bool)}
+}
+
+// start - records the start of a publish, returns false once the tracker is closed
+func (t *publishTracker) start() bool {
+	if t == nil {
+		return true
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.closed {
+		return false
+	}
+	t.inFlight++
+	return true
+}
+
+// done - records the end of a publish
+func (t *publishTracker) done() {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.inFlight--
+	if t.closed && t.inFlight == 0 {
+		close(t.drained)
+	}
+}
+
+// close - stops the publishes from starting, returns a channel closed once the in-flight publishes have ended
+func (t *publishTracker) close() chan bool {
+	if t == nil {
+		drained := make(chan bool)
+		close(drained)
+		return drained
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if !t.closed {
+		t.closed = true
+		if t.inFlight == 0 {
+			close(t.drained)
+		}
+	}
+	return t.drained
+}
+
+// SetContext - sets the context of the requests to AMPLIFY Central, they are canceled when the context is done
+func (c *ServiceClient) SetContext(ctx context.Context) {
+	c.ctx = ctx
+	if c.cfg != nil {
+		c.apiClient = c.newAPIClient(c.cfg)
+	}
+}
+
+// getContext - returns the context of the requests to AMPLIFY Central
+func (c *ServiceClient) getContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// Shutdown - stops processing the subscriptions and publishing the APIs, then waits for the in-flight
+// subscriptions and publishes to end until the context is done
+func (c *ServiceClient) Shutdown(ctx context.Context) error {
+	subscriptionsStopped := make(chan bool)
+	go func() {
+		if c.subscriptionMgr != nil {
+			c.subscriptionMgr.Stop()
+		}
+		close(subscriptionsStopped)
+	}()
+	drained := c.publishes.close()
+
+	for _, done := range []chan bool{subscriptionsStopped, drained} {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ErrPublishTimeout
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package apic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishTracker(t *testing.T) {
+	tracker := newPublishTracker()
+	assert.True(t, tracker.start())
+	assert.True(t, tracker.start())
+	tracker.done()
+
+	drained := tracker.close()
+	assert.False(t, tracker.start())
+	select {
+	case <-drained:
+		assert.Fail(t, "a publish is still in flight")
+	default:
+	}
+	tracker.done()
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		assert.Fail(t, "the publishes were not drained")
+	}
+	assert.Equal(t, drained, tracker.close())
+
+	// a client without tracker never blocks
+	var noTracker *publishTracker
+	assert.True(t, noTracker.start())
+	noTracker.done()
+	<-noTracker.close()
+}
+
+func TestServiceClientShutdown(t *testing.T) {
+	svcClient, _ := GetTestServiceClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	svcClient.SetContext(ctx)
+	assert.Equal(t, ctx, svcClient.getContext())
+	cancel()
+
+	// waits for the in-flight publish until the deadline
+	assert.True(t, svcClient.publishes.start())
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelShutdown()
+	assert.Equal(t, ErrPublishTimeout, svcClient.Shutdown(shutdownCtx))
+
+	svcClient.publishes.done()
+	assert.Nil(t, svcClient.Shutdown(context.Background()))
+
+	// no publish starts once shut down
+	_, err := svcClient.PublishService(ServiceBody{})
+	assert.Equal(t, ErrShuttingDown, err)
+}
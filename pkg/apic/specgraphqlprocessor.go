@@ -0,0 +1,60 @@
+package apic
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+
+	"github.com/Axway/agent-sdk/pkg/util/graphql"
+)
+
+type graphQLProcessor struct {
+	schema *graphql.Schema
+	// the url of the GraphQL server, the schema does not define it
+	url string
+}
+
+func newGraphQLProcessor(schema *graphql.Schema) *graphQLProcessor {
+	return &graphQLProcessor{schema: schema}
+}
+
+func (p *graphQLProcessor) getResourceType() string {
+	return GraphQL
+}
+
+// getEndpoints - returns the endpoint of the service url, the schema has no endpoint
+func (p *graphQLProcessor) getEndpoints() ([]EndpointDefinition, error) {
+	endpoints := []EndpointDefinition{}
+	if p.url == "" {
+		return endpoints, nil
+	}
+
+	serverURL, err := url.Parse(p.url)
+	if err != nil {
+		return nil, err
+	}
+	if serverURL.Scheme == "" || serverURL.Hostname() == "" {
+		return nil, errors.New("Invalid GraphQL server url " + p.url)
+	}
+	port := 0
+	if serverURL.Port() != "" {
+		if port, err = strconv.Atoi(serverURL.Port()); err != nil {
+			return nil, err
+		}
+	}
+	return append(endpoints, createEndpointDefinition(serverURL.Scheme, serverURL.Hostname(), port, serverURL.Path)), nil
+}
+
+// GetGraphQLOperations - returns the queries, mutations and subscriptions of a GraphQL schema, in SDL or as an
+// introspection result, for generating the documentation of GraphQL services
+func GetGraphQLOperations(spec []byte) ([]graphql.Operation, error) {
+	parser := newSpecResourceParser(spec, GraphQL)
+	if err := parser.parse(); err != nil {
+		return nil, err
+	}
+	processor, ok := parser.getSpecProcessor().(*graphQLProcessor)
+	if !ok {
+		return nil, errors.New("Invalid GraphQL specification")
+	}
+	return processor.schema.Operations, nil
+}
@@ -1,18 +1,27 @@
 package apic
 
 import (
+	"archive/zip"
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
 	"strings"
 
+	"github.com/Axway/agent-sdk/pkg/util/asyncapi"
+	"github.com/Axway/agent-sdk/pkg/util/graphql"
 	"github.com/Axway/agent-sdk/pkg/util/oas"
 
 	"github.com/Axway/agent-sdk/pkg/util/wsdl"
-	"github.com/emicklei/proto"
 	"gopkg.in/yaml.v2"
 )
 
+// maxSpecArchiveSize - the maximum size of the uncompressed files read from a specification archive
+const maxSpecArchiveSize = 20 * 1024 * 1024
+
 type specProcessor interface {
 	getEndpoints() ([]EndpointDefinition, error)
 	getResourceType() string
@@ -49,6 +58,9 @@ func (s *specResourceParser) discoverSpecTypeAndCreateProcessor() {
 	if s.specProcessor == nil {
 		s.specProcessor, _ = s.parseWSDLSpec()
 	}
+	if s.specProcessor == nil {
+		s.specProcessor, _ = s.parseGraphQLSpec()
+	}
 	if s.specProcessor == nil {
 		s.specProcessor, _ = s.parseProtobufSpec()
 	}
@@ -67,6 +79,8 @@ func (s *specResourceParser) createProcessorWithResourceType() error {
 		s.specProcessor, err = s.parseProtobufSpec()
 	case AsyncAPI:
 		s.specProcessor, err = s.parseAsyncAPISpec()
+	case GraphQL:
+		s.specProcessor, err = s.parseGraphQLSpec()
 	}
 	return err
 }
@@ -107,14 +121,21 @@ func (s *specResourceParser) discoverYAMLAndJSONSpec() (specProcessor, error) {
 		return nil, errors.New("Invalid swagger 2.0 specification")
 	}
 
-	specType, ok = specDef["asyncapi"]
+	_, ok = specDef["asyncapi"]
 	if ok {
-		return newAsyncAPIProcessor(specDef), nil
+		return s.parseAsyncAPISpec()
+	}
+
+	if graphql.IsIntrospection(s.resourceSpec) {
+		return s.parseGraphQLSpec()
 	}
 	return nil, errors.New("Unknown yaml or json based specification")
 }
 
 func (s *specResourceParser) parseWSDLSpec() (specProcessor, error) {
+	if isZipArchive(s.resourceSpec) {
+		return s.parseWSDLBundle()
+	}
 	def, err := wsdl.Unmarshal(s.resourceSpec)
 	if err != nil {
 		return nil, err
@@ -122,6 +143,24 @@ func (s *specResourceParser) parseWSDLSpec() (specProcessor, error) {
 	return newWsdlProcessor(def), nil
 }
 
+// parseWSDLBundle - parses a zip archive of WSDL and schema documents, the first WSDL document with a service is
+// the specification and its imports are resolved from the archive
+func (s *specResourceParser) parseWSDLBundle() (specProcessor, error) {
+	files, names, err := readZipFiles(s.resourceSpec, ".wsdl", ".xml", ".xsd")
+	if err != nil {
+		return nil, err
+	}
+	resolver := wsdl.NewBundleResolver(files)
+	for _, name := range names {
+		def, err := wsdl.UnmarshalWithResolver(files[name], name, resolver)
+		if err != nil || len(def.Services) == 0 {
+			continue
+		}
+		return newWsdlProcessor(def), nil
+	}
+	return nil, errors.New("Invalid wsdl specification, no service found in the archive")
+}
+
 func (s *specResourceParser) parseOAS2Spec() (specProcessor, error) {
 	swaggerObj := &oas2Swagger{}
 	// lowercase the byte array to ensure keys we care about are parsed
@@ -148,33 +187,96 @@ func (s *specResourceParser) parseOAS3Spec() (specProcessor, error) {
 }
 
 func (s *specResourceParser) parseAsyncAPISpec() (specProcessor, error) {
-	specDef := make(map[string]interface{})
-	// lowercase the byte array to ensure keys we care about are parsed
-	err := yaml.Unmarshal(s.resourceSpec, &specDef)
+	asyncapiDef, err := asyncapi.Parse(s.resourceSpec)
 	if err != nil {
-		err := json.Unmarshal(s.resourceSpec, &specDef)
-		if err != nil {
-			return nil, err
-		}
+		return nil, err
 	}
-	_, ok := specDef["asyncapi"]
-	if ok {
-		return newAsyncAPIProcessor(specDef), nil
+	return newAsyncAPIProcessor(asyncapiDef), nil
+}
+
+// parseGraphQLSpec - parses a GraphQL schema, in SDL or as the JSON result of an introspection query
+func (s *specResourceParser) parseGraphQLSpec() (specProcessor, error) {
+	var schema *graphql.Schema
+	var err error
+	if graphql.IsIntrospection(s.resourceSpec) {
+		schema, err = graphql.ParseIntrospection(s.resourceSpec)
+	} else {
+		schema, err = graphql.ParseSDL(s.resourceSpec)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return nil, errors.New("Invalid asyncapi specification")
+	return newGraphQLProcessor(schema), nil
 }
 
 func (s *specResourceParser) parseProtobufSpec() (specProcessor, error) {
-	reader := bytes.NewReader(s.resourceSpec)
-	parser := proto.NewParser(reader)
-	definition, err := parser.Parse()
+	definitions, err := parseProtobufBundle(s.resourceSpec)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(definition.Elements) > 0 {
-		return newProtobufProcessor(definition), nil
+	for _, definition := range definitions {
+		if len(definition.Elements) == 0 {
+			return nil, errors.New("Invalid protobuf specification")
+		}
+	}
+	return newProtobufBundleProcessor(definitions), nil
+
+}
+
+func isZipArchive(spec []byte) bool {
+	return bytes.HasPrefix(spec, []byte("PK\x03\x04"))
+}
+
+// readZipFiles - returns the content of the files of the zip archive with one of the suffixes, and their names sorted
+// so the result does not depend on the order of the archive
+func readZipFiles(spec []byte, suffixes ...string) (map[string][]byte, []string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(spec), int64(len(spec)))
+	if err != nil {
+		return nil, nil, err
 	}
-	return nil, errors.New("Invalid protobuf specification")
 
+	files := make(map[string][]byte)
+	names := make([]string, 0)
+	remaining := int64(maxSpecArchiveSize)
+	for _, file := range reader.File {
+		if !hasSuffix(file.Name, suffixes) {
+			continue
+		}
+		content, err := readZipFile(file, remaining)
+		if err != nil {
+			return nil, nil, err
+		}
+		remaining -= int64(len(content))
+		files[file.Name] = content
+		names = append(names, file.Name)
+	}
+	sort.Strings(names)
+	return files, names, nil
+}
+
+func hasSuffix(name string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(strings.ToLower(name), suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// readZipFile - returns the uncompressed content of the file, an error when it is larger than the limit
+func readZipFile(file *zip.File, limit int64) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	content, err := ioutil.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("The uncompressed files of the specification archive exceed %d bytes", maxSpecArchiveSize)
+	}
+	return content, nil
 }
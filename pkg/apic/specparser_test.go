@@ -1,6 +1,8 @@
 package apic
 
 import (
+	"archive/zip"
+	"bytes"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -256,7 +258,127 @@ func TestSpecProtobufProcessors(t *testing.T) {
 	endPoints, err := specProcessor.getEndpoints()
 
 	assert.Nil(t, err, "An unexpected Error was returned from getEndpoints with protobuf")
-	assert.Len(t, endPoints, 0, "The returned end points array is not empty")
+	assert.Len(t, endPoints, 0, "The returned end points array is not empty, the spec has no default host")
+
+	// the services without default host use the service url
+	specProcessor.(*protobufProcessor).url = "https://petstore.example.com"
+	endPoints, err = specProcessor.getEndpoints()
+	assert.Nil(t, err)
+	assert.Equal(t, []EndpointDefinition{
+		{Protocol: "grpc", Host: "petstore.example.com", Port: 443, BasePath: "/swaggerpetstore.SwaggerPetstoreService"},
+		{Protocol: "https", Host: "petstore.example.com", Port: 443, BasePath: "/api/pets"},
+	}, endPoints)
+	specProcessor.(*protobufProcessor).url = "petstore:9090"
+	_, err = specProcessor.getEndpoints()
+	assert.NotNil(t, err)
+
+	methods := specProcessor.(*protobufProcessor).getMethods()
+	assert.Len(t, methods, 5)
+	assert.Equal(t, "/swaggerpetstore.SwaggerPetstoreService/AddPet", methods[0].FullName())
+	assert.Equal(t, "swaggerpetstore.AddPetRequest", methods[0].InputType)
+	assert.Equal(t, []ProtobufHTTPRule{{Method: "POST", Path: "/api/pets", Body: "pet"}}, methods[0].HTTPRules)
+	assert.Equal(t, "google.protobuf.Empty", methods[1].OutputType)
+}
+
+func TestSpecWSDLBundle(t *testing.T) {
+	zipBuffer := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(zipBuffer)
+	for _, name := range []string{"orders.wsdl", "orders-interface.wsdl", "schemas/orders.xsd", "schemas/common.xsd"} {
+		content, _ := ioutil.ReadFile("../util/wsdl/testdata/bundle/" + name)
+		w, _ := zipWriter.Create(name)
+		w.Write(content)
+	}
+	zipWriter.Close()
+
+	specParser := newSpecResourceParser(zipBuffer.Bytes(), "")
+	assert.Nil(t, specParser.parse())
+	specProcessor := specParser.getSpecProcessor()
+	assert.Equal(t, Wsdl, specProcessor.getResourceType())
+
+	endPoints, err := specProcessor.getEndpoints()
+	assert.Nil(t, err)
+	assert.Equal(t, []EndpointDefinition{
+		{Protocol: "https", Host: "orders.example.com", Port: 443, BasePath: "/soap"},
+		{Protocol: "https", Host: "orders.example.com", Port: 443, BasePath: "/soap12"},
+		{Protocol: "http", Host: "admin.example.com", Port: 8080, BasePath: "/orders"},
+	}, endPoints)
+
+	operations, err := GetWSDLOperations(zipBuffer.Bytes())
+	assert.Nil(t, err)
+	assert.Len(t, operations, 3)
+	assert.Equal(t, "tns:GetOrderRequest", operations[0].Input)
+
+	// WSDL 2.0 descriptions are discovered
+	specParser, err = createSpecParser("../util/wsdl/testdata/reservation.wsdl", "")
+	assert.Nil(t, err)
+	specProcessor = specParser.getSpecProcessor()
+	assert.Equal(t, Wsdl, specProcessor.getResourceType())
+	endPoints, err = specProcessor.getEndpoints()
+	assert.Nil(t, err)
+	assert.Equal(t, []EndpointDefinition{{Protocol: "http", Host: "greath.example.com", Port: 80, BasePath: "/2004/reservation"}}, endPoints)
+}
+
+func TestSpecProtobufBundle(t *testing.T) {
+	library, _ := ioutil.ReadFile("./testdata/library/library.proto")
+	resources, _ := ioutil.ReadFile("./testdata/library/resources.proto")
+
+	zipBuffer := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(zipBuffer)
+	for name, content := range map[string][]byte{"library/library.proto": library, "library/resources.proto": resources} {
+		w, _ := zipWriter.Create(name)
+		w.Write(content)
+	}
+	zipWriter.Close()
+
+	testCases := map[string][]byte{
+		"zip":          zipBuffer.Bytes(),
+		"concatenated": append(append(resources, '\n'), library...),
+	}
+	for name, spec := range testCases {
+		t.Run(name, func(t *testing.T) {
+			specParser := newSpecResourceParser(spec, Protobuf)
+			assert.Nil(t, specParser.parse())
+			specProcessor := specParser.getSpecProcessor()
+			assert.Equal(t, Protobuf, specProcessor.getResourceType())
+
+			endPoints, err := specProcessor.getEndpoints()
+			assert.Nil(t, err)
+			assert.Equal(t, []EndpointDefinition{
+				{Protocol: "grpc", Host: "library.example.com", Port: 8443, BasePath: "/library.v1.LibraryService"},
+				{Protocol: "https", Host: "library.example.com", Port: 8443, BasePath: "/v1"},
+			}, endPoints)
+
+			methods, err := GetProtobufMethods(spec)
+			assert.Nil(t, err)
+			assert.Len(t, methods, 3)
+
+			assert.Equal(t, "GetBook", methods[0].Name)
+			assert.Equal(t, "library.v1.GetBookRequest", methods[0].InputType)
+			assert.Equal(t, "library.resources.Book", methods[0].OutputType)
+			assert.Equal(t, "Returns a book", methods[0].Comment)
+			assert.Equal(t, []ProtobufHTTPRule{
+				{Method: "GET", Path: "/v1/shelves/{shelf}/books/{name}"},
+				{Method: "GET", Path: "/v1/books/{name}"},
+			}, methods[0].HTTPRules)
+
+			assert.False(t, methods[1].StreamsRequest)
+			assert.True(t, methods[1].StreamsReturns)
+			assert.True(t, methods[2].StreamsRequest)
+			assert.True(t, methods[2].StreamsReturns)
+			assert.Len(t, methods[2].HTTPRules, 0)
+		})
+	}
+
+	// the uncompressed size of the archive is limited
+	zipBuffer.Reset()
+	zipWriter = zip.NewWriter(zipBuffer)
+	w, _ := zipWriter.Create("library/library.proto")
+	w.Write(library)
+	w, _ = zipWriter.Create("library/large.proto")
+	w.Write(make([]byte, maxSpecArchiveSize))
+	zipWriter.Close()
+	_, _, err := readZipFiles(zipBuffer.Bytes(), ".proto")
+	assert.NotNil(t, err)
 }
 
 func TestSpecAsyncAPIProcessors(t *testing.T) {
@@ -279,4 +401,66 @@ func TestSpecAsyncAPIProcessors(t *testing.T) {
 	assert.Equal(t, int32(5676), endPoints[0].Port)
 	assert.Equal(t, "mqtt", endPoints[0].Protocol)
 	assert.Equal(t, "", endPoints[0].BasePath)
+
+	// AsyncAPI 3 specification, in JSON
+	specParser, err = createSpecParser("../util/asyncapi/testdata/chat-websocket.json", "")
+	assert.Nil(t, err)
+	assert.Equal(t, AsyncAPI, specParser.getSpecProcessor().getResourceType())
+	endPoints, err = specParser.getSpecProcessor().getEndpoints()
+	assert.Nil(t, err)
+	assert.Equal(t, []EndpointDefinition{
+		{Host: "rabbit.internal", Port: 15672, Protocol: "amqp"},
+		{Host: "chat.example.com", Port: 443, Protocol: "wss", BasePath: "/v1"},
+	}, endPoints)
+
+	spec, _ := ioutil.ReadFile("../util/asyncapi/testdata/streetlights-kafka.yaml")
+	operations, err := GetAsyncAPIOperations(spec)
+	assert.Nil(t, err)
+	assert.Len(t, operations, 2)
+	assert.Equal(t, "receiveLightMeasurement", operations[1].OperationID)
+
+	// the validation errors are returned
+	_, err = NewServiceBodyBuilder().
+		SetResourceType(AsyncAPI).
+		SetAPISpec([]byte("asyncapi: 2.0.0\ninfo:\n  version: 1.0.0\nchannels: {}\n")).
+		Build()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "'info.title' key not found.")
+}
+
+func TestSpecGraphQLProcessors(t *testing.T) {
+	// SDL and introspection result are discovered
+	for _, specFile := range []string{"../util/graphql/testdata/library.graphql", "../util/graphql/testdata/library.json"} {
+		specParser, err := createSpecParser(specFile, "")
+		assert.Nil(t, err)
+		specProcessor := specParser.getSpecProcessor()
+		assert.Equal(t, GraphQL, specProcessor.getResourceType())
+		_, ok := specProcessor.(*graphQLProcessor)
+		assert.True(t, ok)
+
+		endPoints, err := specProcessor.getEndpoints()
+		assert.Nil(t, err)
+		assert.Len(t, endPoints, 0, "The schema has no endpoint without the service url")
+	}
+
+	_, err := createSpecParser("./testdata/petstore.proto", GraphQL)
+	assert.NotNil(t, err)
+
+	spec, _ := ioutil.ReadFile("../util/graphql/testdata/library.graphql")
+	serviceBody, err := NewServiceBodyBuilder().
+		SetAPIName("library").
+		SetURL("https://library.example.com:8443/graphql").
+		SetAPISpec(spec).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, GraphQL, serviceBody.ResourceType)
+	assert.Equal(t, []EndpointDefinition{{Host: "library.example.com", Port: 8443, Protocol: "https", BasePath: "/graphql"}}, serviceBody.Endpoints)
+
+	_, err = NewServiceBodyBuilder().SetURL("library.example.com").SetAPISpec(spec).Build()
+	assert.NotNil(t, err)
+
+	operations, err := GetGraphQLOperations(spec)
+	assert.Nil(t, err)
+	assert.Len(t, operations, 6)
+	assert.Equal(t, "bookAdded", operations[5].Name)
 }
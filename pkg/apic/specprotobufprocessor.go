@@ -1,21 +1,415 @@
 package apic
 
 import (
+	"bytes"
+	"errors"
+	"net"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
 	"github.com/emicklei/proto"
 )
 
+const (
+	protobufHTTPOption        = "(google.api.http)"
+	protobufDefaultHostOption = "(google.api.default_host)"
+	protobufGRPCProtocol      = "grpc"
+	protobufDefaultPort       = 443
+)
+
+var (
+	protobufHTTPMethods = []string{"get", "put", "post", "delete", "patch"}
+	protobufSyntaxRegEx = regexp.MustCompile(`(?m)^\s*syntax\s*=`)
+)
+
+// ProtobufMethod - an RPC method of a gRPC service, with the HTTP rules of its google.api.http annotation
+type ProtobufMethod struct {
+	Package        string
+	Service        string
+	Name           string
+	InputType      string // the fully qualified name of the request message, when it was resolved
+	OutputType     string // the fully qualified name of the response message, when it was resolved
+	StreamsRequest bool
+	StreamsReturns bool
+	Comment        string
+	HTTPRules      []ProtobufHTTPRule
+}
+
+// FullName - returns the gRPC path of the method, /package.Service/Method
+func (m ProtobufMethod) FullName() string {
+	return "/" + qualifyProtobufName(m.Package, m.Service) + "/" + m.Name
+}
+
+// ProtobufHTTPRule - an HTTP binding of an RPC method
+type ProtobufHTTPRule struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// protobufFile - a proto file of the specification, a specification can be a bundle of files
+type protobufFile struct {
+	name     string
+	pkg      string
+	imports  []string
+	messages map[string]bool
+	def      *proto.Proto
+}
+
 type protobufProcessor struct {
 	protobufDef *proto.Proto
+	files       []*protobufFile
+	// the url of the service, the endpoint of the services without google.api.default_host option
+	url string
 }
 
 func newProtobufProcessor(protobufDef *proto.Proto) *protobufProcessor {
-	return &protobufProcessor{protobufDef: protobufDef}
+	return newProtobufBundleProcessor([]*proto.Proto{protobufDef})
+}
+
+func newProtobufBundleProcessor(definitions []*proto.Proto) *protobufProcessor {
+	p := &protobufProcessor{files: make([]*protobufFile, 0, len(definitions))}
+	if len(definitions) > 0 {
+		p.protobufDef = definitions[0]
+	}
+	for _, def := range definitions {
+		p.files = append(p.files, newProtobufFile(def))
+	}
+	return p
+}
+
+func newProtobufFile(def *proto.Proto) *protobufFile {
+	file := &protobufFile{
+		name:     def.Filename,
+		imports:  make([]string, 0),
+		messages: make(map[string]bool),
+		def:      def,
+	}
+	proto.Walk(def,
+		proto.WithPackage(func(p *proto.Package) {
+			file.pkg = p.Name
+		}),
+		proto.WithImport(func(i *proto.Import) {
+			file.imports = append(file.imports, i.Filename)
+		}),
+	)
+	proto.Walk(def, proto.WithMessage(func(m *proto.Message) {
+		file.messages[qualifyProtobufName(file.pkg, messageName(m))] = true
+	}))
+	return file
+}
+
+// messageName - returns the name of the message, prefixed by the messages it is nested in
+func messageName(m *proto.Message) string {
+	name := m.Name
+	for parent, ok := m.Parent.(*proto.Message); ok; parent, ok = parent.Parent.(*proto.Message) {
+		name = parent.Name + "." + name
+	}
+	return name
+}
+
+func qualifyProtobufName(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
 }
 
 func (p *protobufProcessor) getResourceType() string {
 	return Protobuf
 }
 
+// getEndpoints - returns a grpc endpoint for each service, on the host of its google.api.default_host option or of the
+// service url, and an https endpoint when the methods of the service have google.api.http annotations
 func (p *protobufProcessor) getEndpoints() ([]EndpointDefinition, error) {
-	return []EndpointDefinition{}, nil
+	endpoints := []EndpointDefinition{}
+	urlHost, urlPort, err := p.urlHost()
+	if err != nil {
+		return nil, err
+	}
+	methods := p.getMethods()
+	for _, file := range p.files {
+		for _, service := range file.services() {
+			host, port, ok := p.defaultHost(service)
+			if !ok {
+				if urlHost == "" {
+					continue
+				}
+				host, port = urlHost, urlPort
+			}
+			endpoints = append(endpoints, createEndpointDefinition(protobufGRPCProtocol, host, port, "/"+qualifyProtobufName(file.pkg, service.Name)))
+
+			httpPaths := make([]string, 0)
+			for _, method := range methods {
+				if method.Package != file.pkg || method.Service != service.Name {
+					continue
+				}
+				for _, rule := range method.HTTPRules {
+					httpPaths = append(httpPaths, rule.Path)
+				}
+			}
+			if len(httpPaths) > 0 {
+				endpoints = append(endpoints, createEndpointDefinition("https", host, port, commonBasePath(httpPaths)))
+			}
+		}
+	}
+	return endpoints, nil
+}
+
+// getMethods - returns the RPC methods of all the services of the specification
+func (p *protobufProcessor) getMethods() []ProtobufMethod {
+	methods := make([]ProtobufMethod, 0)
+	for _, file := range p.files {
+		for _, service := range file.services() {
+			for _, element := range service.Elements {
+				rpc, ok := element.(*proto.RPC)
+				if !ok {
+					continue
+				}
+				method := ProtobufMethod{
+					Package:        file.pkg,
+					Service:        service.Name,
+					Name:           rpc.Name,
+					InputType:      p.resolveType(file, rpc.RequestType),
+					OutputType:     p.resolveType(file, rpc.ReturnsType),
+					StreamsRequest: rpc.StreamsRequest,
+					StreamsReturns: rpc.StreamsReturns,
+					HTTPRules:      parseHTTPRules(rpc),
+				}
+				if rpc.Comment != nil {
+					method.Comment = strings.TrimSpace(strings.Join(rpc.Comment.Lines, "\n"))
+				}
+				methods = append(methods, method)
+			}
+		}
+	}
+	return methods
+}
+
+func (f *protobufFile) services() []*proto.Service {
+	services := make([]*proto.Service, 0)
+	proto.Walk(f.def, proto.WithService(func(s *proto.Service) {
+		services = append(services, s)
+	}))
+	return services
+}
+
+// resolveType - qualifies the type with the package of the file, or of the imported file, defining it
+func (p *protobufProcessor) resolveType(file *protobufFile, typeName string) string {
+	typeName = strings.TrimPrefix(typeName, ".")
+	if file.messages[qualifyProtobufName(file.pkg, typeName)] {
+		return qualifyProtobufName(file.pkg, typeName)
+	}
+	for _, other := range p.files {
+		if other == file || !file.importsFile(other) {
+			continue
+		}
+		if other.messages[typeName] {
+			return typeName
+		}
+		if other.messages[qualifyProtobufName(other.pkg, typeName)] {
+			return qualifyProtobufName(other.pkg, typeName)
+		}
+	}
+	return typeName
+}
+
+// importsFile - returns true when the file imports the other file, the files of a concatenated bundle have no name
+// so they are all considered imported
+func (f *protobufFile) importsFile(other *protobufFile) bool {
+	if other.name == "" {
+		return true
+	}
+	for _, imported := range f.imports {
+		if imported == other.name || path.Base(imported) == path.Base(other.name) {
+			return true
+		}
+	}
+	return false
+}
+
+// urlHost - returns the host and port of the service url, the default port of its scheme when it has none
+func (p *protobufProcessor) urlHost() (string, int, error) {
+	if p.url == "" {
+		return "", 0, nil
+	}
+	serviceURL, err := url.Parse(p.url)
+	if err != nil {
+		return "", 0, err
+	}
+	if serviceURL.Hostname() == "" {
+		return "", 0, errors.New("Invalid gRPC service url " + p.url)
+	}
+	if serviceURL.Port() != "" {
+		port, err := strconv.Atoi(serviceURL.Port())
+		return serviceURL.Hostname(), port, err
+	}
+	if port, ok := wsdlDefaultPorts[serviceURL.Scheme]; ok {
+		return serviceURL.Hostname(), port, nil
+	}
+	return serviceURL.Hostname(), protobufDefaultPort, nil
+}
+
+func (p *protobufProcessor) defaultHost(service *proto.Service) (string, int, bool) {
+	for _, element := range service.Elements {
+		option, ok := element.(*proto.Option)
+		if !ok || option.Name != protobufDefaultHostOption || option.Constant.Source == "" {
+			continue
+		}
+		host, portStr, err := net.SplitHostPort(option.Constant.Source)
+		if err != nil {
+			return option.Constant.Source, protobufDefaultPort, true
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return host, protobufDefaultPort, true
+		}
+		return host, port, true
+	}
+	return "", 0, false
+}
+
+// parseHTTPRules - returns the HTTP rules of the google.api.http option of the method, with its additional bindings
+func parseHTTPRules(rpc *proto.RPC) []ProtobufHTTPRule {
+	rules := make([]ProtobufHTTPRule, 0)
+	for _, element := range rpc.Elements {
+		option, ok := element.(*proto.Option)
+		if !ok || option.Name != protobufHTTPOption {
+			continue
+		}
+		rules = append(rules, parseHTTPRule(option.Constant.OrderedMap)...)
+	}
+	return rules
+}
+
+func parseHTTPRule(ruleMap proto.LiteralMap) []ProtobufHTTPRule {
+	rules := make([]ProtobufHTTPRule, 0)
+	rule := ProtobufHTTPRule{}
+	if body, ok := ruleMap.Get("body"); ok {
+		rule.Body = body.Source
+	}
+	for _, method := range protobufHTTPMethods {
+		if literal, ok := ruleMap.Get(method); ok {
+			rule.Method = strings.ToUpper(method)
+			rule.Path = literal.Source
+		}
+	}
+	if custom, ok := ruleMap.Get("custom"); ok {
+		if kind, ok := custom.OrderedMap.Get("kind"); ok {
+			rule.Method = strings.ToUpper(kind.Source)
+		}
+		if customPath, ok := custom.OrderedMap.Get("path"); ok {
+			rule.Path = customPath.Source
+		}
+	}
+	if rule.Path != "" {
+		rules = append(rules, rule)
+	}
+
+	for _, each := range ruleMap {
+		if each.Name != "additional_bindings" {
+			continue
+		}
+		if len(each.Array) > 0 {
+			for _, binding := range each.Array {
+				rules = append(rules, parseHTTPRule(binding.OrderedMap)...)
+			}
+		} else {
+			rules = append(rules, parseHTTPRule(each.OrderedMap)...)
+		}
+	}
+	return rules
+}
+
+// commonBasePath - returns the longest path prefix, made of whole segments without templates, shared by the paths
+func commonBasePath(paths []string) string {
+	common := strings.Split(strings.Trim(paths[0], "/"), "/")
+	for _, p := range paths[1:] {
+		segments := strings.Split(strings.Trim(p, "/"), "/")
+		i := 0
+		for i < len(common) && i < len(segments) && common[i] == segments[i] {
+			i++
+		}
+		common = common[:i]
+	}
+	for i, segment := range common {
+		if strings.ContainsAny(segment, "{*") {
+			common = common[:i]
+			break
+		}
+	}
+	return "/" + strings.Join(common, "/")
+}
+
+// parseProtobufBundle - parses the proto files of a zip archive, of concatenated proto files or of a single file
+func parseProtobufBundle(spec []byte) ([]*proto.Proto, error) {
+	if isZipArchive(spec) {
+		return parseProtobufZip(spec)
+	}
+
+	// concatenated proto files each start with their syntax statement
+	sources := [][]byte{spec}
+	if locs := protobufSyntaxRegEx.FindAllIndex(spec, -1); len(locs) > 1 {
+		sources = make([][]byte, 0, len(locs))
+		for i, loc := range locs {
+			end := len(spec)
+			if i+1 < len(locs) {
+				end = locs[i+1][0]
+			}
+			start := loc[0]
+			if i == 0 {
+				start = 0
+			}
+			sources = append(sources, spec[start:end])
+		}
+	}
+
+	definitions := make([]*proto.Proto, 0, len(sources))
+	for _, source := range sources {
+		definition, err := proto.NewParser(bytes.NewReader(source)).Parse()
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions, nil
+}
+
+func parseProtobufZip(spec []byte) ([]*proto.Proto, error) {
+	files, names, err := readZipFiles(spec, ".proto")
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := make([]*proto.Proto, 0, len(names))
+	for _, name := range names {
+		parser := proto.NewParser(bytes.NewReader(files[name]))
+		parser.Filename(name)
+		definition, err := parser.Parse()
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+	}
+	if len(definitions) == 0 {
+		return nil, errors.New("Invalid protobuf specification, no proto file in the archive")
+	}
+	return definitions, nil
+}
+
+// GetProtobufMethods - returns the RPC methods of the services of a protobuf specification, or bundle of
+// specifications, for generating the documentation of gRPC services
+func GetProtobufMethods(spec []byte) ([]ProtobufMethod, error) {
+	parser := newSpecResourceParser(spec, Protobuf)
+	if err := parser.parse(); err != nil {
+		return nil, err
+	}
+	processor, ok := parser.getSpecProcessor().(*protobufProcessor)
+	if !ok {
+		return nil, errors.New("Invalid protobuf specification")
+	}
+	return processor.getMethods(), nil
 }
@@ -0,0 +1,136 @@
+package apic
+
+import (
+	"encoding/json"
+	"fmt"
+
+	coreerrors "github.com/Axway/agent-sdk/pkg/util/errors"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v2"
+)
+
+// SpecDocument - the OpenAPI specification of a service body, as transformed by the spec transformers.
+// OAS2 is set for the swagger 2 specifications, OAS3 for the openapi 3 specifications.
+type SpecDocument struct {
+	ResourceType string
+	OAS2         *openapi2.T
+	OAS3         *openapi3.T
+}
+
+// SpecTransformer - a step of the spec normalization pipeline, run in order when the service body is built
+type SpecTransformer interface {
+	Name() string
+	Transform(doc *SpecDocument, serviceBody *ServiceBody) error
+}
+
+// DefaultSpecTransformers - returns the default normalization pipeline: swagger 2 specs are converted to openapi 3,
+// the servers are set to the endpoints of the service body, the security schemes of the auth policy are added,
+// the x- extensions are removed, and the result is validated
+func DefaultSpecTransformers(securityOptions SecuritySchemeOptions) []SpecTransformer {
+	return []SpecTransformer{
+		NewOAS2ToOAS3Transformer(),
+		NewPublicEndpointsTransformer(),
+		NewSecuritySchemeTransformer(securityOptions),
+		NewStripExtensionsTransformer(),
+		NewValidateSpecTransformer(),
+	}
+}
+
+// transformSpec - runs the transformers on the openapi specification of the service body, other specifications
+// are published as they are
+func transformSpec(serviceBody *ServiceBody, transformers []SpecTransformer) error {
+	if len(transformers) == 0 || (serviceBody.ResourceType != Oas2 && serviceBody.ResourceType != Oas3) {
+		return nil
+	}
+
+	doc, err := loadSpecDocument(serviceBody.SpecDefinition, serviceBody.ResourceType)
+	if err != nil {
+		return ErrSpecTransform.FormatError("load", err.Error())
+	}
+
+	for _, transformer := range transformers {
+		log.Tracef("transforming the specification of %s with %s", serviceBody.APIName, transformer.Name())
+		if err := transformer.Transform(doc, serviceBody); err != nil {
+			if _, ok := err.(*coreerrors.AgentError); ok {
+				return err
+			}
+			return ErrSpecTransform.FormatError(transformer.Name(), err.Error())
+		}
+	}
+
+	spec, err := doc.marshal()
+	if err != nil {
+		return ErrSpecTransform.FormatError("marshal", err.Error())
+	}
+	serviceBody.SpecDefinition = spec
+	serviceBody.ResourceType = doc.ResourceType
+	return nil
+}
+
+func loadSpecDocument(spec []byte, resourceType string) (*SpecDocument, error) {
+	doc := &SpecDocument{ResourceType: resourceType}
+	if resourceType == Oas3 {
+		return doc, doc.setOAS3(spec)
+	}
+	return doc, doc.setOAS2(spec)
+}
+
+func (d *SpecDocument) setOAS3(spec []byte) error {
+	oas3Obj, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return err
+	}
+	d.OAS3 = oas3Obj
+	return nil
+}
+
+func (d *SpecDocument) setOAS2(spec []byte) error {
+	jsonSpec, err := specToJSON(spec)
+	if err != nil {
+		return err
+	}
+	swaggerObj := &openapi2.T{}
+	if err := json.Unmarshal(jsonSpec, swaggerObj); err != nil {
+		return err
+	}
+	d.OAS2 = swaggerObj
+	return nil
+}
+
+func (d *SpecDocument) marshal() ([]byte, error) {
+	if d.OAS3 != nil {
+		return json.Marshal(d.OAS3)
+	}
+	return json.Marshal(d.OAS2)
+}
+
+// specToJSON - returns the yaml, or json, specification as json so the extensions of the spec are kept
+func specToJSON(spec []byte) ([]byte, error) {
+	if json.Valid(spec) {
+		return spec, nil
+	}
+	var specDef interface{}
+	if err := yaml.Unmarshal(spec, &specDef); err != nil {
+		return nil, err
+	}
+	return json.Marshal(convertYAMLMaps(specDef))
+}
+
+// convertYAMLMaps - converts the maps of an unmarshalled yaml document to maps with string keys
+func convertYAMLMaps(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[fmt.Sprint(key)] = convertYAMLMaps(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range v {
+			v[i] = convertYAMLMaps(val)
+		}
+	}
+	return value
+}
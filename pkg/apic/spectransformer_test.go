@@ -0,0 +1,260 @@
+package apic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const swagger2Spec = `swagger: "2.0"
+info:
+  title: Orders
+  version: 1.0.0
+  x-internal-owner: team-a
+host: backend.internal:8080
+basePath: /orders
+schemes:
+  - http
+x-gateway-integration:
+  uri: http://backend.internal:8080
+paths:
+  /orders/{id}:
+    get:
+      operationId: getOrder
+      x-internal-handler: orders.get
+      parameters:
+        - name: id
+          in: path
+          required: true
+          type: string
+      responses:
+        "200":
+          description: the order
+          schema:
+            $ref: "#/definitions/Order"
+definitions:
+  x-order:
+    type: object
+  Order:
+    type: object
+    properties:
+      id:
+        type: string
+`
+
+const oas3Spec = `openapi: 3.0.1
+info:
+  title: Orders
+  version: 1.0.0
+servers:
+  - url: http://backend.internal:8080/orders
+x-internal-routing: blue
+x-logo: https://example.com/logo.png
+paths:
+  /orders:
+    get:
+      responses:
+        "200":
+          description: the orders
+`
+
+func buildTransformedServiceBody(spec, authPolicy string, transformers ...SpecTransformer) (ServiceBody, error) {
+	return NewServiceBodyBuilder().
+		SetID("orders").
+		SetAPIName("orders").
+		SetAPISpec([]byte(spec)).
+		SetAuthPolicy(authPolicy).
+		AddServiceEndpoint("https", "api.example.com", 443, "/v1/orders").
+		AddServiceEndpoint("http", "api.example.com", 8080, "/v1/orders").
+		SetSpecTransformers(transformers...).
+		Build()
+}
+
+func TestDefaultSpecTransformers(t *testing.T) {
+	serviceBody, err := buildTransformedServiceBody(swagger2Spec, Apikey, DefaultSpecTransformers(SecuritySchemeOptions{})...)
+	assert.Nil(t, err)
+	assert.Equal(t, Oas3, serviceBody.ResourceType)
+
+	specDef := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(serviceBody.SpecDefinition, &specDef))
+	assert.Equal(t, "3.0.3", specDef["openapi"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"url": "https://api.example.com/v1/orders"},
+		map[string]interface{}{"url": "http://api.example.com:8080/v1/orders"},
+	}, specDef["servers"])
+
+	components := specDef["components"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"apiKey": map[string]interface{}{"type": "apiKey", "name": "KeyId", "in": "header"},
+	}, components["securitySchemes"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"apiKey": []interface{}{}}}, specDef["security"])
+
+	// the extensions are removed, the schema named like an extension is kept
+	assert.NotContains(t, specDef, "x-gateway-integration")
+	assert.NotContains(t, specDef["info"], "x-internal-owner")
+	operation := specDef["paths"].(map[string]interface{})["/orders/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+	assert.NotContains(t, operation, "x-internal-handler")
+	assert.Contains(t, components["schemas"], "x-order")
+
+	// the spec is parsed again as openapi 3
+	specParser := newSpecResourceParser(serviceBody.SpecDefinition, "")
+	assert.Nil(t, specParser.parse())
+	assert.Equal(t, Oas3, specParser.getSpecProcessor().getResourceType())
+}
+
+func TestSpecTransformers(t *testing.T) {
+	// swagger 2 is kept when not converted
+	serviceBody, err := buildTransformedServiceBody(swagger2Spec, Oauth,
+		NewPublicEndpointsTransformer(),
+		NewSecuritySchemeTransformer(SecuritySchemeOptions{TokenURL: "https://auth.example.com/token", Scopes: map[string]string{"read": "read orders"}}),
+		NewValidateSpecTransformer(),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, Oas2, serviceBody.ResourceType)
+	specDef := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(serviceBody.SpecDefinition, &specDef))
+	assert.Equal(t, "api.example.com", specDef["host"])
+	assert.Equal(t, "/v1/orders", specDef["basePath"])
+	assert.Equal(t, []interface{}{"https"}, specDef["schemes"])
+	assert.Equal(t, map[string]interface{}{
+		"oauth": map[string]interface{}{
+			"type":     "oauth2",
+			"flow":     "application",
+			"tokenUrl": "https://auth.example.com/token",
+			"scopes":   map[string]interface{}{"read": "read orders"},
+		},
+	}, specDef["securityDefinitions"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"oauth": []interface{}{"read"}}}, specDef["security"])
+	assert.Contains(t, specDef, "x-gateway-integration")
+
+	// only the extensions with the prefixes are removed, oauth without a token url is a bearer scheme
+	serviceBody, err = buildTransformedServiceBody(oas3Spec, Oauth,
+		NewSecuritySchemeTransformer(SecuritySchemeOptions{}),
+		NewStripExtensionsTransformer("x-internal"),
+	)
+	assert.Nil(t, err)
+	specDef = make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(serviceBody.SpecDefinition, &specDef))
+	assert.NotContains(t, specDef, "x-internal-routing")
+	assert.Contains(t, specDef, "x-logo")
+	assert.Equal(t, []interface{}{map[string]interface{}{"url": "http://backend.internal:8080/orders"}}, specDef["servers"])
+	components := specDef["components"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"oauth": map[string]interface{}{"type": "http", "scheme": "bearer"},
+	}, components["securitySchemes"])
+
+	// pass through services get no security scheme
+	serviceBody, err = buildTransformedServiceBody(oas3Spec, Passthrough, NewSecuritySchemeTransformer(SecuritySchemeOptions{}))
+	assert.Nil(t, err)
+	assert.NotContains(t, string(serviceBody.SpecDefinition), "securitySchemes")
+
+	// the specs that are not openapi are not transformed
+	wsdl := []byte("<definitions></definitions>")
+	serviceBody, err = NewServiceBodyBuilder().SetAPISpec(wsdl).SetResourceType(Unstructured).SetSpecTransformers(DefaultSpecTransformers(SecuritySchemeOptions{})...).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, wsdl, serviceBody.SpecDefinition)
+}
+
+func TestSecuritySchemeTransformerOAS2WithoutTokenURL(t *testing.T) {
+	// swagger 2 has no bearer scheme, no oauth scheme is added without a token url
+	serviceBody, err := buildTransformedServiceBody(swagger2Spec, Oauth,
+		NewPublicEndpointsTransformer(),
+		NewSecuritySchemeTransformer(SecuritySchemeOptions{}),
+		NewValidateSpecTransformer(),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, Oas2, serviceBody.ResourceType)
+	specDef := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(serviceBody.SpecDefinition, &specDef))
+	assert.NotContains(t, specDef, "securityDefinitions")
+	assert.NotContains(t, specDef, "security")
+
+	// the api key scheme does not need a token url
+	serviceBody, err = buildTransformedServiceBody(swagger2Spec, Apikey, NewSecuritySchemeTransformer(SecuritySchemeOptions{}))
+	assert.Nil(t, err)
+	specDef = make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(serviceBody.SpecDefinition, &specDef))
+	assert.Contains(t, specDef["securityDefinitions"], APIKeySecuritySchemeName)
+}
+
+func TestStripExtensions(t *testing.T) {
+	specDef := map[string]interface{}{
+		"x-root": "root",
+		"paths": map[string]interface{}{
+			"x-paths-owner": "team-a",
+			"/orders": map[string]interface{}{
+				"get": map[string]interface{}{
+					"responses": map[string]interface{}{
+						"x-response-cache": true,
+						"200":              map[string]interface{}{"description": "the orders", "x-internal": true},
+					},
+				},
+			},
+		},
+		"responses": map[string]interface{}{
+			"x-error": map[string]interface{}{"description": "the error", "x-internal": true},
+		},
+		"components": map[string]interface{}{
+			"responses": map[string]interface{}{
+				"x-error": map[string]interface{}{"description": "the error"},
+			},
+			"schemas": map[string]interface{}{
+				"x-order": map[string]interface{}{"type": "object", "x-internal": true},
+			},
+		},
+	}
+	transformer := NewStripExtensionsTransformer().(*stripExtensionsTransformer)
+	transformer.strip(specDef, true)
+
+	// the extensions next to the paths and the responses of an operation are removed
+	assert.Equal(t, map[string]interface{}{
+		"/orders": map[string]interface{}{
+			"get": map[string]interface{}{
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{"description": "the orders"},
+				},
+			},
+		},
+	}, specDef["paths"])
+	assert.NotContains(t, specDef, "x-root")
+
+	// the definitions named like extensions are kept
+	assert.Equal(t, map[string]interface{}{
+		"x-error": map[string]interface{}{"description": "the error"},
+	}, specDef["responses"])
+	components := specDef["components"].(map[string]interface{})
+	assert.Contains(t, components["responses"], "x-error")
+	assert.Equal(t, map[string]interface{}{
+		"x-order": map[string]interface{}{"type": "object"},
+	}, components["schemas"])
+}
+
+func TestEndpointHost(t *testing.T) {
+	assert.Equal(t, "api.example.com", endpointHost(EndpointDefinition{Protocol: "https", Host: "api.example.com", Port: 443}))
+	assert.Equal(t, "api.example.com", endpointHost(EndpointDefinition{Protocol: "wss", Host: "api.example.com", Port: 443}))
+	assert.Equal(t, "api.example.com", endpointHost(EndpointDefinition{Protocol: "http", Host: "api.example.com"}))
+	assert.Equal(t, "api.example.com:8443", endpointHost(EndpointDefinition{Protocol: "https", Host: "api.example.com", Port: 8443}))
+	assert.Equal(t, "api.example.com:443", endpointHost(EndpointDefinition{Protocol: "grpc", Host: "api.example.com", Port: 443}))
+}
+
+func TestValidateSpecTransformer(t *testing.T) {
+	invalidSpec := `openapi: 3.0.1
+info:
+  title: Orders
+  version: 1.0.0
+paths:
+  /orders:
+    get:
+      responses:
+        "200":
+          description: the orders
+          content:
+            application/json:
+              schema:
+                type: unknown
+`
+	_, err := buildTransformedServiceBody(invalidSpec, Passthrough, NewValidateSpecTransformer())
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "1164")
+}
@@ -0,0 +1,359 @@
+package apic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	coreerrors "github.com/Axway/agent-sdk/pkg/util/errors"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+	"github.com/Axway/agent-sdk/pkg/util/oas"
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Names of the security schemes added by the security scheme transformer
+const (
+	APIKeySecuritySchemeName = "apiKey"
+	OAuthSecuritySchemeName  = "oauth"
+)
+
+// oas2ToOAS3Transformer - converts swagger 2 specifications to openapi 3
+type oas2ToOAS3Transformer struct{}
+
+// NewOAS2ToOAS3Transformer - creates a transformer converting swagger 2 specifications to openapi 3
+func NewOAS2ToOAS3Transformer() SpecTransformer {
+	return &oas2ToOAS3Transformer{}
+}
+
+func (t *oas2ToOAS3Transformer) Name() string {
+	return "oas2ToOAS3"
+}
+
+func (t *oas2ToOAS3Transformer) Transform(doc *SpecDocument, serviceBody *ServiceBody) error {
+	if doc.OAS2 == nil {
+		return nil
+	}
+	oas3Obj, err := openapi2conv.ToV3(doc.OAS2)
+	if err != nil {
+		return err
+	}
+	doc.OAS3 = oas3Obj
+	doc.OAS2 = nil
+	doc.ResourceType = Oas3
+	return nil
+}
+
+// publicEndpointsTransformer - sets the servers, or host and base path, of the spec to the endpoints of the service body
+type publicEndpointsTransformer struct{}
+
+// NewPublicEndpointsTransformer - creates a transformer setting the servers of the spec to the gateway endpoints
+// of the service body
+func NewPublicEndpointsTransformer() SpecTransformer {
+	return &publicEndpointsTransformer{}
+}
+
+func (t *publicEndpointsTransformer) Name() string {
+	return "publicEndpoints"
+}
+
+func (t *publicEndpointsTransformer) Transform(doc *SpecDocument, serviceBody *ServiceBody) error {
+	if len(serviceBody.Endpoints) == 0 {
+		return nil
+	}
+
+	if doc.OAS3 != nil {
+		urls := make([]string, 0, len(serviceBody.Endpoints))
+		for _, endpoint := range serviceBody.Endpoints {
+			urls = append(urls, endpoint.Protocol+"://"+endpointHost(endpoint)+endpoint.BasePath)
+		}
+		doc.OAS3.Servers = nil
+		oas.SetOAS3Servers(urls, doc.OAS3)
+		return nil
+	}
+
+	// swagger 2 has a single host and base path, with a scheme for each endpoint on it
+	first := serviceBody.Endpoints[0]
+	doc.OAS2.Host = endpointHost(first)
+	doc.OAS2.BasePath = first.BasePath
+	doc.OAS2.Schemes = make([]string, 0)
+	for _, endpoint := range serviceBody.Endpoints {
+		if endpointHost(endpoint) != doc.OAS2.Host || endpoint.BasePath != first.BasePath || !validOA2Schemes[endpoint.Protocol] {
+			continue
+		}
+		if !containsString(doc.OAS2.Schemes, endpoint.Protocol) {
+			doc.OAS2.Schemes = append(doc.OAS2.Schemes, endpoint.Protocol)
+		}
+	}
+	return nil
+}
+
+// endpointDefaultPorts - the default ports of the endpoint protocols, omitted from the hosts of the public endpoints
+var endpointDefaultPorts = map[string]int32{"http": 80, "https": 443, "ws": 80, "wss": 443}
+
+// endpointHost - returns the host of the endpoint, with its port when it is not the default port of the protocol
+func endpointHost(endpoint EndpointDefinition) string {
+	if endpoint.Port == 0 {
+		return endpoint.Host
+	}
+	if defaultPort, ok := endpointDefaultPorts[endpoint.Protocol]; ok && defaultPort == endpoint.Port {
+		return endpoint.Host
+	}
+	return net.JoinHostPort(endpoint.Host, strconv.Itoa(int(endpoint.Port)))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SecuritySchemeOptions - the options of the security schemes added for the auth policy of the service body
+type SecuritySchemeOptions struct {
+	APIKeyName string            // the name of the api key, defaults to KeyId
+	APIKeyIn   string            // where the api key is sent, header or query, defaults to header
+	TokenURL   string            // the token url of the oauth client credentials flow, when not set a bearer scheme is used for openapi 3 and no scheme is added to swagger 2
+	Scopes     map[string]string // the scopes of the oauth client credentials flow
+}
+
+// securitySchemeTransformer - adds the security scheme matching the auth policy of the service body
+type securitySchemeTransformer struct {
+	options SecuritySchemeOptions
+}
+
+// NewSecuritySchemeTransformer - creates a transformer adding the security scheme matching the auth policy
+// (verify-api-key or verify-oauth-token) when the spec has none of that type
+func NewSecuritySchemeTransformer(options SecuritySchemeOptions) SpecTransformer {
+	if options.APIKeyName == "" {
+		options.APIKeyName = "KeyId"
+	}
+	if options.APIKeyIn == "" {
+		options.APIKeyIn = "header"
+	}
+	if options.Scopes == nil {
+		options.Scopes = make(map[string]string)
+	}
+	return &securitySchemeTransformer{options: options}
+}
+
+func (t *securitySchemeTransformer) Name() string {
+	return "securityScheme"
+}
+
+func (t *securitySchemeTransformer) Transform(doc *SpecDocument, serviceBody *ServiceBody) error {
+	switch serviceBody.AuthPolicy {
+	case Apikey, Oauth:
+	default:
+		return nil
+	}
+
+	switch {
+	case doc.OAS3 != nil:
+		t.transformOAS3(doc.OAS3, serviceBody.AuthPolicy)
+	case serviceBody.AuthPolicy == Oauth && t.options.TokenURL == "":
+		// swagger 2 has no bearer scheme and its oauth2 scheme requires a token url
+		log.Warnf("No oauth security scheme added to the swagger 2 specification of %s, no token url is set", serviceBody.APIName)
+	default:
+		t.transformOAS2(doc.OAS2, serviceBody.AuthPolicy)
+	}
+	return nil
+}
+
+func (t *securitySchemeTransformer) transformOAS3(spec *openapi3.T, authPolicy string) {
+	name := APIKeySecuritySchemeName
+	scheme := &openapi3.SecurityScheme{Type: "apiKey", Name: t.options.APIKeyName, In: t.options.APIKeyIn}
+	if authPolicy == Oauth {
+		name = OAuthSecuritySchemeName
+		scheme = &openapi3.SecurityScheme{Type: "http", Scheme: "bearer"}
+		if t.options.TokenURL != "" {
+			scheme = &openapi3.SecurityScheme{
+				Type: "oauth2",
+				Flows: &openapi3.OAuthFlows{
+					ClientCredentials: &openapi3.OAuthFlow{TokenURL: t.options.TokenURL, Scopes: t.options.Scopes},
+				},
+			}
+		}
+	}
+
+	for _, existing := range spec.Components.SecuritySchemes {
+		if existing.Value != nil && existing.Value.Type == scheme.Type {
+			return
+		}
+	}
+	if spec.Components.SecuritySchemes == nil {
+		spec.Components.SecuritySchemes = make(openapi3.SecuritySchemes)
+	}
+	spec.Components.SecuritySchemes[name] = &openapi3.SecuritySchemeRef{Value: scheme}
+	spec.Security = *openapi3.NewSecurityRequirements().With(openapi3.NewSecurityRequirement().Authenticate(name, t.scopes(authPolicy)...))
+}
+
+func (t *securitySchemeTransformer) transformOAS2(spec *openapi2.T, authPolicy string) {
+	name := APIKeySecuritySchemeName
+	scheme := &openapi2.SecurityScheme{Type: "apiKey", Name: t.options.APIKeyName, In: t.options.APIKeyIn}
+	if authPolicy == Oauth {
+		name = OAuthSecuritySchemeName
+		scheme = &openapi2.SecurityScheme{Type: "oauth2", Flow: "application", TokenURL: t.options.TokenURL, Scopes: t.options.Scopes}
+	}
+
+	for _, existing := range spec.SecurityDefinitions {
+		if existing.Type == scheme.Type {
+			return
+		}
+	}
+	if spec.SecurityDefinitions == nil {
+		spec.SecurityDefinitions = make(map[string]*openapi2.SecurityScheme)
+	}
+	spec.SecurityDefinitions[name] = scheme
+	spec.Security = openapi2.SecurityRequirements{{name: t.scopes(authPolicy)}}
+}
+
+func (t *securitySchemeTransformer) scopes(authPolicy string) []string {
+	scopes := make([]string, 0)
+	if authPolicy == Oauth {
+		for scope := range t.options.Scopes {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// stripExtensionsTransformer - removes the x- extensions matching its prefixes
+type stripExtensionsTransformer struct {
+	prefixes []string
+}
+
+// NewStripExtensionsTransformer - creates a transformer removing the extensions starting with one of the prefixes,
+// all x- extensions are removed when no prefix is given
+func NewStripExtensionsTransformer(prefixes ...string) SpecTransformer {
+	if len(prefixes) == 0 {
+		prefixes = []string{"x-"}
+	}
+	return &stripExtensionsTransformer{prefixes: prefixes}
+}
+
+func (t *stripExtensionsTransformer) Name() string {
+	return "stripExtensions"
+}
+
+func (t *stripExtensionsTransformer) Transform(doc *SpecDocument, serviceBody *ServiceBody) error {
+	data, err := doc.marshal()
+	if err != nil {
+		return err
+	}
+	specDef := make(map[string]interface{})
+	if err := json.Unmarshal(data, &specDef); err != nil {
+		return err
+	}
+	t.strip(specDef, true)
+	if data, err = json.Marshal(specDef); err != nil {
+		return err
+	}
+
+	if doc.OAS3 != nil {
+		return doc.setOAS3(data)
+	}
+	return doc.setOAS2(data)
+}
+
+// namedObjectKeys - the keys of the maps whose keys are names defined by the spec, not extensions
+var namedObjectKeys = map[string]bool{
+	"properties":      true,
+	"definitions":     true,
+	"schemas":         true,
+	"parameters":      true,
+	"responses":       true,
+	"securitySchemes": true,
+	"headers":         true,
+	"examples":        true,
+	"requestBodies":   true,
+	"callbacks":       true,
+	"links":           true,
+	"paths":           true,
+}
+
+// extensibleNamedObjectKeys - the maps of names that can also hold extensions, the responses of an operation, but not
+// the responses defined at the root of a swagger 2 spec or in the components of an openapi 3 spec
+var extensibleNamedObjectKeys = map[string]bool{
+	"paths":     true,
+	"responses": true,
+}
+
+// strip - removes the extensions of the value, definitions is true for the maps holding the definitions of the spec,
+// the root of a swagger 2 spec and the components of an openapi 3 spec
+func (t *stripExtensionsTransformer) strip(value interface{}, definitions bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			if t.isStripped(key) {
+				delete(v, key)
+				continue
+			}
+			// the children of named objects are definitions, their own properties are checked
+			if children, ok := val.(map[string]interface{}); ok && namedObjectKeys[key] {
+				extensible := extensibleNamedObjectKeys[key] && !(definitions && key == "responses")
+				for name, child := range children {
+					if extensible && t.isStripped(name) {
+						delete(children, name)
+						continue
+					}
+					t.strip(child, false)
+				}
+				continue
+			}
+			t.strip(val, key == "components")
+		}
+	case []interface{}:
+		for _, val := range v {
+			t.strip(val, false)
+		}
+	}
+}
+
+func (t *stripExtensionsTransformer) isStripped(key string) bool {
+	for _, prefix := range t.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSpecTransformer - validates the transformed specification
+type validateSpecTransformer struct{}
+
+// NewValidateSpecTransformer - creates a transformer validating the transformed specification, add it last
+func NewValidateSpecTransformer() SpecTransformer {
+	return &validateSpecTransformer{}
+}
+
+func (t *validateSpecTransformer) Name() string {
+	return "validate"
+}
+
+func (t *validateSpecTransformer) Transform(doc *SpecDocument, serviceBody *ServiceBody) error {
+	if doc.OAS3 != nil {
+		if err := doc.OAS3.Validate(context.Background()); err != nil {
+			return coreerrors.Wrap(ErrInvalidSpec, err.Error())
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(doc.OAS2)
+	if err != nil {
+		return err
+	}
+	if _, err := oas.ParseOAS2(data); err != nil {
+		return coreerrors.Wrap(ErrInvalidSpec, err.Error())
+	}
+	if doc.OAS2.Host == "" {
+		return coreerrors.Wrap(ErrInvalidSpec, fmt.Sprintf("no host defined in the specification of %s", serviceBody.APIName))
+	}
+	return nil
+}
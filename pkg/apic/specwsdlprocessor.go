@@ -1,7 +1,7 @@
 package apic
 
 import (
-	"net"
+	"errors"
 	"net/url"
 	"strconv"
 
@@ -9,6 +9,8 @@ import (
 	"github.com/Axway/agent-sdk/pkg/util/wsdl"
 )
 
+var wsdlDefaultPorts = map[string]int{"http": 80, "https": 443}
+
 type wsdlProcessor struct {
 	spec *wsdl.Definitions
 }
@@ -21,43 +23,56 @@ func (p *wsdlProcessor) getResourceType() string {
 	return Wsdl
 }
 
+// getEndpoints - returns the endpoints of the ports of all the services, the ports without a location are skipped
 func (p *wsdlProcessor) getEndpoints() ([]EndpointDefinition, error) {
 	endPoints := []EndpointDefinition{}
-	ports := p.spec.Service.Ports
-	for _, val := range ports {
-		loc := val.Address.Location
-		fixed, err := url.Parse(loc)
-		if err != nil {
-			log.Errorf("Error parsing service location in WSDL to get endpoints: %v", err.Error())
-			return nil, err
-		}
-		protocol := fixed.Scheme
-		host := fixed.Hostname()
-		portStr := fixed.Port()
-		if portStr == "" {
-			p, err := net.LookupPort("tcp", protocol)
+	for _, service := range p.spec.Services {
+		for _, val := range service.Ports {
+			loc := val.Address.Location
+			if loc == "" {
+				continue
+			}
+			fixed, err := url.Parse(loc)
 			if err != nil {
-				log.Errorf("Error finding port for endpoint: %v", err.Error())
+				log.Errorf("Error parsing service location in WSDL to get endpoints: %v", err.Error())
 				return nil, err
 			}
-			portStr = strconv.Itoa(p)
-		}
-		port, _ := strconv.Atoi(portStr)
+			protocol := fixed.Scheme
+			// use the default port of the scheme when the location has none
+			port := wsdlDefaultPorts[protocol]
+			if fixed.Port() != "" {
+				port, _ = strconv.Atoi(fixed.Port())
+			}
 
-		endPoint := EndpointDefinition{
-			Host:     host,
-			Port:     int32(port),
-			Protocol: protocol,
-			BasePath: fixed.Path,
-		}
-		if !p.contains(endPoints, endPoint) {
-			endPoints = append(endPoints, endPoint)
+			endPoint := EndpointDefinition{
+				Host:     fixed.Hostname(),
+				Port:     int32(port),
+				Protocol: protocol,
+				BasePath: fixed.Path,
+			}
+			if !p.contains(endPoints, endPoint) {
+				endPoints = append(endPoints, endPoint)
+			}
 		}
 	}
 
 	return endPoints, nil
 }
 
+// GetWSDLOperations - returns the operations of the bindings of a WSDL specification, or zip archive of WSDL and
+// schema documents, with their SOAP action and messages
+func GetWSDLOperations(spec []byte) ([]wsdl.OperationInfo, error) {
+	parser := newSpecResourceParser(spec, Wsdl)
+	if err := parser.parse(); err != nil {
+		return nil, err
+	}
+	processor, ok := parser.getSpecProcessor().(*wsdlProcessor)
+	if !ok {
+		return nil, errors.New("Invalid wsdl specification")
+	}
+	return processor.spec.GetOperations(), nil
+}
+
 func (p *wsdlProcessor) contains(endpts []EndpointDefinition, endpt EndpointDefinition) bool {
 	for _, pt := range endpts {
 		if pt == endpt {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	coreapi "github.com/Axway/agent-sdk/pkg/api"
 	uc "github.com/Axway/agent-sdk/pkg/apic/unifiedcatalog/models"
@@ -195,6 +196,11 @@ func (s *CentralSubscription) getServiceClient() *ServiceClient {
 	return s.apicClient
 }
 
+// getModifyTimestamp - returns the last modify time of the subscription
+func (s *CentralSubscription) getModifyTimestamp() int64 {
+	return time.Time(s.CatalogItemSubscription.Metadata.ModifyTimestamp).UnixNano()
+}
+
 // getSubscriptions -
 func (c *ServiceClient) getSubscriptions(states []string) ([]CentralSubscription, error) {
 	queryParams := make(map[string]string)
@@ -0,0 +1,295 @@
+package apic
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/cache"
+	corecfg "github.com/Axway/agent-sdk/pkg/config"
+	log "github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+// SubscriptionResultStatus - the outcome of a subscription processor
+type SubscriptionResultStatus string
+
+// SubscriptionResultStatus
+const (
+	// SubscriptionProcessed - the transition was processed
+	SubscriptionProcessed = SubscriptionResultStatus("SUCCESS")
+	// SubscriptionRetryable - the transition failed and is processed again after the retry backoff
+	SubscriptionRetryable = SubscriptionResultStatus("RETRYABLE")
+	// SubscriptionPermanentFailure - the transition failed and is not retried, the subscription is set to failed
+	SubscriptionPermanentFailure = SubscriptionResultStatus("PERMANENT_FAILURE")
+)
+
+// SubscriptionResult - the result of a subscription processor
+type SubscriptionResult struct {
+	Status      SubscriptionResultStatus
+	Description string
+}
+
+// SubscriptionSuccess - returns the result of a processed transition
+func SubscriptionSuccess() SubscriptionResult {
+	return SubscriptionResult{Status: SubscriptionProcessed}
+}
+
+// SubscriptionRetry - returns the result of a transition to retry, e.g. the gateway was not reachable
+func SubscriptionRetry(err error) SubscriptionResult {
+	return SubscriptionResult{Status: SubscriptionRetryable, Description: err.Error()}
+}
+
+// SubscriptionFailure - returns the result of a transition that can not succeed
+func SubscriptionFailure(err error) SubscriptionResult {
+	return SubscriptionResult{Status: SubscriptionPermanentFailure, Description: err.Error()}
+}
+
+// SubscriptionTransition - the transition of the subscription to its state, the key is the same for the retries and
+// after a restart, so that the processor can make its changes idempotent
+type SubscriptionTransition struct {
+	Key     string
+	State   SubscriptionState
+	Attempt int
+}
+
+// SubscriptionLifecycleProcessor - callback method type to process a subscription transition, with its result
+type SubscriptionLifecycleProcessor func(subscription Subscription, transition SubscriptionTransition) SubscriptionResult
+
+// transitionStatus - the status of the processing of a transition
+type transitionStatus string
+
+const (
+	transitionProcessing = transitionStatus("processing")
+	transitionRetrying   = transitionStatus("retrying")
+	transitionSucceeded  = transitionStatus("succeeded")
+	transitionFailed     = transitionStatus("failed")
+)
+
+// transitionRecordTTL - the time the transitions are recorded
+const transitionRecordTTL = 7 * 24 * time.Hour
+
+// subscriptionStateFileProperty - the config property of the file the transitions are recorded to
+const subscriptionStateFileProperty = "central.subscriptions.lifecycle.stateFile"
+
+// failedStates - the states the subscriptions are set to when their transition failed
+var failedStates = map[SubscriptionState]SubscriptionState{
+	SubscriptionApproved:             SubscriptionFailedToSubscribe,
+	SubscriptionUnsubscribeInitiated: SubscriptionFailedToUnsubscribe,
+}
+
+// transitionRecord - the record of the processing of a subscription transition
+type transitionRecord struct {
+	IdempotencyKey string           `json:"idempotencyKey"`
+	SubscriptionID string           `json:"subscriptionId"`
+	State          string           `json:"state"`
+	Status         transitionStatus `json:"status"`
+	Attempts       int              `json:"attempts"`
+	NextAttempt    time.Time        `json:"nextAttempt,omitempty"`
+	LastError      string           `json:"lastError,omitempty"`
+	Modified       int64            `json:"modified"` // the modify time of the subscription when the transition started
+	UpdatedAt      time.Time        `json:"updatedAt"`
+}
+
+// modifiedSubscription - the subscriptions with their modify time, a subscription that is modified after its
+// transition completed, in the same state, is a new transition
+type modifiedSubscription interface {
+	getModifyTimestamp() int64
+}
+
+// subscriptionLifecycle - processes the subscription transitions, recording each transition so that a completed
+// transition is not processed again, and retrying the failed transitions with backoff
+type subscriptionLifecycle struct {
+	lock         sync.Mutex
+	store        cache.TTLCache
+	persisted    bool // false when the transitions are recorded in memory only
+	processors   map[SubscriptionState][]SubscriptionLifecycleProcessor
+	maxRetries   int
+	retryBackoff time.Duration
+}
+
+func newSubscriptionLifecycle(cfg corecfg.SubscriptionConfig) *subscriptionLifecycle {
+	l := &subscriptionLifecycle{
+		processors:   make(map[SubscriptionState][]SubscriptionLifecycleProcessor),
+		maxRetries:   cfg.GetLifecycleMaxRetries(),
+		retryBackoff: cfg.GetLifecycleRetryBackoff(),
+	}
+	l.store, l.persisted = newTransitionStore(cfg.GetLifecycleStateFile())
+	return l
+}
+
+// newTransitionStore - creates the store of the transition records, persisted to the state file when set, returns
+// false when the store is in memory only
+func newTransitionStore(stateFile string) (cache.TTLCache, bool) {
+	if stateFile == "" {
+		return cache.New().(cache.TTLCache), false
+	}
+	if dir := filepath.Dir(stateFile); dir != "" {
+		os.MkdirAll(dir, 0700)
+	}
+	backend, err := cache.NewLogBackend(stateFile)
+	if err != nil {
+		log.Error(ErrSubscriptionStateFile.FormatError(stateFile), ": ", err.Error())
+		return cache.New().(cache.TTLCache), false
+	}
+	return cache.New(cache.WithBackend(backend)).(cache.TTLCache), true
+}
+
+// onConfigChange - applies the new retry settings, the transitions are kept in the same store
+func (l *subscriptionLifecycle) onConfigChange(cfg corecfg.SubscriptionConfig) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.maxRetries = cfg.GetLifecycleMaxRetries()
+	l.retryBackoff = cfg.GetLifecycleRetryBackoff()
+}
+
+func (l *subscriptionLifecycle) register(state SubscriptionState, processor SubscriptionLifecycleProcessor) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if len(l.processors) == 0 && !l.persisted {
+		log.Warnf("The subscription transitions are recorded in memory only, the transitions are processed again after a restart. Set %s to persist them", subscriptionStateFileProperty)
+	}
+	l.processors[state] = append(l.processors[state], processor)
+}
+
+func transitionRecordKey(subscriptionID string, state SubscriptionState) string {
+	return subscriptionID + "/" + string(state)
+}
+
+func (l *subscriptionLifecycle) getRecord(key string) *transitionRecord {
+	data, err := l.store.Get(key)
+	if err != nil {
+		return nil
+	}
+	if record, ok := data.(*transitionRecord); ok {
+		return record
+	}
+
+	// loaded from the state file
+	record := &transitionRecord{}
+	buf, _ := json.Marshal(data)
+	if json.Unmarshal(buf, record) != nil {
+		return nil
+	}
+	return record
+}
+
+func (l *subscriptionLifecycle) saveRecord(key string, record transitionRecord) {
+	record.UpdatedAt = time.Now()
+	if err := l.store.SetWithTTL(key, &record, transitionRecordTTL); err != nil {
+		log.Errorf("could not record the transition of subscription %s to %s: %s", record.SubscriptionID, record.State, err.Error())
+	}
+}
+
+// getRetryBackoff - the wait before the retry, doubled on each attempt
+func getRetryBackoff(retryBackoff time.Duration, attempts int) time.Duration {
+	backoff := retryBackoff
+	for i := 1; i < attempts && backoff < time.Hour; i++ {
+		backoff *= 2
+	}
+	return backoff
+}
+
+// process - processes the transition of the subscription to its current state, unless it was already processed or
+// its retry is not due yet
+func (l *subscriptionLifecycle) process(subscription Subscription) {
+	state := subscription.GetState()
+	l.lock.Lock()
+	processors := append([]SubscriptionLifecycleProcessor{}, l.processors[state]...)
+	maxRetries, retryBackoff := l.maxRetries, l.retryBackoff
+	l.lock.Unlock()
+	if len(processors) == 0 {
+		return
+	}
+
+	var modified int64
+	if m, ok := subscription.(modifiedSubscription); ok {
+		modified = m.getModifyTimestamp()
+	}
+
+	key := transitionRecordKey(subscription.GetID(), state)
+	record := l.getRecord(key)
+	if record != nil {
+		switch record.Status {
+		case transitionSucceeded, transitionFailed:
+			if record.Modified == modified {
+				log.Tracef("transition of subscription %s to %s already %s", subscription.GetID(), state, record.Status)
+				return
+			}
+			// the subscription entered the state again
+			record = nil
+		case transitionRetrying:
+			if time.Now().Before(record.NextAttempt) {
+				return
+			}
+		case transitionProcessing:
+			// the agent stopped while processing the transition, the processors are called again with the same key
+			log.Debugf("resuming the transition of subscription %s to %s", subscription.GetID(), state)
+		}
+	}
+	if record == nil {
+		record = &transitionRecord{
+			IdempotencyKey: fmt.Sprintf("%s/%s/%d", subscription.GetID(), state, modified),
+			SubscriptionID: subscription.GetID(),
+			State:          string(state),
+			Modified:       modified,
+		}
+	}
+
+	// record the attempt before calling the processors
+	record.Attempts++
+	record.Status = transitionProcessing
+	l.saveRecord(key, *record)
+
+	transition := SubscriptionTransition{Key: record.IdempotencyKey, State: state, Attempt: record.Attempts}
+	result := l.invoke(processors, subscription, transition)
+	record.LastError = result.Description
+
+	switch {
+	case result.Status == SubscriptionProcessed:
+		record.Status = transitionSucceeded
+	case result.Status == SubscriptionRetryable && record.Attempts <= maxRetries:
+		backoff := getRetryBackoff(retryBackoff, record.Attempts)
+		record.Status = transitionRetrying
+		record.NextAttempt = time.Now().Add(backoff)
+		log.Warnf("processing of subscription %s in %s state failed, retry %d of %d in %s: %s",
+			subscription.GetID(), state, record.Attempts, maxRetries, backoff, result.Description)
+	default:
+		record.Status = transitionFailed
+		l.fail(subscription, record)
+	}
+	l.saveRecord(key, *record)
+}
+
+// invoke - calls the processors, the first failure stops the processing of the transition
+func (l *subscriptionLifecycle) invoke(processors []SubscriptionLifecycleProcessor, subscription Subscription, transition SubscriptionTransition) (result SubscriptionResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = SubscriptionFailure(fmt.Errorf("subscription processor panic: %v", r))
+		}
+	}()
+
+	for _, processor := range processors {
+		result = processor(subscription, transition)
+		if result.Status != SubscriptionProcessed {
+			return result
+		}
+	}
+	return SubscriptionSuccess()
+}
+
+// fail - sets the subscription to the failed state of its transition
+func (l *subscriptionLifecycle) fail(subscription Subscription, record *transitionRecord) {
+	err := ErrSubscriptionProcessing.FormatError(record.Attempts, record.LastError)
+	log.Error(err)
+
+	failedState, ok := failedStates[SubscriptionState(record.State)]
+	if !ok {
+		return
+	}
+	if updateErr := subscription.UpdateState(failedState, err.Error()); updateErr != nil {
+		log.Errorf("could not set subscription %s to %s: %s", subscription.GetID(), failedState, updateErr.Error())
+	}
+}
@@ -0,0 +1,190 @@
+package apic
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corecfg "github.com/Axway/agent-sdk/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type modifiedMockSubscription struct {
+	MockSubscription
+	modified int64
+}
+
+func (s *modifiedMockSubscription) getModifyTimestamp() int64 { return s.modified }
+
+func newTestLifecycle(stateFile string, maxRetries int, retryBackoff time.Duration) *subscriptionLifecycle {
+	cfg := corecfg.NewSubscriptionConfig().(*corecfg.SubscriptionConfiguration)
+	cfg.Lifecycle.StateFile = stateFile
+	cfg.Lifecycle.MaxRetries = maxRetries
+	cfg.Lifecycle.RetryBackoff = retryBackoff
+	return newSubscriptionLifecycle(cfg)
+}
+
+func TestSubscriptionLifecycleSuccess(t *testing.T) {
+	lifecycle := newTestLifecycle("", 2, time.Hour)
+	transitions := make([]SubscriptionTransition, 0)
+	lifecycle.register(SubscriptionApproved, func(subscription Subscription, transition SubscriptionTransition) SubscriptionResult {
+		transitions = append(transitions, transition)
+		return SubscriptionSuccess()
+	})
+
+	subscription := &modifiedMockSubscription{MockSubscription: MockSubscription{ID: "1111", State: SubscriptionApproved}, modified: 1}
+	lifecycle.process(subscription)
+	assert.Len(t, transitions, 1)
+	assert.Equal(t, "1111/APPROVED/1", transitions[0].Key)
+	assert.Equal(t, 1, transitions[0].Attempt)
+
+	// the completed transition is not processed again
+	lifecycle.process(subscription)
+	assert.Len(t, transitions, 1)
+
+	// the subscription entered the state again
+	subscription.modified = 2
+	lifecycle.process(subscription)
+	assert.Len(t, transitions, 2)
+	assert.Equal(t, "1111/APPROVED/2", transitions[1].Key)
+
+	// no processor for the state
+	subscription.State = SubscriptionActive
+	lifecycle.process(subscription)
+	assert.Len(t, transitions, 2)
+}
+
+func TestSubscriptionLifecycleRetries(t *testing.T) {
+	lifecycle := newTestLifecycle("", 2, time.Hour)
+	attempts := 0
+	lifecycle.register(SubscriptionApproved, func(subscription Subscription, transition SubscriptionTransition) SubscriptionResult {
+		attempts++
+		return SubscriptionRetry(fmt.Errorf("gateway unavailable"))
+	})
+
+	subscription := &MockSubscription{ID: "1111", State: SubscriptionApproved}
+	key := transitionRecordKey("1111", SubscriptionApproved)
+	lifecycle.process(subscription)
+	assert.Equal(t, 1, attempts)
+	record := lifecycle.getRecord(key)
+	assert.Equal(t, transitionRetrying, record.Status)
+	assert.Equal(t, "gateway unavailable", record.LastError)
+	assert.True(t, record.NextAttempt.After(time.Now().Add(59*time.Minute)))
+
+	// the retry is not due yet
+	lifecycle.process(subscription)
+	assert.Equal(t, 1, attempts)
+
+	// retried until the retries are exhausted
+	for i := 0; i < 2; i++ {
+		record = lifecycle.getRecord(key)
+		record.NextAttempt = time.Now()
+		lifecycle.saveRecord(key, *record)
+		lifecycle.process(subscription)
+	}
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, transitionFailed, lifecycle.getRecord(key).Status)
+	assert.Equal(t, SubscriptionFailedToSubscribe, subscription.State)
+	assert.Contains(t, subscription.Description, "after 3 attempts: gateway unavailable")
+
+	assert.Equal(t, time.Second, getRetryBackoff(time.Second, 1))
+	assert.Equal(t, 4*time.Second, getRetryBackoff(time.Second, 3))
+}
+
+func TestSubscriptionLifecyclePermanentFailure(t *testing.T) {
+	lifecycle := newTestLifecycle("", 5, time.Hour)
+	called := false
+	lifecycle.register(SubscriptionUnsubscribeInitiated, func(subscription Subscription, transition SubscriptionTransition) SubscriptionResult {
+		return SubscriptionFailure(fmt.Errorf("application not found"))
+	})
+	lifecycle.register(SubscriptionUnsubscribeInitiated, func(subscription Subscription, transition SubscriptionTransition) SubscriptionResult {
+		called = true
+		return SubscriptionSuccess()
+	})
+
+	subscription := &MockSubscription{ID: "1111", State: SubscriptionUnsubscribeInitiated}
+	lifecycle.process(subscription)
+	assert.False(t, called)
+	assert.Equal(t, SubscriptionFailedToUnsubscribe, subscription.State)
+
+	// a panic is a permanent failure
+	lifecycle.register(SubscriptionApproved, func(subscription Subscription, transition SubscriptionTransition) SubscriptionResult {
+		panic("processor bug")
+	})
+	subscription = &MockSubscription{ID: "2222", State: SubscriptionApproved}
+	lifecycle.process(subscription)
+	assert.Equal(t, SubscriptionFailedToSubscribe, subscription.State)
+	assert.Contains(t, subscription.Description, "processor bug")
+}
+
+func TestSubscriptionLifecycleStateFile(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state", "subscriptions.log")
+	keys := make([]string, 0)
+	processor := func(subscription Subscription, transition SubscriptionTransition) SubscriptionResult {
+		keys = append(keys, transition.Key)
+		return SubscriptionSuccess()
+	}
+
+	lifecycle := newTestLifecycle(stateFile, 5, time.Hour)
+	assert.True(t, lifecycle.persisted)
+	lifecycle.register(SubscriptionApproved, processor)
+	lifecycle.process(&MockSubscription{ID: "1111", State: SubscriptionApproved})
+	assert.Len(t, keys, 1)
+
+	// the agent stopped while processing a transition
+	lifecycle.saveRecord(transitionRecordKey("2222", SubscriptionApproved), transitionRecord{
+		IdempotencyKey: "2222/APPROVED/0",
+		SubscriptionID: "2222",
+		State:          string(SubscriptionApproved),
+		Status:         transitionProcessing,
+		Attempts:       1,
+	})
+
+	// after a restart, the completed transition is not processed again, the interrupted one is resumed with its key
+	lifecycle = newTestLifecycle(stateFile, 5, time.Hour)
+	lifecycle.register(SubscriptionApproved, processor)
+	lifecycle.process(&MockSubscription{ID: "1111", State: SubscriptionApproved})
+	assert.Len(t, keys, 1)
+
+	lifecycle.process(&MockSubscription{ID: "2222", State: SubscriptionApproved})
+	assert.Equal(t, []string{"1111/APPROVED/0", "2222/APPROVED/0"}, keys)
+	assert.Equal(t, 2, lifecycle.getRecord(transitionRecordKey("2222", SubscriptionApproved)).Attempts)
+}
+
+func TestSubscriptionLifecycleMemoryStore(t *testing.T) {
+	// without a state file the transitions are recorded in memory
+	lifecycle := newTestLifecycle("", 5, time.Hour)
+	assert.False(t, lifecycle.persisted)
+
+	// the state file can not be opened, the transitions are recorded in memory
+	lifecycle = newTestLifecycle(t.TempDir(), 5, time.Hour)
+	assert.False(t, lifecycle.persisted)
+	lifecycle.register(SubscriptionApproved, func(subscription Subscription, transition SubscriptionTransition) SubscriptionResult {
+		return SubscriptionSuccess()
+	})
+	lifecycle.process(&MockSubscription{ID: "1111", State: SubscriptionApproved})
+	assert.NotNil(t, lifecycle.getRecord(transitionRecordKey("1111", SubscriptionApproved)))
+}
+
+func TestSubscriptionProcessorsWithoutResult(t *testing.T) {
+	sm := &subscriptionManager{
+		processorMap: make(map[SubscriptionState][]SubscriptionProcessor),
+		lifecycle:    newTestLifecycle("", 2, time.Hour),
+	}
+	calls, lifecycleCalls := 0, 0
+	sm.RegisterProcessor(SubscriptionApproved, func(subscription Subscription) { calls++ })
+	sm.RegisterLifecycleProcessor(SubscriptionApproved, func(subscription Subscription, transition SubscriptionTransition) SubscriptionResult {
+		lifecycleCalls++
+		return SubscriptionSuccess()
+	})
+	assert.Equal(t, []string{string(SubscriptionApproved)}, sm.statesToQuery)
+
+	subscription := createSubscription("1111", string(SubscriptionApproved), "", nil).(*CentralSubscription)
+	sm.invokeProcessor(*subscription)
+	sm.invokeProcessor(*subscription)
+
+	// the processors without result are called on each poll, the recorded transition is processed once
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 1, lifecycleCalls)
+}
@@ -1,6 +1,7 @@
 package apic
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 // SubscriptionManager - Interface for subscription manager
 type SubscriptionManager interface {
 	RegisterProcessor(state SubscriptionState, processor SubscriptionProcessor)
+	RegisterLifecycleProcessor(state SubscriptionState, processor SubscriptionLifecycleProcessor)
 	RegisterValidator(validator SubscriptionValidator)
 	Start()
 	Stop()
@@ -32,6 +34,7 @@ type subscriptionManager struct {
 	receiveChannel      chan interface{}
 	receiverQuitChannel chan bool
 	processorMap        map[SubscriptionState][]SubscriptionProcessor
+	lifecycle           *subscriptionLifecycle
 	validator           SubscriptionValidator
 	statesToQuery       []string
 	apicClient          *ServiceClient
@@ -45,13 +48,14 @@ func newSubscriptionManager(apicClient *ServiceClient) SubscriptionManager {
 		isRunning:     false,
 		apicClient:    apicClient,
 		processorMap:  make(map[SubscriptionState][]SubscriptionProcessor),
+		lifecycle:     newSubscriptionLifecycle(apicClient.cfg.GetSubscriptionConfig()),
 		statesToQuery: make([]string, 0),
 		locklist:      make(map[string]string),
 		locklistLock:  &sync.RWMutex{},
 	}
 
 	if apicClient.cfg.GetSubscriptionConfig().PollingEnabled() {
-		_, err := jobs.RegisterIntervalJob(subscriptionMgr, apicClient.cfg.GetPollInterval())
+		_, err := jobs.RegisterIntervalJob(subscriptionMgr, apicClient.cfg.GetPollInterval(), jobs.WithJobName("subscription-manager"))
 		if err != nil {
 			log.Errorf("Error registering interval job to poll for subscriptions: %s", err.Error())
 		}
@@ -63,6 +67,7 @@ func newSubscriptionManager(apicClient *ServiceClient) SubscriptionManager {
 // OnConfigChange - config change handler
 func (sm *subscriptionManager) OnConfigChange(apicClient *ServiceClient) {
 	sm.apicClient = apicClient
+	sm.lifecycle.onConfigChange(apicClient.cfg.GetSubscriptionConfig())
 }
 
 // RegisterCallback - Register subscription processor callback for specified state
@@ -71,10 +76,27 @@ func (sm *subscriptionManager) RegisterProcessor(state SubscriptionState, proces
 	if !ok {
 		processorList = make([]SubscriptionProcessor, 0)
 	}
-	sm.statesToQuery = append(sm.statesToQuery, string(state))
+	sm.addStateToQuery(state)
 	sm.processorMap[state] = append(processorList, processor)
 }
 
+// RegisterLifecycleProcessor - Register subscription processor callback, returning the result of the processing, for
+// specified state. The transitions are recorded, the retryable failures are processed again after the retry backoff
+func (sm *subscriptionManager) RegisterLifecycleProcessor(state SubscriptionState, processor SubscriptionLifecycleProcessor) {
+	sm.addStateToQuery(state)
+	sm.lifecycle.register(state, processor)
+}
+
+// addStateToQuery - adds the state to the states of the subscriptions queried on each poll
+func (sm *subscriptionManager) addStateToQuery(state SubscriptionState) {
+	for _, queried := range sm.statesToQuery {
+		if queried == string(state) {
+			return
+		}
+	}
+	sm.statesToQuery = append(sm.statesToQuery, string(state))
+}
+
 // RegisterValidator - Registers validator for subscription to be processed
 func (sm *subscriptionManager) RegisterValidator(validator SubscriptionValidator) {
 	sm.validator = validator
@@ -92,10 +114,19 @@ func (sm *subscriptionManager) Status() error {
 }
 
 func (sm *subscriptionManager) Execute() error {
+	return sm.ExecuteWithContext(context.Background())
+}
+
+// ExecuteWithContext - queries the subscriptions to process, stops queuing them for processing when the context is done
+func (sm *subscriptionManager) ExecuteWithContext(ctx context.Context) error {
 	subscriptions, err := sm.apicClient.getSubscriptions(sm.statesToQuery)
 	if err == nil {
 		for _, subscription := range subscriptions {
-			sm.publishChannel <- subscription
+			select {
+			case sm.publishChannel <- subscription:
+			case <-ctx.Done():
+				return nil
+			}
 		}
 	}
 	return nil
@@ -199,12 +230,12 @@ func (sm *subscriptionManager) invokeProcessor(subscription CentralSubscription)
 		invokeProcessor = sm.validator(&subscription)
 	}
 	if invokeProcessor {
-		processorList, ok := sm.processorMap[SubscriptionState(subscription.GetState())]
-		if ok {
-			for _, processor := range processorList {
-				processor(&subscription)
-			}
+		// the processors without result are not recorded, they are called on each poll while the subscription is in
+		// their state, so that they can retry their failures
+		for _, processor := range sm.processorMap[SubscriptionState(subscription.GetState())] {
+			processor(&subscription)
 		}
+		sm.lifecycle.process(&subscription)
 	}
 }
 
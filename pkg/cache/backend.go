@@ -0,0 +1,212 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+// compactMinRecords - the log is not compacted before it has this many records
+const compactMinRecords = 1000
+
+// Backend - interface for persisting the cache items, every change to the cache is written to the backend
+type Backend interface {
+	Load() (map[string]*Item, error)
+	Put(key string, item *Item) error
+	Delete(key string) error
+	Clear() error
+	Close() error
+}
+
+// logBackend - persists the cache items in an append-only log file, each change is appended as a record
+// so a crash loses at most the record being written.  The log is compacted, rewritten with the live items only,
+// once most of its records are superseded.
+type logBackend struct {
+	path    string
+	file    *os.File
+	lock    *sync.Mutex
+	records int
+	keys    map[string]struct{}
+}
+
+type logRecord struct {
+	Key  string `json:"key"`
+	Item *Item  `json:"item,omitempty"` // nil when the item was deleted
+}
+
+// NewLogBackend - creates a backend persisting the cache items to the log file at path
+func NewLogBackend(path string) (Backend, error) {
+	l := &logBackend{
+		path: filepath.Clean(path),
+		lock: &sync.Mutex{},
+		keys: make(map[string]struct{}),
+	}
+	err := l.open()
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *logBackend) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	l.file = file
+	return nil
+}
+
+// read - replays the log, a truncated last record is ignored and reported as not clean
+func (l *logBackend) read() (items map[string]*Item, records int, clean bool, err error) {
+	items = make(map[string]*Item)
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		record := logRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			log.Warnf("ignoring the incomplete records at the end of the cache log %s: %s", l.path, err.Error())
+			return items, records, false, nil
+		}
+		records++
+		if record.Item == nil {
+			delete(items, record.Key)
+			continue
+		}
+		items[record.Key] = record.Item
+	}
+	return items, records, true, scanner.Err()
+}
+
+// Load - returns the live items of the log
+func (l *logBackend) Load() (map[string]*Item, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	items, records, clean, err := l.read()
+	if err != nil {
+		return nil, err
+	}
+	l.records = records
+	l.keys = make(map[string]struct{})
+	for key := range items {
+		l.keys[key] = struct{}{}
+	}
+
+	if !clean {
+		// drop the incomplete record, so the appended records are readable
+		err = l.rewrite(items)
+	}
+	return items, err
+}
+
+// Put - appends the item to the log
+func (l *logBackend) Put(key string, item *Item) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.keys[key] = struct{}{}
+	return l.append(logRecord{Key: key, Item: item})
+}
+
+// Delete - appends the deletion of the item to the log
+func (l *logBackend) Delete(key string) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if _, ok := l.keys[key]; !ok {
+		return nil
+	}
+	delete(l.keys, key)
+	return l.append(logRecord{Key: key})
+}
+
+// Clear - removes all items from the log
+func (l *logBackend) Clear() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.keys = make(map[string]struct{})
+	return l.rewrite(map[string]*Item{})
+}
+
+// Close - closes the log file
+func (l *logBackend) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.file.Close()
+}
+
+func (l *logBackend) append(record logRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = l.file.Write(append(data, '\n'))
+	if err != nil {
+		return err
+	}
+	l.records++
+
+	if l.records >= compactMinRecords && l.records > 2*len(l.keys) {
+		return l.compact()
+	}
+	return nil
+}
+
+// compact - rewrites the log with the live items
+func (l *logBackend) compact() error {
+	items, _, _, err := l.read()
+	if err != nil {
+		return fmt.Errorf("error compacting the cache log %s: %s", l.path, err.Error())
+	}
+	return l.rewrite(items)
+}
+
+// rewrite - replaces the log with a log of the items, the new log is written aside and renamed
+// so the log is never left partially written
+func (l *logBackend) rewrite(items map[string]*Item) error {
+	tmpPath := l.path + ".tmp"
+	tmpFile, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(tmpFile)
+	encoder := json.NewEncoder(writer)
+	for key, item := range items {
+		if err = encoder.Encode(logRecord{Key: key, Item: item}); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = tmpFile.Sync()
+	}
+	tmpFile.Close()
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	l.file.Close()
+	if err = os.Rename(tmpPath, l.path); err != nil {
+		l.open()
+		return err
+	}
+	l.records = len(items)
+	return l.open()
+}
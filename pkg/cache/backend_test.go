@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogBackend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.log")
+	backend, err := NewLogBackend(path)
+	assert.Nil(t, err)
+
+	cache := New(WithBackend(backend))
+	cache.Set("key1", "data1")
+	cache.SetWithSecondaryKey("key2", "secKey2", map[string]interface{}{"name": "data2"})
+	cache.(TTLCache).SetWithTTL("expired", "data", time.Millisecond)
+	cache.Set("deleted", "data")
+	cache.Delete("deleted")
+	assert.Nil(t, backend.Close())
+
+	// simulate a crash while writing a record
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	file.WriteString(`{"key":"partial","item":{"da`)
+	file.Close()
+
+	time.Sleep(5 * time.Millisecond)
+	backend, err = NewLogBackend(path)
+	assert.Nil(t, err)
+	cache = New(WithBackend(backend))
+	assert.ElementsMatch(t, []string{"key1", "key2"}, cache.GetKeys())
+	data, err := cache.GetBySecondaryKey("secKey2")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "data2"}, data)
+
+	// records appended after the recovered log are readable
+	cache.Set("key3", "data3")
+	cache.DeleteSecondaryKey("secKey2")
+	assert.Nil(t, backend.Close())
+	backend, _ = NewLogBackend(path)
+	cache = New(WithBackend(backend))
+	assert.ElementsMatch(t, []string{"key1", "key2", "key3"}, cache.GetKeys())
+	_, err = cache.GetBySecondaryKey("secKey2")
+	assert.NotNil(t, err)
+
+	cache.Flush()
+	assert.Nil(t, backend.Close())
+	backend, _ = NewLogBackend(path)
+	cache = New(WithBackend(backend), WithMaxItems(5))
+	assert.Len(t, cache.GetKeys(), 0)
+
+	// the log is compacted to the live items
+	for i := 0; i < compactMinRecords; i++ {
+		cache.Set(fmt.Sprintf("key%d", i), i)
+	}
+	logBackend := backend.(*logBackend)
+	assert.True(t, logBackend.records < compactMinRecords, "The log should have been compacted")
+	assert.Nil(t, backend.Close())
+
+	backend, _ = NewLogBackend(path)
+	cache = New(WithBackend(backend))
+	assert.Len(t, cache.GetKeys(), 5)
+	_, err = cache.Get(fmt.Sprintf("key%d", compactMinRecords-1))
+	assert.Nil(t, err)
+	assert.Nil(t, backend.Close())
+}
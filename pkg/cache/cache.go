@@ -2,17 +2,23 @@ package cache
 
 import (
 	"bytes"
+	"container/list"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
 	util "github.com/Axway/agent-sdk/pkg/util"
+	"github.com/Axway/agent-sdk/pkg/util/log"
 )
 
+// expiredSweepInterval - the minimum interval between the removals of all expired items
+const expiredSweepInterval = time.Second
+
 var globalCache Cache
 
 // Cache - Interface for managing the proxy cache
@@ -35,12 +41,59 @@ type Cache interface {
 	Load(path string) error
 }
 
+// TTLCache - Interface for a cache that sets the time to live of each item, the caches created by New implement it
+type TTLCache interface {
+	Cache
+	SetWithTTL(key string, data interface{}, ttl time.Duration) error
+}
+
 // itemCache
 type itemCache struct {
-	Items       map[string]*Item  `json:"cache"`
-	SecKeys     map[string]string `json:"secondaryKeys"`
-	itemsLock   *sync.RWMutex     // Use lock when making changes/reading the items map
-	secKeysLock *sync.RWMutex     // Use lock when making changes/reading the secKeys map
+	Items         map[string]*Item  `json:"cache"`
+	SecKeys       map[string]string `json:"secondaryKeys"`
+	itemsLock     *sync.RWMutex     // Use lock when making changes/reading the items map
+	secKeysLock   *sync.RWMutex     // Use lock when making changes/reading the secKeys map
+	ttl           time.Duration
+	maxItems      int
+	evictionTopic string
+	backend       Backend
+	lru           *list.List               // keys ordered from the most to the least recently used, when the cache is bounded
+	lruItems      map[string]*list.Element // Use lruLock when making changes/reading the lru list and map
+	lruLock       *sync.Mutex
+	expiring      bool // true once an item with a ttl was set
+	lastSweep     time.Time
+}
+
+// Option - configures the cache on creation
+type Option func(*itemCache)
+
+// WithTTL - items set without a ttl expire after the ttl
+func WithTTL(ttl time.Duration) Option {
+	return func(c *itemCache) {
+		c.ttl = ttl
+	}
+}
+
+// WithMaxItems - bounds the cache, the least recently used items are evicted when the max is exceeded
+func WithMaxItems(maxItems int) Option {
+	return func(c *itemCache) {
+		c.maxItems = maxItems
+	}
+}
+
+// WithEvictionTopic - evictions of expired and least recently used items are published as Eviction
+// to the PubSub topic, the topic has to be created with CreateTopic
+func WithEvictionTopic(topic string) Option {
+	return func(c *itemCache) {
+		c.evictionTopic = topic
+	}
+}
+
+// WithBackend - persists the cache changes to the backend, the cache is loaded from the backend on creation
+func WithBackend(backend Backend) Option {
+	return func(c *itemCache) {
+		c.backend = backend
+	}
 }
 
 func init() {
@@ -61,33 +114,97 @@ func GetCache() Cache {
 }
 
 // New - create a new cache object
-func New() Cache {
-	newCache := &itemCache{
-		Items:       make(map[string]*Item),
-		SecKeys:     make(map[string]string),
-		itemsLock:   &sync.RWMutex{},
-		secKeysLock: &sync.RWMutex{},
+func New(opts ...Option) Cache {
+	newCache := newItemCache(opts...)
+	if newCache.backend != nil {
+		items, err := newCache.backend.Load()
+		if err != nil {
+			log.Errorf("error loading the cache from its backend, starting with an empty cache: %s", err.Error())
+		}
+		if items != nil {
+			newCache.Items = items
+		}
+		newCache.rebuild()
 	}
 	return newCache
 }
 
 // Load - create a new cache object and load saved data
-func Load(path string) Cache {
+func Load(path string, opts ...Option) Cache {
+	newCache := newItemCache(opts...)
+	newCache.Load(path)
+	return newCache
+}
+
+func newItemCache(opts ...Option) *itemCache {
 	newCache := &itemCache{
 		Items:       make(map[string]*Item),
 		SecKeys:     make(map[string]string),
 		itemsLock:   &sync.RWMutex{},
 		secKeysLock: &sync.RWMutex{},
+		lruLock:     &sync.Mutex{},
+	}
+	for _, opt := range opts {
+		opt(newCache)
+	}
+	if newCache.maxItems > 0 {
+		newCache.lru = list.New()
+		newCache.lruItems = make(map[string]*list.Element)
 	}
-	newCache.Load(path)
 	return newCache
 }
 
+// rebuild - rebuilds the secondary keys and usage of the loaded items, dropping the expired items
+// and the least recently updated items over the max
+func (c *itemCache) rebuild() {
+	c.itemsLock.Lock()
+	defer c.itemsLock.Unlock()
+	c.secKeysLock.Lock()
+	defer c.secKeysLock.Unlock()
+	c.lruLock.Lock()
+	defer c.lruLock.Unlock()
+
+	now := time.Now()
+	keys := make([]string, 0, len(c.Items))
+	for key, item := range c.Items {
+		if item.isExpired(now) {
+			delete(c.Items, key)
+			c.backendDelete(key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return c.Items[keys[i]].UpdateTime > c.Items[keys[j]].UpdateTime
+	})
+	if c.maxItems > 0 && len(keys) > c.maxItems {
+		for _, key := range keys[c.maxItems:] {
+			delete(c.Items, key)
+			c.backendDelete(key)
+		}
+		keys = keys[:c.maxItems]
+	}
+
+	c.SecKeys = make(map[string]string)
+	c.resetUsage()
+	for i := len(keys) - 1; i >= 0; i-- {
+		key := keys[i]
+		item := c.Items[key]
+		if item.ExpireTime > 0 {
+			c.expiring = true
+		}
+		if item.SecondaryKeys == nil {
+			item.SecondaryKeys = make(map[string]bool)
+		}
+		for secKey := range item.SecondaryKeys {
+			c.SecKeys[secKey] = key
+		}
+		c.touch(key)
+	}
+}
+
 // check the current hash vs the newHash, return true if it has changed
 func (c *itemCache) hasItemChanged(key string, data interface{}) (bool, error) {
-	c.itemsLock.RLock()
-	defer c.itemsLock.RUnlock()
-
 	// Get the current item by key
 	item, err := c.get(key)
 	if err != nil {
@@ -110,12 +227,133 @@ func (c *itemCache) hasItemChanged(key string, data interface{}) (bool, error) {
 // returns the entire item, if found
 func (c *itemCache) get(key string) (*Item, error) {
 	c.itemsLock.RLock()
-	defer c.itemsLock.RUnlock()
+	item, ok := c.Items[key]
+	expired := ok && item.isExpired(time.Now())
+	if ok && !expired {
+		c.lruLock.Lock()
+		c.touch(key)
+		c.lruLock.Unlock()
+	}
+	c.itemsLock.RUnlock()
+
+	if expired {
+		c.expire(key)
+	}
+	if !ok || expired {
+		return nil, fmt.Errorf("Could not find item with key: %s", key)
+	}
+	return item, nil
+}
+
+// expire - removes the item when it is expired
+func (c *itemCache) expire(key string) {
+	c.itemsLock.Lock()
+	item, ok := c.Items[key]
+	if !ok || !item.isExpired(time.Now()) {
+		c.itemsLock.Unlock()
+		return
+	}
+	c.removeItem(key)
+	c.itemsLock.Unlock()
+
+	c.publishEvictions([]Eviction{{Key: key, Item: item, Reason: EvictionExpired}})
+}
+
+// evict - removes the expired items and the least recently used items over the max, the items lock has to be held
+func (c *itemCache) evict() []Eviction {
+	evictions := []Eviction{}
+
+	now := time.Now()
+	if c.expiring && now.Sub(c.lastSweep) >= expiredSweepInterval {
+		c.lastSweep = now
+		for key, item := range c.Items {
+			if item.isExpired(now) {
+				c.removeItem(key)
+				evictions = append(evictions, Eviction{Key: key, Item: item, Reason: EvictionExpired})
+			}
+		}
+	}
+
+	if c.maxItems <= 0 {
+		return evictions
+	}
+	for len(c.Items) > c.maxItems {
+		c.lruLock.Lock()
+		key := c.lru.Back().Value.(string)
+		c.lruLock.Unlock()
+
+		item := c.Items[key]
+		c.removeItem(key)
+		evictions = append(evictions, Eviction{Key: key, Item: item, Reason: EvictionCapacity})
+	}
+	return evictions
+}
+
+// publishEvictions - publishes the evictions to the eviction topic, the cache locks may not be held
+func (c *itemCache) publishEvictions(evictions []Eviction) {
+	if c.evictionTopic == "" {
+		return
+	}
+	for _, eviction := range evictions {
+		publishEviction(c.evictionTopic, eviction)
+	}
+}
+
+// touch - marks the key as the most recently used, the lru lock has to be held
+func (c *itemCache) touch(key string) {
+	if c.lru == nil {
+		return
+	}
+	if elem, ok := c.lruItems[key]; ok {
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.lruItems[key] = c.lru.PushFront(key)
+}
 
-	if item, ok := c.Items[key]; ok {
-		return item, nil
+// resetUsage - clears the usage of all keys, the lru lock has to be held
+func (c *itemCache) resetUsage() {
+	if c.lru == nil {
+		return
+	}
+	c.lru.Init()
+	c.lruItems = make(map[string]*list.Element)
+}
+
+// removeItem - removes the item, its secondary keys and usage, the items lock has to be held
+func (c *itemCache) removeItem(key string) {
+	// Remove all secondary keys
+	for secKey := range c.Items[key].SecondaryKeys {
+		c.removeSecondaryKey(secKey)
+	}
+	delete(c.Items, key)
+
+	c.lruLock.Lock()
+	if c.lru != nil {
+		if elem, ok := c.lruItems[key]; ok {
+			c.lru.Remove(elem)
+			delete(c.lruItems, key)
+		}
+	}
+	c.lruLock.Unlock()
+
+	c.backendDelete(key)
+}
+
+func (c *itemCache) backendPut(key string, item *Item) error {
+	if c.backend == nil {
+		return nil
+	}
+	return c.backend.Put(key, item)
+}
+
+func (c *itemCache) backendDelete(key string) {
+	if c.backend == nil {
+		return
+	}
+	if err := c.backend.Delete(key); err != nil {
+		log.Errorf("error removing the cache item with key %s from the cache backend: %s", key, err.Error())
 	}
-	return nil, fmt.Errorf("Could not find item with key: %s", key)
 }
 
 // returns the primary key based on the secondary key
@@ -131,30 +369,46 @@ func (c *itemCache) findPrimaryKey(secondaryKey string) (string, error) {
 }
 
 // set the Item object to the key specified, updates the hash
-func (c *itemCache) set(key string, data interface{}) error {
-	c.itemsLock.Lock()
-	defer c.itemsLock.Unlock()
-
+func (c *itemCache) set(key string, data interface{}, ttl time.Duration) error {
 	hash, err := util.ComputeHash(data)
 	if err != nil {
 		return err
 	}
 
+	c.itemsLock.Lock()
 	secKeys := make(map[string]bool)
 	if _, ok := c.Items[key]; ok {
 		secKeys = c.Items[key].SecondaryKeys
 	}
-	c.Items[key] = &Item{
+	now := time.Now()
+	item := &Item{
 		Object:        data,
-		UpdateTime:    time.Now().Unix(),
+		UpdateTime:    now.Unix(),
 		Hash:          hash,
 		SecondaryKeys: secKeys,
 	}
-	return nil
+	if ttl > 0 {
+		item.ExpireTime = now.Add(ttl).UnixNano()
+		c.expiring = true
+	}
+	c.Items[key] = item
+	err = c.backendPut(key, item)
+
+	c.lruLock.Lock()
+	c.touch(key)
+	c.lruLock.Unlock()
+
+	evictions := c.evict()
+	c.itemsLock.Unlock()
+
+	c.publishEvictions(evictions)
+	return err
 }
 
 // set the secondaryKey for the key given
 func (c *itemCache) setSecondaryKey(key string, secondaryKey string) error {
+	c.itemsLock.Lock()
+	defer c.itemsLock.Unlock()
 	c.secKeysLock.Lock()
 	defer c.secKeysLock.Unlock()
 
@@ -168,9 +422,6 @@ func (c *itemCache) setSecondaryKey(key string, secondaryKey string) error {
 		return fmt.Errorf("Can't use %s as a secondary key, it is already a secondary key", secondaryKey)
 	}
 
-	c.itemsLock.Lock()
-	defer c.itemsLock.Unlock()
-
 	item, ok := c.Items[key]
 	// Check that the key given is in the cache
 	if !ok {
@@ -179,7 +430,7 @@ func (c *itemCache) setSecondaryKey(key string, secondaryKey string) error {
 
 	c.SecKeys[secondaryKey] = key
 	item.SecondaryKeys[secondaryKey] = true
-	return nil
+	return c.backendPut(key, item)
 }
 
 // delete an item from the cache
@@ -191,12 +442,7 @@ func (c *itemCache) delete(key string) error {
 		return fmt.Errorf("Cache item with key %s does not exist", key)
 	}
 
-	// Remove all secondary keys
-	for secKey := range c.Items[key].SecondaryKeys {
-		c.removeSecondaryKey(secKey)
-	}
-
-	delete(c.Items, key)
+	c.removeItem(key)
 	return nil
 }
 
@@ -205,7 +451,15 @@ func (c *itemCache) deleteSecondaryKey(secondaryKey string) error {
 	c.itemsLock.Lock()
 	defer c.itemsLock.Unlock()
 
-	return c.removeSecondaryKey(secondaryKey)
+	key, err := c.findPrimaryKey(secondaryKey)
+	if err != nil {
+		return err
+	}
+	err = c.removeSecondaryKey(secondaryKey)
+	if err != nil {
+		return err
+	}
+	return c.backendPut(key, c.Items[key])
 }
 
 //removeSecondaryKey - removes a secondary key reference in the cache
@@ -232,6 +486,16 @@ func (c *itemCache) flush() {
 
 	c.SecKeys = make(map[string]string)
 	c.Items = make(map[string]*Item)
+
+	c.lruLock.Lock()
+	c.resetUsage()
+	c.lruLock.Unlock()
+
+	if c.backend != nil {
+		if err := c.backend.Clear(); err != nil {
+			log.Errorf("error clearing the cache backend: %s", err.Error())
+		}
+	}
 }
 
 func (c *itemCache) save(path string) error {
@@ -256,19 +520,23 @@ func (c *itemCache) save(path string) error {
 }
 
 func (c *itemCache) load(path string) error {
-	c.secKeysLock.Lock()
-	defer c.secKeysLock.Unlock()
-	c.itemsLock.Lock()
-	defer c.itemsLock.Unlock()
-
 	file, err := os.Open(filepath.Clean(path))
 	if err != nil {
 		return err
 	}
 
+	c.itemsLock.Lock()
+	c.secKeysLock.Lock()
 	err = json.NewDecoder(file).Decode(c)
+	c.secKeysLock.Unlock()
+	c.itemsLock.Unlock()
 	file.Close()
-	return err
+	if err != nil {
+		return err
+	}
+
+	c.rebuild()
+	return nil
 }
 
 // Get - return the object in the cache
@@ -315,9 +583,15 @@ func (c *itemCache) GetItemBySecondaryKey(secondaryKey string) (*Item, error) {
 
 // GetKeys - Returns the keys in cache
 func (c *itemCache) GetKeys() []string {
+	c.itemsLock.RLock()
+	defer c.itemsLock.RUnlock()
+
+	now := time.Now()
 	keys := []string{}
-	for key := range c.Items {
-		keys = append(keys, key)
+	for key, item := range c.Items {
+		if !item.isExpired(now) {
+			keys = append(keys, key)
+		}
 	}
 	return keys
 }
@@ -338,14 +612,19 @@ func (c *itemCache) HasItemBySecondaryKeyChanged(secondaryKey string, data inter
 	return c.hasItemChanged(key, data)
 }
 
-// Set - Create a new item, or update an existing item, in the cache with key
+// Set - Create a new item, or update an existing item, in the cache with key.  The item expires after the ttl of the cache
 func (c *itemCache) Set(key string, data interface{}) error {
-	return c.set(key, data)
+	return c.set(key, data, c.ttl)
+}
+
+// SetWithTTL - Create a new item, or update an existing item, in the cache with key.  The item expires after the ttl
+func (c *itemCache) SetWithTTL(key string, data interface{}, ttl time.Duration) error {
+	return c.set(key, data, ttl)
 }
 
 // SetSecondaryKey - Create a new item in the cache with key and a secondaryKey reference
 func (c *itemCache) SetWithSecondaryKey(key string, secondaryKey string, data interface{}) error {
-	err := c.set(key, data)
+	err := c.set(key, data, c.ttl)
 	if err != nil {
 		return err
 	}
@@ -3,6 +3,7 @@ package cache
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -316,3 +317,89 @@ func TestSaveAndLoad(t *testing.T) {
 	cache.Set(key1, "key1 val2")
 	assert.NotEqual(t, cache.(*itemCache).Items[key1].Object, cache2.(*itemCache).Items[key1].Object, "Updating the oringal cache seemed to have changed the loaded cache")
 }
+
+func TestCacheTTL(t *testing.T) {
+	cache := New(WithTTL(50 * time.Millisecond))
+
+	cache.Set("default", "data")
+	cache.(TTLCache).SetWithTTL("short", "data", 10*time.Millisecond)
+	cache.(TTLCache).SetWithTTL("long", "data", time.Hour)
+	cache.SetWithSecondaryKey("secondary", "secKey", "data")
+	item, _ := cache.GetItem("long")
+	assert.NotZero(t, item.GetExpireTime())
+	assert.Len(t, cache.GetKeys(), 4)
+
+	time.Sleep(20 * time.Millisecond)
+	_, err := cache.Get("short")
+	assert.NotNil(t, err, "The item should have expired")
+	_, err = cache.Get("default")
+	assert.Nil(t, err)
+	assert.Len(t, cache.GetKeys(), 3)
+
+	time.Sleep(40 * time.Millisecond)
+	assert.ElementsMatch(t, []string{"long"}, cache.GetKeys())
+	_, err = cache.GetBySecondaryKey("secKey")
+	assert.NotNil(t, err, "The item should have expired")
+	assert.Len(t, cache.(*itemCache).SecKeys, 0, "The secondary key of the expired item should have been removed")
+
+	// items set without a ttl on a cache without a ttl do not expire
+	cache = New()
+	cache.Set("key", "data")
+	item, _ = cache.GetItem("key")
+	assert.Zero(t, item.GetExpireTime())
+}
+
+func TestCacheMaxItems(t *testing.T) {
+	cache := New(WithMaxItems(2))
+
+	cache.Set("key1", "data1")
+	cache.SetWithSecondaryKey("key2", "secKey2", "data2")
+	// key1 becomes the most recently used
+	cache.Get("key1")
+	cache.Set("key3", "data3")
+
+	assert.ElementsMatch(t, []string{"key1", "key3"}, cache.GetKeys())
+	_, err := cache.GetBySecondaryKey("secKey2")
+	assert.NotNil(t, err, "The least recently used item should have been evicted")
+
+	cache.Set("key4", "data4")
+	assert.ElementsMatch(t, []string{"key3", "key4"}, cache.GetKeys())
+
+	cache.Delete("key3")
+	cache.Set("key5", "data5")
+	assert.ElementsMatch(t, []string{"key4", "key5"}, cache.GetKeys())
+
+	cache.Flush()
+	cache.Set("key6", "data6")
+	assert.ElementsMatch(t, []string{"key6"}, cache.GetKeys())
+}
+
+func TestCacheEvictionTopic(t *testing.T) {
+	topic := "evictions"
+	pubsub, err := CreateTopic(topic)
+	assert.Nil(t, err)
+	defer RemoveTopic(topic)
+
+	evictions := make(chan Eviction, 2)
+	pubsub.SubscribeWithCallback(func(data interface{}) {
+		if eviction, ok := data.(Eviction); ok {
+			evictions <- eviction
+		}
+	})
+
+	cache := New(WithMaxItems(1), WithEvictionTopic(topic))
+	cache.(TTLCache).SetWithTTL("key1", "data1", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	_, err = cache.Get("key1")
+	assert.NotNil(t, err)
+	cache.Set("key2", "data2")
+	cache.Set("key3", "data3")
+
+	eviction := <-evictions
+	assert.Equal(t, "key1", eviction.Key)
+	assert.Equal(t, EvictionExpired, eviction.Reason)
+	eviction = <-evictions
+	assert.Equal(t, "key2", eviction.Key)
+	assert.Equal(t, "data2", eviction.Item.GetObject())
+	assert.Equal(t, EvictionCapacity, eviction.Reason)
+}
@@ -0,0 +1,17 @@
+package cache
+
+// EvictionReason - the reason an item was evicted from the cache
+type EvictionReason string
+
+// Eviction reasons
+const (
+	EvictionExpired  EvictionReason = "expired"  // the ttl of the item passed
+	EvictionCapacity EvictionReason = "capacity" // the item was the least recently used when the max items was exceeded
+)
+
+// Eviction - published to the eviction topic of the cache when an item is evicted
+type Eviction struct {
+	Key    string
+	Item   *Item
+	Reason EvictionReason
+}
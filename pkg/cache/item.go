@@ -1,11 +1,14 @@
 package cache
 
+import "time"
+
 // Item - a cached item
 type Item struct {
 	Object        interface{}     `json:"data"`
 	UpdateTime    int64           `json:"updateTime"`
 	Hash          uint64          `json:"hash"`
-	SecondaryKeys map[string]bool `json:"secondaryKeys"` // keep track of secondary keys for clean up
+	SecondaryKeys map[string]bool `json:"secondaryKeys"`        // keep track of secondary keys for clean up
+	ExpireTime    int64           `json:"expireTime,omitempty"` // epoch time in nanoseconds, 0 when the item does not expire
 }
 
 // GetObject - returns the object saved in this cache item
@@ -22,3 +25,12 @@ func (i *Item) GetUpdateTime() int64 {
 func (i *Item) GetHash() uint64 {
 	return i.Hash
 }
+
+// GetExpireTime - returns the epoch time, in nanoseconds, that this cache item expires, 0 if it does not expire
+func (i *Item) GetExpireTime() int64 {
+	return i.ExpireTime
+}
+
+func (i *Item) isExpired(now time.Time) bool {
+	return i.ExpireTime > 0 && now.UnixNano() >= i.ExpireTime
+}
@@ -220,3 +220,12 @@ func (c *cachePubSub) SubscribeWithCallback(callback func(data interface{})) str
 
 	return subscriber.GetID()
 }
+
+// publishEviction - sends the eviction to the subscribers of the topic, when the topic exists
+func publishEviction(topic string, eviction Eviction) {
+	cPubSub, ok := topics[topic]
+	if !ok {
+		return
+	}
+	cPubSub.channel <- eviction
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/Axway/agent-sdk/pkg/agent"
 	v1 "github.com/Axway/agent-sdk/pkg/apic/apiserver/models/api/v1"
 	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
 	corecfg "github.com/Axway/agent-sdk/pkg/config"
@@ -684,3 +685,36 @@ func TestRootCmdHandlerWithSecretRefProperties(t *testing.T) {
 	assert.Equal(t, "secretValue2", agentCfg.sProp)
 	assert.Equal(t, true, cmdHandlerInvoked)
 }
+
+func TestRootCmdDryRun(t *testing.T) {
+	rootCmd := NewRootCmd("Test", "TestRootCmd", nil, nil, corecfg.TraceabilityAgent)
+	assert.Nil(t, getPFlag(rootCmd, DryRunFlag), "dry run is only supported by discovery agents")
+
+	handled := false
+	cmdHandler := func() error {
+		handled = true
+		return nil
+	}
+	rootCmd = NewRootCmd("Test", "TestRootCmd", nil, cmdHandler, corecfg.DiscoveryAgent)
+	assertCmdFlag(t, rootCmd, DryRunFlag, "bool", "Print the changes the agent would make in AMPLIFY Central, then exit without making them")
+	assertCmdFlag(t, rootCmd, DryRunWaitFlag, "duration", "Time the agent discovers APIs in dry run mode before the plan is printed")
+	c := rootCmd.(*agentRootCommand)
+	defer agent.SetDryRunMode(false)
+
+	rootCmd.RootCmd().Flags().Set(DryRunFlag, "true")
+	rootCmd.RootCmd().Flags().Set(DryRunFormatFlag, "yaml")
+	assert.NotNil(t, c.setDryRunMode())
+
+	rootCmd.RootCmd().Flags().Set(DryRunFormatFlag, "json")
+	rootCmd.RootCmd().Flags().Set(DryRunWaitFlag, "1m")
+	assert.Nil(t, c.setDryRunMode())
+	assert.True(t, agent.IsDryRunMode())
+	assert.Equal(t, time.Minute, c.dryRunWait)
+
+	buf := &bytes.Buffer{}
+	dryRunOutput = buf
+	defer func() { dryRunOutput = os.Stdout }()
+	assert.Equal(t, 0, c.runDryRun())
+	assert.True(t, handled)
+	assert.Equal(t, "[]\n", buf.String())
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/agent"
+	"github.com/Axway/agent-sdk/pkg/config"
+	log "github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+// Constants for the dry run flags
+const (
+	DryRunFlag       = "dry-run"
+	DryRunFormatFlag = "dry-run-format"
+	DryRunWaitFlag   = "dry-run-wait"
+
+	defaultDryRunWait = 30 * time.Second
+)
+
+var dryRunOutput io.Writer = os.Stdout
+
+// addDryRunProps - adds the dry run flags for discovery agents
+func (c *agentRootCommand) addDryRunProps() {
+	if c.agentType != config.DiscoveryAgent {
+		return
+	}
+	c.props.AddBoolFlag(DryRunFlag, "Print the changes the agent would make in AMPLIFY Central, then exit without making them")
+	c.props.AddStringFlag(DryRunFormatFlag, "Format of the dry run plan, table (default) or json")
+	c.props.AddDurationFlag(DryRunWaitFlag, defaultDryRunWait, "Time the agent discovers APIs in dry run mode before the plan is printed")
+}
+
+// setDryRunMode - checks the dry run flags, the mode is set before the agent is initialized
+func (c *agentRootCommand) setDryRunMode() error {
+	dryRun := c.props.BoolFlagValue(DryRunFlag)
+	agent.SetDryRunMode(dryRun)
+	if !dryRun {
+		return nil
+	}
+
+	_, c.dryRunFormat = c.props.StringFlagValue(DryRunFormatFlag)
+	if c.dryRunFormat != "" && c.dryRunFormat != agent.DryRunFormatTable && c.dryRunFormat != agent.DryRunFormatJSON {
+		return agent.ErrDryRunPlanFormat.FormatError(c.dryRunFormat)
+	}
+
+	c.dryRunWait = c.props.DurationFlagValue(DryRunWaitFlag)
+	return nil
+}
+
+// runDryRun - runs the command handler until it returns or the dry run wait elapsed, then prints the
+// recorded plan.  Returns the exit code.
+func (c *agentRootCommand) runDryRun() int {
+	log.Infof("Starting %s (%s) in dry run mode, no changes are made in AMPLIFY Central", c.rootCmd.Short, c.rootCmd.Version)
+	exitcode := 0
+	if c.commandHandler != nil {
+		done := make(chan error, 1)
+		go func() {
+			done <- c.commandHandler()
+		}()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				log.Error(err.Error())
+				exitcode = 1
+			}
+		case <-time.After(c.dryRunWait):
+		}
+	}
+
+	err := agent.WriteDryRunPlan(dryRunOutput, c.dryRunFormat)
+	if err != nil {
+		log.Error(err.Error())
+		return 1
+	}
+	return exitcode
+}
@@ -0,0 +1,142 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	corefilter "github.com/Axway/agent-sdk/pkg/filter"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// Output formats of the filter test command
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// Sample - a set of tags and attributes the filter is tested against
+type Sample struct {
+	Name       string                 `json:"name,omitempty" yaml:"name,omitempty"`
+	Tags       map[string]interface{} `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+}
+
+// SampleResult - the evaluation of the filter against a sample
+type SampleResult struct {
+	Name        string                  `json:"name"`
+	Match       bool                    `json:"match"`
+	Explanation *corefilter.Explanation `json:"explanation"`
+}
+
+// GenFilterCmd - generates the filter command, with the test sub command evaluating a filter against sample data
+func GenFilterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "filter",
+		Short: "Validate discovery filters",
+	}
+
+	testCmd := &cobra.Command{
+		Use:   "test <filter> <file>",
+		Short: "Evaluate the filter against the tag sets in a JSON or YAML file and explain the results",
+		Long: "Evaluate the filter against the tag sets in a JSON or YAML file and explain the results.\n" +
+			"The file holds a list of samples, each with a name, tags and attributes, e.g.\n" +
+			"- name: petstore\n  tags:\n    version: \"2.1\"\n  attributes:\n    internal: \"true\"",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString("output")
+			return testFilter(cmd.OutOrStdout(), args[0], args[1], output)
+		},
+	}
+	testCmd.Flags().StringP("output", "o", outputText, "Output format of the results (text, json)")
+	cmd.AddCommand(testCmd)
+	return cmd
+}
+
+func testFilter(w io.Writer, filterConfig, file, output string) error {
+	if output != outputText && output != outputJSON {
+		return fmt.Errorf("unknown output format %s, expected text or json", output)
+	}
+
+	agentFilter, err := corefilter.NewFilter(filterConfig)
+	if err != nil {
+		return err
+	}
+
+	samples, err := readSamples(file)
+	if err != nil {
+		return err
+	}
+
+	results := make([]SampleResult, 0, len(samples))
+	for i, sample := range samples {
+		name := sample.Name
+		if name == "" {
+			name = fmt.Sprintf("sample %d", i+1)
+		}
+		explanation := agentFilter.Explain(toFilterData(sample.Tags), toFilterData(sample.Attributes))
+		results = append(results, SampleResult{
+			Name:        name,
+			Match:       explanation.Result,
+			Explanation: explanation,
+		})
+	}
+
+	if output == outputJSON {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	for _, result := range results {
+		match := "MATCH"
+		if !result.Match {
+			match = "NO MATCH"
+		}
+		fmt.Fprintf(w, "%s: %s\n", result.Name, match)
+		for _, line := range strings.Split(strings.TrimSuffix(result.Explanation.String(), "\n"), "\n") {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+	return nil
+}
+
+// readSamples - reads the samples from the JSON or YAML file, JSON being valid YAML
+func readSamples(file string) ([]Sample, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	samples := []Sample{}
+	err = yaml.Unmarshal(data, &samples)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the samples in %s: %s", file, err.Error())
+	}
+	return samples, nil
+}
+
+// toFilterData - converts the sample values to the strings and string lists the filter evaluates
+func toFilterData(values map[string]interface{}) map[string]interface{} {
+	data := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		switch v := value.(type) {
+		case nil:
+			data[key] = ""
+		case []interface{}:
+			list := make([]string, 0, len(v))
+			for _, item := range v {
+				list = append(list, fmt.Sprint(item))
+			}
+			data[key] = list
+		default:
+			data[key] = fmt.Sprint(v)
+		}
+	}
+	return data
+}
@@ -0,0 +1,45 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterTestCmd(t *testing.T) {
+	filterConfig := "tag.version >= \"2.0\" && !attr.internal.Exists()"
+
+	buf := &bytes.Buffer{}
+	cmd := GenFilterCmd()
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"test", filterConfig, "./testdata/samples.yaml"})
+	assert.Nil(t, cmd.Execute())
+	out := buf.String()
+	assert.Contains(t, out, "petstore: MATCH")
+	assert.Contains(t, out, "internal-orders: NO MATCH")
+	assert.Contains(t, out, "sample 3: NO MATCH")
+	assert.Contains(t, out, "[tag.version = \"1.9\"]")
+
+	buf.Reset()
+	cmd = GenFilterCmd()
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"test", filterConfig, "./testdata/samples.json", "--output", "json"})
+	assert.Nil(t, cmd.Execute())
+	results := []SampleResult{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &results))
+	assert.Len(t, results, 2)
+	assert.True(t, results[0].Match)
+	assert.False(t, results[1].Match)
+	assert.Len(t, results[0].Explanation.Conditions, 2)
+
+	// list tags are evaluated as comma separated values
+	buf.Reset()
+	assert.Nil(t, testFilter(buf, "tag.env.Contains(\"test\")", "./testdata/samples.yaml", outputText))
+	assert.Contains(t, buf.String(), "petstore: MATCH")
+
+	assert.NotNil(t, testFilter(buf, "tag.version = 1", "./testdata/samples.yaml", outputText))
+	assert.NotNil(t, testFilter(buf, filterConfig, "./testdata/missing.yaml", outputText))
+	assert.NotNil(t, testFilter(buf, filterConfig, "./testdata/samples.yaml", "xml"))
+}
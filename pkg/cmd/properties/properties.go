@@ -36,6 +36,7 @@ type Properties interface {
 	AddIntProperty(name string, defaultVal int, description string)
 	AddBoolProperty(name string, defaultVal bool, description string)
 	AddBoolFlag(name, description string)
+	AddDurationFlag(name string, defaultVal time.Duration, description string)
 	AddStringSliceProperty(name string, defaultVal []string, description string)
 
 	// Methods to get the configured properties
@@ -45,6 +46,7 @@ type Properties interface {
 	IntPropertyValue(name string) int
 	BoolPropertyValue(name string) bool
 	BoolFlagValue(name string) bool
+	DurationFlagValue(name string) time.Duration
 	StringSlicePropertyValue(name string) []string
 
 	// Log Properties
@@ -179,6 +181,12 @@ func (p *properties) AddBoolFlag(flagName string, description string) {
 	}
 }
 
+func (p *properties) AddDurationFlag(flagName string, defaultVal time.Duration, description string) {
+	if p.rootCmd != nil {
+		p.rootCmd.Flags().Duration(flagName, defaultVal, description)
+	}
+}
+
 func (p *properties) StringSlicePropertyValue(name string) []string {
 	val := viper.Get(name)
 
@@ -312,6 +320,15 @@ func (p *properties) BoolFlagValue(name string) bool {
 	return false
 }
 
+func (p *properties) DurationFlagValue(name string) time.Duration {
+	flag := p.rootCmd.Flag(name)
+	if flag == nil {
+		return 0
+	}
+	d, _ := time.ParseDuration(flag.Value.String())
+	return d
+}
+
 func (p *properties) nameToFlagName(name string) (flagName string) {
 	parts := strings.Split(name, ".")
 	flagName = parts[0]
@@ -0,0 +1,101 @@
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Axway/agent-sdk/pkg/agent"
+	coreapi "github.com/Axway/agent-sdk/pkg/api"
+	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
+	"github.com/Axway/agent-sdk/pkg/cache"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+const (
+	secretConfigPrefix  = "@Secret."
+	secretMapItemPrefix = "SecretResource_"
+)
+
+// centralSecretResolver - resolves the @Secret.name.key references with the Secret resources of Amplify Central
+type centralSecretResolver struct {
+	secretsCache cache.Cache
+}
+
+func newCentralSecretResolver() *centralSecretResolver {
+	return &centralSecretResolver{
+		secretsCache: cache.New(),
+	}
+}
+
+func (s *centralSecretResolver) Scheme() string {
+	return secretConfigPrefix
+}
+
+// ready - the secret references can not be resolved until central config is parsed and initialized
+func (s *centralSecretResolver) ready() bool {
+	cfg := agent.GetCentralConfig()
+	return cfg != nil && !reflect.ValueOf(cfg).IsNil()
+}
+
+// reset - flushes the cached secrets, the cache is kept as the secrets can be resolved at the same time
+func (s *centralSecretResolver) reset() {
+	s.secretsCache.Flush()
+}
+
+// parseSecretRef - parses the secret reference with secret name and key, name.key
+func (s *centralSecretResolver) parseSecretRef(secretRef string) (string, string) {
+	secretRefElements := strings.Split(secretRef, ".")
+	if len(secretRefElements) > 1 {
+		return secretRefElements[0], strings.Join(secretRefElements[1:], ".")
+	}
+	return "", ""
+}
+
+func (s *centralSecretResolver) getSecret(secretName string) (*v1alpha1.Secret, error) {
+	secretResourceURL := agent.GetCentralConfig().GetEnvironmentURL() + "/secrets/" + secretName
+
+	response, err := agent.GetCentralClient().ExecuteAPI(coreapi.GET, secretResourceURL, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	secret := &v1alpha1.Secret{}
+	err = json.Unmarshal(response, secret)
+	return secret, err
+}
+
+func (s *centralSecretResolver) parseKeyValueFromSecretSpec(secret *v1alpha1.Secret, key string) (string, error) {
+	// Return empty string if secret key not found
+	keyVal, ok := secret.Spec.Data[key]
+	if !ok {
+		msg := fmt.Sprintf("key %s not found in secret %s", key, secret.Name)
+		return "", errors.New(msg)
+	}
+	return keyVal, nil
+}
+
+func (s *centralSecretResolver) Resolve(secretRef string) (string, error) {
+	secretName, key := s.parseSecretRef(secretRef)
+	if secretName == "" || key == "" {
+		return "", fmt.Errorf("invalid secret reference %s%s, expected %sname.key", secretConfigPrefix, secretRef, secretConfigPrefix)
+	}
+
+	var secret *v1alpha1.Secret
+	// Get cached secret to resolve key
+	cachedSecret, err := s.secretsCache.Get(secretMapItemPrefix + secretName)
+	if err != nil {
+		// Secret not cached, get the secret from API server
+		secret, err = s.getSecret(secretName)
+		if err != nil {
+			log.Trace(err.Error())
+			msg := fmt.Sprintf("unable to resolve secret %s", secretName)
+			return "", errors.New(msg)
+		}
+		s.secretsCache.Set(secretMapItemPrefix+secret.GetName(), secret)
+	} else {
+		secret, _ = cachedSecret.(*v1alpha1.Secret)
+	}
+	return s.parseKeyValueFromSecretSpec(secret, key)
+}
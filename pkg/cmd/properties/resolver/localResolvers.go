@@ -0,0 +1,52 @@
+package resolver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+const (
+	fileSecretPrefix = "@file:"
+	envSecretPrefix  = "@env:"
+)
+
+type fileResolver struct{}
+
+// NewFileResolver - creates the resolver of the @file:path references, the value is the content of the file without
+// its trailing new lines, e.g. a secret mounted by kubernetes
+func NewFileResolver() SchemeResolver {
+	return &fileResolver{}
+}
+
+func (r *fileResolver) Scheme() string {
+	return fileSecretPrefix
+}
+
+func (r *fileResolver) Resolve(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the secret file %s: %s", path, err.Error())
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+type envResolver struct{}
+
+// NewEnvResolver - creates the resolver of the @env:NAME references, the value is the environment variable
+func NewEnvResolver() SchemeResolver {
+	return &envResolver{}
+}
+
+func (r *envResolver) Scheme() string {
+	return envSecretPrefix
+}
+
+func (r *envResolver) Resolve(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s not set", name)
+	}
+	return value, nil
+}
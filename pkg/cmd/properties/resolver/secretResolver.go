@@ -1,114 +1,256 @@
 package resolver
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
-	"reflect"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/Axway/agent-sdk/pkg/agent"
-	coreapi "github.com/Axway/agent-sdk/pkg/api"
-	"github.com/Axway/agent-sdk/pkg/apic/apiserver/models/management/v1alpha1"
-	"github.com/Axway/agent-sdk/pkg/cache"
 	"github.com/Axway/agent-sdk/pkg/cmd/properties"
+	"github.com/Axway/agent-sdk/pkg/jobs"
 	"github.com/Axway/agent-sdk/pkg/util/log"
 )
 
-const (
-	secretConfigPrefix  = "@Secret."
-	secretMapItemPrefix = "SecretResource_"
-)
+// SchemeResolver - Interface to resolve the secret references starting with its scheme, e.g. @file:
+type SchemeResolver interface {
+	// Scheme - returns the prefix of the references resolved
+	Scheme() string
+	// Resolve - returns the value of the reference, without its scheme
+	Resolve(reference string) (string, error)
+}
+
+// SecretChangeHandler - Callback for the change of resolved secret values, with the references that changed
+type SecretChangeHandler func(references []string)
 
 // SecretResolver - Interface to resolve secret reference
 type SecretResolver interface {
 	properties.SecretPropertyResolver
 	ResetResolver()
+	// RegisterResolver - registers the resolver of a scheme, replacing the resolver of the same scheme
+	RegisterResolver(resolver SchemeResolver)
+	// SetRefreshInterval - sets the time after which the resolved values are resolved again, 0 to cache them until reset
+	SetRefreshInterval(interval time.Duration)
+	// OnSecretChange - registers the handler called when refreshed values changed
+	OnSecretChange(handler SecretChangeHandler)
+	// Refresh - resolves the expired values again, returns the references whose value changed
+	Refresh() []string
+	// StartRefresh - starts the job refreshing the values at the refresh interval
+	StartRefresh() error
+}
+
+// resettable - the resolvers caching the values they read, the cache is dropped before refreshing the values
+type resettable interface {
+	reset()
+}
+
+// readiness - the resolvers that can not resolve the references yet, e.g. before the central config is initialized
+type readiness interface {
+	ready() bool
+}
+
+type resolvedSecret struct {
+	value      string
+	resolvedAt time.Time
 }
 
 type secretResolver struct {
 	SecretResolver
-	secretsCache cache.Cache
+	lock            sync.Mutex
+	resolvers       map[string]SchemeResolver
+	resolved        map[string]resolvedSecret
+	refreshInterval time.Duration
+	changeHandler   SecretChangeHandler
+	generation      int // incremented on reset, the values resolved before the reset are not cached
+	refreshLock     sync.Mutex
+	refreshJobID    string
 }
 
-// NewSecretResolver - create a new secret resolver
+// NewSecretResolver - create a new secret resolver, with the @Secret., @file: and @env: resolvers
 func NewSecretResolver() SecretResolver {
-	return &secretResolver{
-		secretsCache: cache.New(),
+	s := &secretResolver{
+		resolvers: make(map[string]SchemeResolver),
+		resolved:  make(map[string]resolvedSecret),
 	}
+	s.RegisterResolver(newCentralSecretResolver())
+	s.RegisterResolver(NewFileResolver())
+	s.RegisterResolver(NewEnvResolver())
+	return s
 }
 
-// parseSecretRef - parses the secret reference with prefixed secret name ane key
-func (s *secretResolver) parseSecretRef(secretRef string) (string, string) {
-	// parse secret and key from @Secret.secretName.key
-	secretRef = strings.TrimSpace(secretRef)
-	if strings.HasPrefix(secretRef, secretConfigPrefix) {
-		secretRef = secretRef[len(secretConfigPrefix):]
-		secretRefElements := strings.Split(secretRef, ".")
-		if len(secretRefElements) > 1 {
-			return secretRefElements[0], strings.Join(secretRefElements[1:], ".")
+func (s *secretResolver) RegisterResolver(resolver SchemeResolver) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.resolvers[resolver.Scheme()] = resolver
+}
+
+func (s *secretResolver) SetRefreshInterval(interval time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.refreshInterval = interval
+}
+
+func (s *secretResolver) OnSecretChange(handler SecretChangeHandler) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.changeHandler = handler
+}
+
+// getResolver - returns the resolver of the scheme of the reference, and the reference without its scheme
+func (s *secretResolver) getResolver(secretRef string) (SchemeResolver, string) {
+	for scheme, resolver := range s.resolvers {
+		if strings.HasPrefix(secretRef, scheme) {
+			return resolver, secretRef[len(scheme):]
 		}
 	}
-	return "", ""
+	return nil, ""
 }
 
-func (s *secretResolver) getSecret(secretName string) (*v1alpha1.Secret, error) {
-	secretResourceURL := agent.GetCentralConfig().GetEnvironmentURL() + "/secrets/" + secretName
+// ResolveSecret - returns the value of the reference, the lock is not held while the value is read from the secret
+// store so a slow store does not block the other references
+func (s *secretResolver) ResolveSecret(secretRef string) (string, error) {
+	secretRef = strings.TrimSpace(secretRef)
+	s.lock.Lock()
+	resolver, reference := s.getResolver(secretRef)
+	cached, isCached := s.resolved[secretRef]
+	isCached = isCached && !s.isExpired(cached)
+	generation := s.generation
+	s.lock.Unlock()
+
+	if resolver == nil {
+		// Not a secret ref, return it as value
+		return secretRef, nil
+	}
+	if r, ok := resolver.(readiness); ok && !r.ready() {
+		return secretRef, nil
+	}
+	if isCached {
+		return cached.value, nil
+	}
 
-	response, err := agent.GetCentralClient().ExecuteAPI(coreapi.GET, secretResourceURL, nil, nil)
+	value, err := resolver.Resolve(reference)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	secret := &v1alpha1.Secret{}
-	err = json.Unmarshal(response, secret)
-	return secret, err
-}
 
-func (s *secretResolver) parseKeyValueFromSecretSpec(secret *v1alpha1.Secret, key string) (string, error) {
-	// Return empty string if secret key not found
-	keyVal, ok := secret.Spec.Data[key]
-	if !ok {
-		msg := fmt.Sprintf("key %s not found in secret %s", key, secret.Name)
-		return "", errors.New(msg)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.generation == generation {
+		s.resolved[secretRef] = resolvedSecret{value: value, resolvedAt: time.Now()}
 	}
-	return keyVal, nil
+	return value, nil
 }
 
-func (s *secretResolver) ResolveSecret(secretRef string) (string, error) {
-	// Do not parse secret reference until central config is parsed and initialized
-	// secret ref be applied to agent config only and not central config
-	cfg := agent.GetCentralConfig()
-	if cfg == nil || reflect.ValueOf(cfg).IsNil() {
-		return secretRef, nil
+func (s *secretResolver) isExpired(cached resolvedSecret) bool {
+	return s.refreshInterval > 0 && time.Since(cached.resolvedAt) >= s.refreshInterval
+}
+
+// expiredSecret - a resolved secret to refresh, with its previous value
+type expiredSecret struct {
+	resolver  SchemeResolver
+	reference string
+	value     string
+}
+
+func (s *secretResolver) Refresh() []string {
+	s.lock.Lock()
+	for _, resolver := range s.resolvers {
+		if r, ok := resolver.(resettable); ok {
+			r.reset()
+		}
+	}
+	expired := make(map[string]expiredSecret)
+	for secretRef, cached := range s.resolved {
+		if !s.isExpired(cached) {
+			continue
+		}
+		if resolver, reference := s.getResolver(secretRef); resolver != nil {
+			expired[secretRef] = expiredSecret{resolver: resolver, reference: reference, value: cached.value}
+		}
 	}
+	generation := s.generation
+	handler := s.changeHandler
+	s.lock.Unlock()
 
-	secretName, key := s.parseSecretRef(secretRef)
-	if secretName != "" && key != "" {
-		var secret *v1alpha1.Secret
-		// Get cached secret to resolve key
-		cachedSecret, err := s.secretsCache.Get(secretMapItemPrefix + secretName)
+	// the values are read without the lock, the secret stores can be slow
+	refreshed := make(map[string]resolvedSecret)
+	for secretRef, secret := range expired {
+		value, err := secret.resolver.Resolve(secret.reference)
 		if err != nil {
-			// Secret not cached, get the secret from API server
-			secret, err = s.getSecret(secretName)
-			if err != nil {
-				log.Trace(err.Error())
-				msg := fmt.Sprintf("unable to resolve secret %s", secretName)
-				return "", errors.New(msg)
+			// keep the previous value, the secret store may be temporarily unavailable
+			log.Warnf("could not refresh the secret %s: %s", secretRef, err.Error())
+			continue
+		}
+		refreshed[secretRef] = resolvedSecret{value: value, resolvedAt: time.Now()}
+	}
+
+	changed := make([]string, 0)
+	s.lock.Lock()
+	if s.generation == generation {
+		for secretRef, resolved := range refreshed {
+			if resolved.value != expired[secretRef].value {
+				changed = append(changed, secretRef)
 			}
-			s.secretsCache.Set(secretMapItemPrefix+secret.GetName(), secret)
-		} else {
-			secret, _ = cachedSecret.(*v1alpha1.Secret)
+			s.resolved[secretRef] = resolved
 		}
-		keyVal, err := s.parseKeyValueFromSecretSpec(secret, key)
-		if err != nil {
-			return "", err
+	}
+	s.lock.Unlock()
+
+	sort.Strings(changed)
+	if len(changed) > 0 {
+		log.Infof("the values of the secrets %s changed", strings.Join(changed, ", "))
+		if handler != nil {
+			handler(changed)
 		}
-		return keyVal, nil
 	}
-	// Not a secret ref, return it as value
-	return secretRef, nil
+	return changed
+}
+
+func (s *secretResolver) StartRefresh() error {
+	// not the resolver lock, the running refresh takes it before the job can be unregistered
+	s.refreshLock.Lock()
+	defer s.refreshLock.Unlock()
+	if s.refreshJobID != "" {
+		jobs.UnregisterJob(s.refreshJobID)
+		s.refreshJobID = ""
+	}
+	s.lock.Lock()
+	interval := s.refreshInterval
+	s.lock.Unlock()
+	if interval <= 0 {
+		return nil
+	}
+
+	var err error
+	s.refreshJobID, err = jobs.RegisterIntervalJob(&secretRefreshJob{resolver: s}, interval, jobs.WithJobName("secret-refresh"), jobs.WithFailurePolicy(jobs.FailurePolicyIsolate))
+	return err
 }
 
 func (s *secretResolver) ResetResolver() {
-	s.secretsCache = cache.New()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.resolved = make(map[string]resolvedSecret)
+	s.generation++
+	for _, resolver := range s.resolvers {
+		if r, ok := resolver.(resettable); ok {
+			r.reset()
+		}
+	}
+}
+
+// secretRefreshJob - the job refreshing the resolved secrets
+type secretRefreshJob struct {
+	resolver SecretResolver
+}
+
+func (j *secretRefreshJob) Ready() bool {
+	return true
+}
+
+func (j *secretRefreshJob) Status() error {
+	return nil
+}
+
+func (j *secretRefreshJob) Execute() error {
+	j.resolver.Refresh()
+	return nil
 }
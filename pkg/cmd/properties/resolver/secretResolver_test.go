@@ -0,0 +1,210 @@
+package resolver
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveLocalSecrets(t *testing.T) {
+	s := NewSecretResolver()
+
+	// not a reference
+	value, err := s.ResolveSecret("value")
+	assert.Nil(t, err)
+	assert.Equal(t, "value", value)
+
+	// central secret before the central config is initialized
+	value, err = s.ResolveSecret("@Secret.name.key")
+	assert.Nil(t, err)
+	assert.Equal(t, "@Secret.name.key", value)
+
+	// file
+	secretFile := filepath.Join(t.TempDir(), "password")
+	ioutil.WriteFile(secretFile, []byte("filesecret\n"), 0600)
+	value, err = s.ResolveSecret("@file:" + secretFile)
+	assert.Nil(t, err)
+	assert.Equal(t, "filesecret", value)
+
+	_, err = s.ResolveSecret("@file:" + secretFile + ".missing")
+	assert.NotNil(t, err)
+
+	// env
+	os.Setenv("TEST_SECRET_RESOLVER", "envsecret")
+	defer os.Unsetenv("TEST_SECRET_RESOLVER")
+	value, err = s.ResolveSecret("@env:TEST_SECRET_RESOLVER")
+	assert.Nil(t, err)
+	assert.Equal(t, "envsecret", value)
+
+	_, err = s.ResolveSecret("@env:TEST_SECRET_RESOLVER_MISSING")
+	assert.NotNil(t, err)
+}
+
+func TestRefreshSecrets(t *testing.T) {
+	s := NewSecretResolver()
+	s.SetRefreshInterval(time.Millisecond)
+
+	var changedRefs []string
+	s.OnSecretChange(func(references []string) {
+		changedRefs = references
+	})
+
+	secretFile := filepath.Join(t.TempDir(), "password")
+	ioutil.WriteFile(secretFile, []byte("first"), 0600)
+	value, err := s.ResolveSecret("@file:" + secretFile)
+	assert.Nil(t, err)
+	assert.Equal(t, "first", value)
+
+	// not changed
+	time.Sleep(2 * time.Millisecond)
+	assert.Len(t, s.Refresh(), 0)
+	assert.Nil(t, changedRefs)
+
+	// rotated
+	ioutil.WriteFile(secretFile, []byte("second"), 0600)
+	time.Sleep(2 * time.Millisecond)
+	assert.Equal(t, []string{"@file:" + secretFile}, s.Refresh())
+	assert.Equal(t, []string{"@file:" + secretFile}, changedRefs)
+	value, _ = s.ResolveSecret("@file:" + secretFile)
+	assert.Equal(t, "second", value)
+
+	// removed, the previous value is kept
+	os.Remove(secretFile)
+	time.Sleep(2 * time.Millisecond)
+	assert.Len(t, s.Refresh(), 0)
+
+	// cached until reset when no refresh interval
+	s.SetRefreshInterval(0)
+	ioutil.WriteFile(secretFile, []byte("third"), 0600)
+	value, _ = s.ResolveSecret("@file:" + secretFile)
+	assert.Equal(t, "second", value)
+	s.ResetResolver()
+	value, _ = s.ResolveSecret("@file:" + secretFile)
+	assert.Equal(t, "third", value)
+}
+
+// blockingResolver - resolves the references once released, like a slow secret store
+type blockingResolver struct {
+	release chan struct{}
+}
+
+func (r *blockingResolver) Scheme() string {
+	return "@blocking:"
+}
+
+func (r *blockingResolver) Resolve(reference string) (string, error) {
+	<-r.release
+	return reference, nil
+}
+
+func TestResolveSecretWithoutLock(t *testing.T) {
+	s := NewSecretResolver()
+	blocking := &blockingResolver{release: make(chan struct{})}
+	s.RegisterResolver(blocking)
+
+	resolved := make(chan string)
+	go func() {
+		value, _ := s.ResolveSecret("@blocking:slow")
+		resolved <- value
+	}()
+
+	// the other references are resolved while the slow store is read
+	os.Setenv("TEST_SECRET_RESOLVER", "envsecret")
+	defer os.Unsetenv("TEST_SECRET_RESOLVER")
+	done := make(chan struct{})
+	go func() {
+		value, err := s.ResolveSecret("@env:TEST_SECRET_RESOLVER")
+		assert.Nil(t, err)
+		assert.Equal(t, "envsecret", value)
+		s.Refresh()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the secret resolver is blocked by the slow secret store")
+	}
+
+	close(blocking.release)
+	assert.Equal(t, "slow", <-resolved)
+}
+
+func TestVaultResolver(t *testing.T) {
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			body := map[string]string{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["role_id"] != "role" || body["secret_id"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			logins++
+			w.Write([]byte(`{"auth":{"client_token":"approletoken","lease_duration":3600}}`))
+		case "/v1/secret/data/agent":
+			token := r.Header.Get("X-Vault-Token")
+			if token != "token" && token != "approletoken" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			assert.Equal(t, "ns", r.Header.Get("X-Vault-Namespace"))
+			w.Write([]byte(`{"data":{"data":{"password":"kv2secret"},"metadata":{"version":1}}}`))
+		case "/v1/kv/agent":
+			w.Write([]byte(`{"data":{"password":"kv1secret"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// token
+	s := NewSecretResolver()
+	s.RegisterResolver(NewVaultResolver(&config.VaultConfiguration{URL: server.URL, Namespace: "ns", Token: "token"}, nil))
+	value, err := s.ResolveSecret("@vault:secret/data/agent#password")
+	assert.Nil(t, err)
+	assert.Equal(t, "kv2secret", value)
+
+	value, err = s.ResolveSecret("@vault:kv/agent#password")
+	assert.Nil(t, err)
+	assert.Equal(t, "kv1secret", value)
+
+	_, err = s.ResolveSecret("@vault:secret/data/agent#missing")
+	assert.NotNil(t, err)
+	_, err = s.ResolveSecret("@vault:secret/data/other#password")
+	assert.NotNil(t, err)
+	_, err = s.ResolveSecret("@vault:secret/data/agent")
+	assert.NotNil(t, err)
+
+	// bad token
+	s = NewSecretResolver()
+	s.RegisterResolver(NewVaultResolver(&config.VaultConfiguration{URL: server.URL, Namespace: "ns", Token: "bad"}, nil))
+	_, err = s.ResolveSecret("@vault:secret/data/agent#password")
+	assert.NotNil(t, err)
+
+	// app role, the token is reused
+	s = NewSecretResolver()
+	s.RegisterResolver(NewVaultResolver(&config.VaultConfiguration{URL: server.URL, Namespace: "ns", RoleID: "role", SecretID: "secret", AuthPath: "approle"}, nil))
+	value, err = s.ResolveSecret("@vault:secret/data/agent#password")
+	assert.Nil(t, err)
+	assert.Equal(t, "kv2secret", value)
+	s.ResetResolver()
+	value, err = s.ResolveSecret("@vault:secret/data/agent#password")
+	assert.Nil(t, err)
+	assert.Equal(t, "kv2secret", value)
+	assert.Equal(t, 1, logins)
+
+	// bad app role
+	s = NewSecretResolver()
+	s.RegisterResolver(NewVaultResolver(&config.VaultConfiguration{URL: server.URL, Namespace: "ns", RoleID: "role", SecretID: "bad", AuthPath: "approle"}, nil))
+	_, err = s.ResolveSecret("@vault:secret/data/agent#password")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,167 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	coreapi "github.com/Axway/agent-sdk/pkg/api"
+	"github.com/Axway/agent-sdk/pkg/config"
+)
+
+const (
+	vaultSecretPrefix = "@vault:"
+	vaultTokenHeader  = "X-Vault-Token"
+	vaultNSHeader     = "X-Vault-Namespace"
+)
+
+type vaultResolver struct {
+	cfg         config.VaultConfig
+	client      coreapi.Client
+	lock        sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// vaultSecret - the response of a KV read, the values are in data.data with the KV version 2 and in data with version 1
+type vaultSecret struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+type vaultLogin struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+	} `json:"auth"`
+}
+
+// NewVaultResolver - creates the resolver of the @vault:path#key references, reading the key of the secret at path
+// in the Vault KV store, authenticated with the token or with the AppRole of the config
+func NewVaultResolver(cfg config.VaultConfig, client coreapi.Client) SchemeResolver {
+	if client == nil {
+		client = coreapi.NewClient(nil, "")
+	}
+	return &vaultResolver{
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+func (r *vaultResolver) Scheme() string {
+	return vaultSecretPrefix
+}
+
+func (r *vaultResolver) url(path string) string {
+	return strings.TrimSuffix(r.cfg.GetURL(), "/") + "/v1/" + strings.TrimPrefix(path, "/")
+}
+
+func (r *vaultResolver) headers(token string) map[string]string {
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+	if token != "" {
+		headers[vaultTokenHeader] = token
+	}
+	if r.cfg.GetNamespace() != "" {
+		headers[vaultNSHeader] = r.cfg.GetNamespace()
+	}
+	return headers
+}
+
+// getToken - returns the configured token, or the token of the AppRole login, login again when it expired
+func (r *vaultResolver) getToken(forceLogin bool) (string, error) {
+	if r.cfg.GetToken() != "" {
+		return r.cfg.GetToken(), nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !forceLogin && r.token != "" && (r.tokenExpiry.IsZero() || time.Now().Before(r.tokenExpiry)) {
+		return r.token, nil
+	}
+
+	body, _ := json.Marshal(map[string]string{
+		"role_id":   r.cfg.GetRoleID(),
+		"secret_id": r.cfg.GetSecretID(),
+	})
+	response, err := r.client.Send(coreapi.Request{
+		Method:  coreapi.POST,
+		URL:     r.url("auth/" + strings.Trim(r.cfg.GetAuthPath(), "/") + "/login"),
+		Headers: r.headers(""),
+		Body:    body,
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to login to vault: %s", err.Error())
+	}
+	if response.Code != http.StatusOK {
+		return "", fmt.Errorf("unable to login to vault, status code %d", response.Code)
+	}
+
+	login := &vaultLogin{}
+	if err := json.Unmarshal(response.Body, login); err != nil || login.Auth.ClientToken == "" {
+		return "", fmt.Errorf("unable to login to vault, no client token in the response")
+	}
+	r.token = login.Auth.ClientToken
+	r.tokenExpiry = time.Time{}
+	if login.Auth.LeaseDuration > 0 {
+		// login again a little before the token expires
+		lease := time.Duration(login.Auth.LeaseDuration) * time.Second
+		r.tokenExpiry = time.Now().Add(lease - lease/10)
+	}
+	return r.token, nil
+}
+
+func (r *vaultResolver) read(path string, forceLogin bool) (*coreapi.Response, error) {
+	token, err := r.getToken(forceLogin)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.Send(coreapi.Request{
+		Method:  coreapi.GET,
+		URL:     r.url(path),
+		Headers: r.headers(token),
+	})
+}
+
+func (r *vaultResolver) Resolve(reference string) (string, error) {
+	elements := strings.SplitN(reference, "#", 2)
+	if len(elements) != 2 || elements[0] == "" || elements[1] == "" {
+		return "", fmt.Errorf("invalid secret reference %s%s, expected %spath#key", vaultSecretPrefix, reference, vaultSecretPrefix)
+	}
+	path, key := elements[0], elements[1]
+
+	response, err := r.read(path, false)
+	if err == nil && response.Code == http.StatusForbidden && r.cfg.GetToken() == "" {
+		// the AppRole token was revoked or expired, login again
+		response, err = r.read(path, true)
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read the vault secret %s: %s", path, err.Error())
+	}
+	if response.Code != http.StatusOK {
+		return "", fmt.Errorf("unable to read the vault secret %s, status code %d", path, response.Code)
+	}
+
+	secret := &vaultSecret{}
+	if err := json.Unmarshal(response.Body, secret); err != nil {
+		return "", fmt.Errorf("unable to read the vault secret %s: %s", path, err.Error())
+	}
+	data := secret.Data
+	if kv2, ok := data["data"].(map[string]interface{}); ok {
+		if _, isMetadata := data["metadata"]; isMetadata {
+			data = kv2
+		}
+	}
+
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in vault secret %s", key, path)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"net/url"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/Axway/agent-sdk/pkg/agent"
 	"github.com/Axway/agent-sdk/pkg/cmd/agentsync"
+	"github.com/Axway/agent-sdk/pkg/cmd/filter"
 	"github.com/Axway/agent-sdk/pkg/cmd/properties"
 	"github.com/Axway/agent-sdk/pkg/cmd/properties/resolver"
 	"github.com/Axway/agent-sdk/pkg/config"
@@ -48,6 +50,7 @@ type AgentRootCmd interface {
 	AddCommand(*cobra.Command)
 
 	GetProperties() properties.Properties
+	GetSecretResolver() resolver.SecretResolver
 }
 
 // agentRootCommand - Represents the agent root command
@@ -62,6 +65,8 @@ type agentRootCommand struct {
 	centralCfg        config.CentralConfig
 	agentCfg          interface{}
 	secretResolver    resolver.SecretResolver
+	dryRunFormat      string
+	dryRunWait        time.Duration
 }
 
 func init() {
@@ -92,8 +97,15 @@ func NewRootCmd(exeName, desc string, initConfigHandler InitConfigHandler, comma
 	c.addBaseProps()
 	config.AddLogConfigProperties(c.props, fmt.Sprintf("%s.log", exeName))
 	agentsync.AddSyncConfigProperties(c.props)
+	c.addDryRunProps()
 	config.AddCentralConfigProperties(c.props, agentType)
 	config.AddStatusConfigProperties(c.props)
+	config.AddSecretConfigProperties(c.props)
+
+	// Discovery agents can test their filters
+	if agentType == config.DiscoveryAgent {
+		c.rootCmd.AddCommand(filter.GenFilterCmd())
+	}
 
 	hc.SetNameAndVersion(exeName, c.rootCmd.Version)
 
@@ -125,8 +137,15 @@ func NewCmd(rootCmd *cobra.Command, exeName, desc string, initConfigHandler Init
 	c.addBaseProps()
 	config.AddLogConfigProperties(c.props, fmt.Sprintf("%s.log", exeName))
 	agentsync.AddSyncConfigProperties(c.props)
+	c.addDryRunProps()
 	config.AddCentralConfigProperties(c.props, agentType)
 	config.AddStatusConfigProperties(c.props)
+	config.AddSecretConfigProperties(c.props)
+
+	// Discovery agents can test their filters
+	if agentType == config.DiscoveryAgent {
+		c.rootCmd.AddCommand(filter.GenFilterCmd())
+	}
 
 	hc.SetNameAndVersion(exeName, c.rootCmd.Version)
 
@@ -175,7 +194,7 @@ func (c *agentRootCommand) initialize(cmd *cobra.Command, args []string) error {
 
 	c.checkStatusFlag()
 	agentsync.SetSyncMode(c.GetProperties())
-	return nil
+	return c.setDryRunMode()
 }
 
 func (c *agentRootCommand) checkStatusFlag() {
@@ -221,6 +240,12 @@ func (c *agentRootCommand) initConfig() error {
 		return err
 	}
 
+	// Init the secret resolvers, the central config can reference their secrets
+	err = c.initSecretResolvers()
+	if err != nil {
+		return err
+	}
+
 	// Init Central Config
 	c.centralCfg, err = config.ParseCentralConfig(c.GetProperties(), c.GetAgentType())
 	if err != nil {
@@ -259,8 +284,31 @@ func (c *agentRootCommand) initConfig() error {
 	return nil
 }
 
+// initSecretResolvers - Parses the secret config, sets the refresh interval and registers the vault resolver
+func (c *agentRootCommand) initSecretResolvers() error {
+	secretCfg, err := config.ParseSecretConfig(c.GetProperties())
+	if err != nil {
+		return err
+	}
+	err = secretCfg.ValidateCfg()
+	if err != nil {
+		return err
+	}
+
+	c.secretResolver.SetRefreshInterval(secretCfg.GetRefreshInterval())
+	if secretCfg.GetVaultConfig().GetURL() != "" {
+		c.secretResolver.RegisterResolver(resolver.NewVaultResolver(secretCfg.GetVaultConfig(), nil))
+	}
+	return nil
+}
+
 // run - Executes the agent command
 func (c *agentRootCommand) run(cmd *cobra.Command, args []string) (err error) {
+	// the root context of the agent, canceled when the agent is shut down or the command returns
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	agent.SetContext(ctx)
+
 	err = c.initConfig()
 	statusText := ""
 	if err == nil {
@@ -268,12 +316,26 @@ func (c *agentRootCommand) run(cmd *cobra.Command, args []string) (err error) {
 		// This should trigger config init and applyresourcechange handlers
 		agent.OnAgentResourceChange(c.onConfigChange)
 
+		// Re-initialize config when the values of the referenced secrets are rotated
+		c.secretResolver.OnSecretChange(func(references []string) {
+			c.onConfigChange()
+		})
+		if err = c.secretResolver.StartRefresh(); err != nil {
+			log.Errorf("could not start the refresh of the secrets: %s", err.Error())
+			err = nil
+		}
+
 		// Check the sync flag
 		exitcode := agentsync.CheckSyncFlag()
 		if exitcode > -1 {
 			os.Exit(exitcode)
 		}
 
+		// Print the plan of a dry run and exit
+		if agent.IsDryRunMode() {
+			os.Exit(c.runDryRun())
+		}
+
 		log.Infof("Starting %s (%s)", c.rootCmd.Short, c.rootCmd.Version)
 		if c.commandHandler != nil {
 			// Setup logp to use beats logger.
@@ -316,6 +378,10 @@ func (c *agentRootCommand) GetProperties() properties.Properties {
 	return c.props
 }
 
+func (c *agentRootCommand) GetSecretResolver() resolver.SecretResolver {
+	return c.secretResolver
+}
+
 func (c *agentRootCommand) AddCommand(cmd *cobra.Command) {
 	c.rootCmd.AddCommand(cmd)
 }
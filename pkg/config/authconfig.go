@@ -11,8 +11,21 @@ import (
 
 const tokenEndpoint = "/protocol/openid-connect/token"
 
+// Authentication modes of the service account, selected with central.auth.type
+const (
+	// AuthTypeKeyPair - the client assertion JWT signed with the private key, the default
+	AuthTypeKeyPair = "keypair"
+	// AuthTypeClientSecret - the client credentials grant with the client secret
+	AuthTypeClientSecret = "client_secret"
+	// AuthTypeMTLS - the client credentials grant authenticated with the client certificate of the TLS connection
+	AuthTypeMTLS = "mtls"
+	// AuthTypeTokenFile - the token read from a file supplied by the platform, e.g. a workload identity token
+	AuthTypeTokenFile = "token_file"
+)
+
 // AuthConfig - Interface for service account config
 type AuthConfig interface {
+	GetAuthType() string
 	GetTokenURL() string
 	GetRealm() string
 	GetAudience() string
@@ -20,6 +33,11 @@ type AuthConfig interface {
 	GetPrivateKey() string
 	GetPublicKey() string
 	GetKeyPassword() string
+	GetClientSecret() string
+	GetClientCertificate() string
+	GetClientKey() string
+	GetTokenFile() string
+	GetRefreshPercentage() int
 	GetTimeout() time.Duration
 	validate()
 }
@@ -27,6 +45,7 @@ type AuthConfig interface {
 // AuthConfiguration -
 type AuthConfiguration struct {
 	AuthConfig
+	Type           string        `config:"type"`
 	URL            string        `config:"url"`
 	Realm          string        `config:"realm"`
 	ClientID       string        `config:"clientId"`
@@ -35,6 +54,11 @@ type AuthConfiguration struct {
 	PrivateKeyData string        `config:"privateKeyData"`
 	PublicKeyData  string        `config:"publicKeyData"`
 	KeyPwd         string        `config:"keyPassword"`
+	ClientSecret   string        `config:"clientSecret"`
+	ClientCert     string        `config:"clientCert"`
+	ClientKey      string        `config:"clientKey"`
+	TokenFile      string        `config:"tokenFile"`
+	RefreshPercent int           `config:"refreshPercentage"`
 	Timeout        time.Duration `config:"timeout"`
 }
 
@@ -45,6 +69,32 @@ func newAuthConfig() AuthConfig {
 }
 
 func (a *AuthConfiguration) validate() {
+	if a.RefreshPercent < 0 || a.RefreshPercent >= 100 {
+		exception.Throw(ErrBadConfig.FormatError(pathAuthRefreshPercentage))
+	}
+
+	switch a.GetAuthType() {
+	case AuthTypeKeyPair:
+		a.validateServiceAccount()
+		a.validatePrivateKey()
+		a.validatePublicKey()
+	case AuthTypeClientSecret:
+		a.validateServiceAccount()
+		if a.GetClientSecret() == "" {
+			exception.Throw(ErrBadConfig.FormatError(pathAuthClientSecret))
+		}
+	case AuthTypeMTLS:
+		a.validateServiceAccount()
+		a.validateFile(a.GetClientCertificate(), pathAuthClientCert, "client certificate")
+		a.validateFile(a.GetClientKey(), pathAuthClientKey, "client key")
+	case AuthTypeTokenFile:
+		a.validateFile(a.GetTokenFile(), pathAuthTokenFile, "token")
+	default:
+		exception.Throw(ErrBadConfig.FormatError(pathAuthType))
+	}
+}
+
+func (a *AuthConfiguration) validateServiceAccount() {
 	if a.URL == "" {
 		exception.Throw(ErrBadConfig.FormatError(pathAuthURL))
 	} else if _, err := url.ParseRequestURI(a.URL); err != nil {
@@ -58,9 +108,16 @@ func (a *AuthConfiguration) validate() {
 	if a.GetClientID() == "" {
 		exception.Throw(ErrBadConfig.FormatError(pathAuthClientID))
 	}
+}
 
-	a.validatePrivateKey()
-	a.validatePublicKey()
+func (a *AuthConfiguration) validateFile(path, configPath, fileType string) {
+	if path == "" {
+		exception.Throw(ErrBadConfig.FormatError(configPath))
+	}
+	// Validate that the file is readable
+	if _, err := ioutil.ReadFile(path); err != nil {
+		exception.Throw(ErrReadingAuthFile.FormatError(fileType, path))
+	}
 }
 
 func (a *AuthConfiguration) validatePrivateKey() {
@@ -99,6 +156,14 @@ func (a *AuthConfiguration) validatePublicKey() {
 	}
 }
 
+// GetAuthType - Returns the authentication mode, keypair by default
+func (a *AuthConfiguration) GetAuthType() string {
+	if a.Type == "" {
+		return AuthTypeKeyPair
+	}
+	return a.Type
+}
+
 // GetTokenURL - Returns the token URL
 func (a *AuthConfiguration) GetTokenURL() string {
 	if a.URL == "" || a.Realm == "" {
@@ -140,6 +205,34 @@ func (a *AuthConfiguration) GetKeyPassword() string {
 	return a.KeyPwd
 }
 
+// GetClientSecret - Returns the client secret of the client_secret authentication
+func (a *AuthConfiguration) GetClientSecret() string {
+	return a.ClientSecret
+}
+
+// GetClientCertificate - Returns the client certificate file path of the mtls authentication
+func (a *AuthConfiguration) GetClientCertificate() string {
+	return a.ClientCert
+}
+
+// GetClientKey - Returns the client key file path of the mtls authentication
+func (a *AuthConfiguration) GetClientKey() string {
+	return a.ClientKey
+}
+
+// GetTokenFile - Returns the file path of the token of the token_file authentication
+func (a *AuthConfiguration) GetTokenFile() string {
+	return a.TokenFile
+}
+
+// GetRefreshPercentage - Returns the percentage of the token lifetime after which the token is renewed, 80 by default
+func (a *AuthConfiguration) GetRefreshPercentage() int {
+	if a.RefreshPercent == 0 {
+		return 80
+	}
+	return a.RefreshPercent
+}
+
 // GetTimeout - Returns the token audience URL
 func (a *AuthConfiguration) GetTimeout() time.Duration {
 	return a.Timeout
@@ -78,3 +78,52 @@ func TestAuthConfig(t *testing.T) {
 	err = os.Remove("./" + fs.Name())
 	assert.Nil(t, err)
 }
+
+func TestAuthConfigTypes(t *testing.T) {
+	cfg := &AuthConfiguration{
+		URL:      "http://foo.com:8080",
+		Realm:    "rrr",
+		ClientID: "cccc",
+	}
+	assert.Equal(t, AuthTypeKeyPair, cfg.GetAuthType())
+
+	cfg.Type = "unknown"
+	err := validateAuth(cfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, "[Error Code 1401] - error with config central.auth.type, please set and/or check its value", err.Error())
+
+	// client secret
+	cfg.Type = AuthTypeClientSecret
+	err = validateAuth(cfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, "[Error Code 1401] - error with config central.auth.clientSecret, please set and/or check its value", err.Error())
+	cfg.ClientSecret = "secret"
+	assert.Nil(t, validateAuth(cfg))
+
+	// mtls
+	cfg.Type = AuthTypeMTLS
+	err = validateAuth(cfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, "[Error Code 1401] - error with config central.auth.clientCert, please set and/or check its value", err.Error())
+	cfg.ClientCert = "./missing.crt"
+	err = validateAuth(cfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, "[Error Code 1406] - could not read the client certificate file ./missing.crt", err.Error())
+
+	fs, _ := ioutil.TempFile(".", "test*")
+	defer os.Remove("./" + fs.Name())
+	cfg.ClientCert = "./" + fs.Name()
+	err = validateAuth(cfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, "[Error Code 1401] - error with config central.auth.clientKey, please set and/or check its value", err.Error())
+	cfg.ClientKey = "./" + fs.Name()
+	assert.Nil(t, validateAuth(cfg))
+
+	// token file, the service account is not needed
+	cfg = &AuthConfiguration{Type: AuthTypeTokenFile}
+	err = validateAuth(cfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, "[Error Code 1401] - error with config central.auth.tokenFile, please set and/or check its value", err.Error())
+	cfg.TokenFile = "./" + fs.Name()
+	assert.Nil(t, validateAuth(cfg))
+}
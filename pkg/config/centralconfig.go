@@ -112,11 +112,13 @@ type CentralConfig interface {
 	GetPollInterval() time.Duration
 	GetReportActivityFrequency() time.Duration
 	GetClientTimeout() time.Duration
+	GetShutdownTimeout() time.Duration
 	GetCatalogItemByIDURL(catalogItemID string) string
 	GetAppendEnvironmentToTitle() bool
 	CanPublishUsageEvent() bool
-	// CanPublishMetricEvent() bool
+	CanPublishMetricEvent() bool
 	GetEventAggregationInterval() time.Duration
+	CanWatchResources() bool
 }
 
 // CentralConfiguration - Structure to hold the central config
@@ -137,18 +139,20 @@ type CentralConfiguration struct {
 	TagsToPublish             string             `config:"additionalTags"`
 	AppendEnvironmentToTitle  bool               `config:"appendEnvironmentToTitle"`
 	UpdateFromAPIServer       bool               `config:"updateFromAPIServer"`
+	WatchResources            bool               `config:"watchResources"`
 	Auth                      AuthConfig         `config:"auth"`
 	TLS                       TLSConfig          `config:"ssl"`
 	PollInterval              time.Duration      `config:"pollInterval"`
 	ReportActivityFrequency   time.Duration      `config:"reportActivityFrequency"`
 	ClientTimeout             time.Duration      `config:"clientTimeout"`
+	ShutdownTimeout           time.Duration      `config:"shutdownTimeout"`
 	ProxyURL                  string             `config:"proxyUrl"`
 	SubscriptionConfiguration SubscriptionConfig `config:"subscriptions"`
 	PublishUsageEvents        bool               `config:"publishUsage"`
+	PublishMetricEvents       bool               `config:"publishMetric"`
 	EventAggregationInterval  time.Duration      `config:"eventAggregationInterval"`
-	// PublishMetricEvents       bool  `config:"publishMetric"`
-	environmentID string
-	teamID        string
+	environmentID             string
+	teamID                    string
 }
 
 // NewCentralConfig - Creates the default central config
@@ -161,6 +165,7 @@ func NewCentralConfig(agentType AgentType) CentralConfig {
 		TLS:                       NewTLSConfig(),
 		PollInterval:              60 * time.Second,
 		ClientTimeout:             60 * time.Second,
+		ShutdownTimeout:           30 * time.Second,
 		PlatformURL:               "https://platform.axway.com",
 		SubscriptionConfiguration: NewSubscriptionConfig(),
 		AppendEnvironmentToTitle:  true,
@@ -385,6 +390,11 @@ func (c *CentralConfiguration) GetClientTimeout() time.Duration {
 	return c.ClientTimeout
 }
 
+// GetShutdownTimeout - Returns the time given to the agent to stop its jobs and end its in-flight work on shutdown
+func (c *CentralConfiguration) GetShutdownTimeout() time.Duration {
+	return c.ShutdownTimeout
+}
+
 // GetAppendEnvironmentToTitle - Returns the value of append environment name to title attribute
 func (c *CentralConfiguration) GetAppendEnvironmentToTitle() bool {
 	return c.AppendEnvironmentToTitle
@@ -401,9 +411,14 @@ func (c *CentralConfiguration) CanPublishUsageEvent() bool {
 }
 
 // CanPublishMetricEvent - Returns flag to indicate agent can publish metric events
-// func (c *CentralConfiguration) CanPublishMetricEvent() bool {
-// 	return c.PublishMetricEvents
-// }
+func (c *CentralConfiguration) CanPublishMetricEvent() bool {
+	return c.PublishMetricEvents
+}
+
+// CanWatchResources - Returns flag to indicate the agent watches the API server for resource changes instead of polling
+func (c *CentralConfiguration) CanWatchResources() bool {
+	return c.WatchResources
+}
 
 // GetEventAggregationInterval - Returns the interval duration to generate usage and metric events
 func (c *CentralConfiguration) GetEventAggregationInterval() time.Duration {
@@ -422,6 +437,12 @@ const (
 	pathAuthRealm                = "central.auth.realm"
 	pathAuthClientID             = "central.auth.clientId"
 	pathAuthTimeout              = "central.auth.timeout"
+	pathAuthType                 = "central.auth.type"
+	pathAuthClientSecret         = "central.auth.clientSecret"
+	pathAuthClientCert           = "central.auth.clientCert"
+	pathAuthClientKey            = "central.auth.clientKey"
+	pathAuthTokenFile            = "central.auth.tokenFile"
+	pathAuthRefreshPercentage    = "central.auth.refreshPercentage"
 	pathSSLNextProtos            = "central.ssl.nextProtos"
 	pathSSLInsecureSkipVerify    = "central.ssl.insecureSkipVerify"
 	pathSSLCipherSuites          = "central.ssl.cipherSuites"
@@ -435,11 +456,13 @@ const (
 	pathPollInterval             = "central.pollInterval"
 	pathReportActivityFrequency  = "central.reportActivityFrequency"
 	pathClientTimeout            = "central.clientTimeout"
+	pathShutdownTimeout          = "central.shutdownTimeout"
 	pathProxyURL                 = "central.proxyUrl"
 	pathAPIServerVersion         = "central.apiServerVersion"
 	pathAdditionalTags           = "central.additionalTags"
 	pathAppendEnvironmentToTitle = "central.appendEnvironmentToTitle"
 	pathUpdateFromAPIServer      = "central.updateFromAPIServer"
+	pathWatchResources           = "central.watchResources"
 	pathPublishUsage             = "central.publishUsage"
 	pathPublishMetric            = "central.publishMetric"
 	pathEventAggregationInterval = "central.eventAggregationInterval"
@@ -552,6 +575,12 @@ func AddCentralConfigProperties(props properties.Properties, agentType AgentType
 	props.AddStringProperty(pathAuthRealm, "Broker", "AMPLIFY Central authentication Realm")
 	props.AddStringProperty(pathAuthClientID, "", "Client ID for the service account")
 	props.AddDurationProperty(pathAuthTimeout, 10*time.Second, "Timeout waiting for AxwayID response")
+	props.AddStringProperty(pathAuthType, AuthTypeKeyPair, "Authentication mode for the service account: keypair, client_secret, mtls or token_file")
+	props.AddStringProperty(pathAuthClientSecret, "", "Client secret for the client_secret authentication")
+	props.AddStringProperty(pathAuthClientCert, "", "Path to the client certificate for the mtls authentication")
+	props.AddStringProperty(pathAuthClientKey, "", "Path to the client key for the mtls authentication")
+	props.AddStringProperty(pathAuthTokenFile, "", "Path to the token file for the token_file authentication, read again when it changes")
+	props.AddIntProperty(pathAuthRefreshPercentage, 80, "Percentage of the token lifetime after which the token is renewed in the background")
 	// ssl properties and command flags
 	props.AddStringSliceProperty(pathSSLNextProtos, []string{}, "List of supported application level protocols, comma separated")
 	props.AddBoolProperty(pathSSLInsecureSkipVerify, false, "Controls whether a client verifies the server's certificate chain and host name")
@@ -564,14 +593,16 @@ func AddCentralConfigProperties(props properties.Properties, agentType AgentType
 	props.AddDurationProperty(pathPollInterval, 60*time.Second, "The time interval at which the central will be polled for subscription processing")
 	props.AddDurationProperty(pathReportActivityFrequency, 5*time.Minute, "The time interval at which the agent polls for event changes for the periodic agent status updater")
 	props.AddDurationProperty(pathClientTimeout, 60*time.Second, "The time interval at which the http client times out making HTTP requests and processing the response")
+	props.AddDurationProperty(pathShutdownTimeout, 30*time.Second, "The time given to the agent, on shutdown, to stop its jobs and end its in-flight publishes before exiting")
 	props.AddStringProperty(pathAPIServerVersion, "v1alpha1", "Version of the API Server")
 	props.AddBoolProperty(pathUpdateFromAPIServer, false, "Controls whether to call API Server if the API is not in the local cache")
+	props.AddBoolProperty(pathWatchResources, false, "Controls whether the agent watches API Server for resource changes instead of polling at the poll interval")
 
 	if agentType == TraceabilityAgent {
 		props.AddStringProperty(pathDeployment, "prod", "AMPLIFY Central")
 		props.AddStringProperty(pathLighthouseURL, "https://lighthouse.admin.axway.com", "URL of the Lighthouse")
 		props.AddBoolProperty(pathPublishUsage, true, "Indicates if the agent can publish usage event to AMPLIFY platform. Default to true")
-		// props.AddBoolProperty(pathPublishMetric, true, "Indicates if the agent can publish metric event to AMPLIFY platform. Default to true")
+		props.AddBoolProperty(pathPublishMetric, true, "Indicates if the agent can publish metric event to AMPLIFY platform. Default to true")
 		props.AddDurationProperty(pathEventAggregationInterval, 5*time.Minute, "The time interval at which usage and metric event will be generated")
 	} else {
 		props.AddStringProperty(pathMode, "publishToEnvironmentAndCatalog", "Agent Mode")
@@ -590,17 +621,24 @@ func ParseCentralConfig(props properties.Properties, agentType AgentType) (Centr
 		PollInterval:            props.DurationPropertyValue(pathPollInterval),
 		ReportActivityFrequency: props.DurationPropertyValue(pathReportActivityFrequency),
 		ClientTimeout:           props.DurationPropertyValue(pathClientTimeout),
+		ShutdownTimeout:         props.DurationPropertyValue(pathShutdownTimeout),
 		Environment:             props.StringPropertyValue(pathEnvironment),
 		TeamName:                props.StringPropertyValue(pathTeam),
 		AgentName:               props.StringPropertyValue(pathAgentName),
 		Auth: &AuthConfiguration{
-			URL:        props.StringPropertyValue(pathAuthURL),
-			Realm:      props.StringPropertyValue(pathAuthRealm),
-			ClientID:   props.StringPropertyValue(pathAuthClientID),
-			PrivateKey: props.StringPropertyValue(pathAuthPrivateKey),
-			PublicKey:  props.StringPropertyValue(pathAuthPublicKey),
-			KeyPwd:     props.StringPropertyValue(pathAuthKeyPassword),
-			Timeout:    props.DurationPropertyValue(pathAuthTimeout),
+			URL:            props.StringPropertyValue(pathAuthURL),
+			Realm:          props.StringPropertyValue(pathAuthRealm),
+			ClientID:       props.StringPropertyValue(pathAuthClientID),
+			PrivateKey:     props.StringPropertyValue(pathAuthPrivateKey),
+			PublicKey:      props.StringPropertyValue(pathAuthPublicKey),
+			KeyPwd:         props.StringPropertyValue(pathAuthKeyPassword),
+			Timeout:        props.DurationPropertyValue(pathAuthTimeout),
+			Type:           props.StringPropertyValue(pathAuthType),
+			ClientSecret:   props.StringPropertyValue(pathAuthClientSecret),
+			ClientCert:     props.StringPropertyValue(pathAuthClientCert),
+			ClientKey:      props.StringPropertyValue(pathAuthClientKey),
+			TokenFile:      props.StringPropertyValue(pathAuthTokenFile),
+			RefreshPercent: props.IntPropertyValue(pathAuthRefreshPercentage),
 		},
 		TLS: &TLSConfiguration{
 			NextProtos:         props.StringSlicePropertyValue(pathSSLNextProtos),
@@ -611,6 +649,7 @@ func ParseCentralConfig(props properties.Properties, agentType AgentType) (Centr
 		},
 		ProxyURL:            proxyURL,
 		UpdateFromAPIServer: props.BoolPropertyValue(pathUpdateFromAPIServer),
+		WatchResources:      props.BoolPropertyValue(pathWatchResources),
 	}
 
 	cfg.URL = props.StringPropertyValue(pathURL)
@@ -621,7 +660,7 @@ func ParseCentralConfig(props properties.Properties, agentType AgentType) (Centr
 		cfg.APICDeployment = props.StringPropertyValue(pathDeployment)
 		cfg.LighthouseURL = props.StringPropertyValue(pathLighthouseURL)
 		cfg.PublishUsageEvents = props.BoolPropertyValue(pathPublishUsage)
-		// cfg.PublishMetricEvents = props.BoolPropertyValue(pathPublishMetric)
+		cfg.PublishMetricEvents = props.BoolPropertyValue(pathPublishMetric)
 		cfg.EventAggregationInterval = props.DurationPropertyValue(pathEventAggregationInterval)
 	} else {
 		cfg.Mode = StringAgentModeMap[strings.ToLower(props.StringPropertyValue(pathMode))]
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/cmd/properties"
+)
+
+// SecretConfig - Interface for the config of the secret resolvers
+type SecretConfig interface {
+	GetRefreshInterval() time.Duration
+	GetVaultConfig() VaultConfig
+	ValidateCfg() error
+}
+
+// VaultConfig - Interface for the config of the Vault KV store resolving the @vault: references
+type VaultConfig interface {
+	GetURL() string
+	GetNamespace() string
+	GetToken() string
+	GetRoleID() string
+	GetSecretID() string
+	GetAuthPath() string
+}
+
+// SecretConfiguration -
+type SecretConfiguration struct {
+	SecretConfig
+	RefreshInterval time.Duration       `config:"refreshInterval"`
+	Vault           *VaultConfiguration `config:"vault"`
+}
+
+// VaultConfiguration -
+type VaultConfiguration struct {
+	VaultConfig
+	URL       string `config:"url"`
+	Namespace string `config:"namespace"`
+	Token     string `config:"token"`
+	RoleID    string `config:"roleID"`
+	SecretID  string `config:"secretID"`
+	AuthPath  string `config:"authPath"`
+}
+
+// NewSecretConfig - create a new secret config
+func NewSecretConfig() SecretConfig {
+	return &SecretConfiguration{
+		RefreshInterval: 5 * time.Minute,
+		Vault: &VaultConfiguration{
+			AuthPath: "approle",
+		},
+	}
+}
+
+// GetRefreshInterval - Returns the time after which the resolved secrets are resolved again, 0 to never refresh them
+func (s *SecretConfiguration) GetRefreshInterval() time.Duration {
+	return s.RefreshInterval
+}
+
+// GetVaultConfig - Returns the Vault config
+func (s *SecretConfiguration) GetVaultConfig() VaultConfig {
+	return s.Vault
+}
+
+// GetURL - Returns the URL of the Vault server, the @vault: references are not resolved when not set
+func (v *VaultConfiguration) GetURL() string {
+	return v.URL
+}
+
+// GetNamespace - Returns the Vault enterprise namespace
+func (v *VaultConfiguration) GetNamespace() string {
+	return v.Namespace
+}
+
+// GetToken - Returns the Vault token
+func (v *VaultConfiguration) GetToken() string {
+	return v.Token
+}
+
+// GetRoleID - Returns the role ID of the AppRole authentication, used when no token is set
+func (v *VaultConfiguration) GetRoleID() string {
+	return v.RoleID
+}
+
+// GetSecretID - Returns the secret ID of the AppRole authentication
+func (v *VaultConfiguration) GetSecretID() string {
+	return v.SecretID
+}
+
+// GetAuthPath - Returns the mount path of the AppRole auth method
+func (v *VaultConfiguration) GetAuthPath() string {
+	return v.AuthPath
+}
+
+const (
+	pathSecretRefreshInterval = "secret.refreshInterval"
+	pathVaultURL              = "secret.vault.url"
+	pathVaultNamespace        = "secret.vault.namespace"
+	pathVaultToken            = "secret.vault.token"
+	pathVaultRoleID           = "secret.vault.roleID"
+	pathVaultSecretID         = "secret.vault.secretID"
+	pathVaultAuthPath         = "secret.vault.authPath"
+)
+
+// AddSecretConfigProperties - Adds the command properties needed for the secret resolvers
+func AddSecretConfigProperties(props properties.Properties) {
+	props.AddDurationProperty(pathSecretRefreshInterval, 5*time.Minute, "Interval for resolving the secret references again, 0 to never refresh them")
+	props.AddStringProperty(pathVaultURL, "", "URL of the Vault server resolving the @vault: secret references")
+	props.AddStringProperty(pathVaultNamespace, "", "Vault enterprise namespace")
+	props.AddStringProperty(pathVaultToken, "", "Vault token")
+	props.AddStringProperty(pathVaultRoleID, "", "Role ID for the Vault AppRole authentication, used when no token is set")
+	props.AddStringProperty(pathVaultSecretID, "", "Secret ID for the Vault AppRole authentication")
+	props.AddStringProperty(pathVaultAuthPath, "approle", "Mount path of the Vault AppRole auth method")
+}
+
+// ParseSecretConfig - Parses the secret resolvers config values from the command line
+func ParseSecretConfig(props properties.Properties) (SecretConfig, error) {
+	cfg := &SecretConfiguration{
+		RefreshInterval: props.DurationPropertyValue(pathSecretRefreshInterval),
+		Vault: &VaultConfiguration{
+			URL:       props.StringPropertyValue(pathVaultURL),
+			Namespace: props.StringPropertyValue(pathVaultNamespace),
+			Token:     props.StringPropertyValue(pathVaultToken),
+			RoleID:    props.StringPropertyValue(pathVaultRoleID),
+			SecretID:  props.StringPropertyValue(pathVaultSecretID),
+			AuthPath:  props.StringPropertyValue(pathVaultAuthPath),
+		},
+	}
+	return cfg, nil
+}
+
+// ValidateCfg - Validates the config, implementing IConfigInterface
+func (s *SecretConfiguration) ValidateCfg() error {
+	if s.GetRefreshInterval() < 0 {
+		return ErrBadConfig.FormatError(pathSecretRefreshInterval)
+	}
+	vault := s.Vault
+	if vault == nil || vault.URL == "" {
+		return nil
+	}
+	if vault.Token == "" && (vault.RoleID == "" || vault.SecretID == "") {
+		return ErrBadConfig.FormatError(pathVaultToken)
+	}
+	if vault.Token == "" && vault.AuthPath == "" {
+		return ErrBadConfig.FormatError(pathVaultAuthPath)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/cmd/properties"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretConfig(t *testing.T) {
+	props := properties.NewProperties(&cobra.Command{})
+	AddSecretConfigProperties(props)
+
+	cfg, err := ParseSecretConfig(props)
+	assert.Nil(t, err)
+	assert.Nil(t, cfg.ValidateCfg(), "Expected no error with default values")
+	assert.Equal(t, 5*time.Minute, cfg.GetRefreshInterval())
+	assert.Equal(t, "", cfg.GetVaultConfig().GetURL())
+	assert.Equal(t, "approle", cfg.GetVaultConfig().GetAuthPath())
+
+	// vault without authentication
+	secretCfg := cfg.(*SecretConfiguration)
+	secretCfg.Vault.URL = "https://vault:8200"
+	assert.NotNil(t, cfg.ValidateCfg(), "Expected error with no vault token or role")
+
+	// app role without secret id
+	secretCfg.Vault.RoleID = "role"
+	assert.NotNil(t, cfg.ValidateCfg(), "Expected error with no vault secret id")
+
+	secretCfg.Vault.SecretID = "secret"
+	assert.Nil(t, cfg.ValidateCfg())
+
+	secretCfg.Vault.AuthPath = ""
+	assert.NotNil(t, cfg.ValidateCfg(), "Expected error with no vault auth path")
+
+	// token
+	secretCfg.Vault.Token = "token"
+	assert.Nil(t, cfg.ValidateCfg())
+
+	secretCfg.RefreshInterval = -time.Second
+	assert.NotNil(t, cfg.ValidateCfg(), "Expected error with negative refresh interval")
+}
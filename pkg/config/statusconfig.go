@@ -11,6 +11,8 @@ type StatusConfig interface {
 	GetPort() int
 	GetHealthCheckPeriod() time.Duration
 	GetHealthCheckInterval() time.Duration
+	GetControlToken() string
+	IsMetricsEnabled() bool
 	ValidateCfg() error
 }
 
@@ -20,6 +22,8 @@ type StatusConfiguration struct {
 	Port                int           `config:"port"`
 	HealthCheckPeriod   time.Duration `config:"healthCheckPeriod"`
 	HealthCheckInterval time.Duration `config:"healthCheckInterval"` // this for binary agents only
+	ControlToken        string        `config:"controlToken"`
+	Metrics             bool          `config:"metrics"`
 }
 
 // NewStatusConfig - create a new status config
@@ -28,6 +32,7 @@ func NewStatusConfig() StatusConfig {
 		Port:                8989,
 		HealthCheckPeriod:   3 * time.Minute,
 		HealthCheckInterval: 30 * time.Second,
+		Metrics:             true,
 	}
 }
 
@@ -46,10 +51,22 @@ func (a *StatusConfiguration) GetHealthCheckInterval() time.Duration {
 	return a.HealthCheckInterval
 }
 
+// GetControlToken - Returns the bearer token of the job control endpoints, the endpoints are disabled when empty
+func (a *StatusConfiguration) GetControlToken() string {
+	return a.ControlToken
+}
+
+// IsMetricsEnabled - Returns true when the metrics endpoint is served
+func (a *StatusConfiguration) IsMetricsEnabled() bool {
+	return a.Metrics
+}
+
 const (
 	pathPort                = "status.port"
 	pathHealthcheckPeriod   = "status.healthCheckPeriod"
 	pathHealthcheckInterval = "status.healthCheckInterval"
+	pathControlToken        = "status.controlToken"
+	pathMetrics             = "status.metrics"
 )
 
 // AddStatusConfigProperties - Adds the command properties needed for Status Config
@@ -57,6 +74,8 @@ func AddStatusConfigProperties(props properties.Properties) {
 	props.AddIntProperty(pathPort, 8989, "The port that will serve the status endpoints")
 	props.AddDurationProperty(pathHealthcheckPeriod, 3*time.Minute, "Time in minutes allotted for services to be ready before exiting discovery agent")
 	props.AddDurationProperty(pathHealthcheckInterval, 30*time.Second, "Time between running periodic health checker. Can be between 30 seconds and 5 minutes (binary agents only)")
+	props.AddStringProperty(pathControlToken, "", "The bearer token for the endpoints triggering, pausing and resuming the jobs, the endpoints are disabled when not set")
+	props.AddBoolProperty(pathMetrics, true, "Controls whether the status server serves the agent metrics on /metrics, in the Prometheus and OpenMetrics text formats")
 	props.AddBoolFlag("status", "Get the status of all the Health Checks")
 }
 
@@ -66,6 +85,8 @@ func ParseStatusConfig(props properties.Properties) (StatusConfig, error) {
 		Port:                props.IntPropertyValue(pathPort),
 		HealthCheckPeriod:   props.DurationPropertyValue(pathHealthcheckPeriod),
 		HealthCheckInterval: props.DurationPropertyValue(pathHealthcheckInterval),
+		ControlToken:        props.StringPropertyValue(pathControlToken),
+		Metrics:             props.BoolPropertyValue(pathMetrics),
 	}
 	return cfg, nil
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/Axway/agent-sdk/pkg/cmd/properties"
 	"github.com/Axway/agent-sdk/pkg/util/log"
@@ -49,6 +50,9 @@ type SubscriptionConfig interface {
 	GetUnsubscribeFailedTemplate() *EmailTemplate
 	GetSubscriptionApprovalMode() string
 	GetSubscriptionApprovalWebhookConfig() WebhookConfig
+	GetLifecycleStateFile() string
+	GetLifecycleMaxRetries() int
+	GetLifecycleRetryBackoff() time.Duration
 }
 
 // NotificationConfig -
@@ -77,6 +81,13 @@ type ApprovalConfig struct {
 	SubscriptionApprovalWebhook WebhookConfig `config:"webhook"`
 }
 
+// LifecycleConfig - the retries of the subscription processors and the file recording the subscription transitions
+type LifecycleConfig struct {
+	StateFile    string        `config:"stateFile"`
+	MaxRetries   int           `config:"maxRetries"`
+	RetryBackoff time.Duration `config:"retryBackoff"`
+}
+
 // SubscriptionConfiguration - Structure to hold the subscription config
 type SubscriptionConfiguration struct {
 	SubscriptionConfig
@@ -84,12 +95,21 @@ type SubscriptionConfiguration struct {
 	DisablePolling bool                `config:"disablePolling"`
 	Approval       *ApprovalConfig     `config:"approval"`
 	Notifications  *NotificationConfig `config:"notifications"`
+	Lifecycle      *LifecycleConfig    `config:"lifecycle"`
 	Types          []NotificationType
 }
 
+const (
+	defaultLifecycleMaxRetries   = 5
+	defaultLifecycleRetryBackoff = 30 * time.Second
+)
+
 // These constants are the paths that the settings are at in a config file
 const (
 	pathSubscriptionsDisablePolling                            = "central.subscriptions.disablePolling"
+	pathSubscriptionsLifecycleStateFile                        = "central.subscriptions.lifecycle.stateFile"
+	pathSubscriptionsLifecycleMaxRetries                       = "central.subscriptions.lifecycle.maxRetries"
+	pathSubscriptionsLifecycleRetryBackoff                     = "central.subscriptions.lifecycle.retryBackoff"
 	pathSubscriptionsApprovalMode                              = "central.subscriptions.approval.mode"
 	pathSubscriptionsApprovalWebhookURL                        = "central.subscriptions.approval.webhook.url"
 	pathSubscriptionsApprovalWebhookHeaders                    = "central.subscriptions.approval.webhook.headers"
@@ -128,6 +148,11 @@ func AddSubscriptionConfigProperties(props properties.Properties) {
 	// subscriptions enabled
 	props.AddBoolProperty(pathSubscriptionsDisablePolling, false, "Set to true to disable polling for subscriptions on the agent")
 
+	// subscription lifecycle
+	props.AddStringProperty(pathSubscriptionsLifecycleStateFile, "", "File recording the subscription transitions, so that they are not processed again after a restart")
+	props.AddIntProperty(pathSubscriptionsLifecycleMaxRetries, defaultLifecycleMaxRetries, "Number of retries of a subscription processor before the subscription is set to failed")
+	props.AddDurationProperty(pathSubscriptionsLifecycleRetryBackoff, defaultLifecycleRetryBackoff, "Wait before the first retry of a subscription processor, doubled on each retry")
+
 	// subscription approvals
 	props.AddStringProperty(pathSubscriptionsApprovalMode, ManualApproval, "The mode to use for approving subscriptions for AMPLIFY Central (manual, webhook, auto)")
 	props.AddStringProperty(pathSubscriptionsApprovalWebhookURL, "", "The subscription webhook URL to use for approving subscriptions for AMPLIFY Central")
@@ -170,6 +195,11 @@ func ParseSubscriptionConfig(props properties.Properties) SubscriptionConfig {
 
 	cfg := &SubscriptionConfiguration{
 		DisablePolling: props.BoolPropertyValue(pathSubscriptionsDisablePolling),
+		Lifecycle: &LifecycleConfig{
+			StateFile:    props.StringPropertyValue(pathSubscriptionsLifecycleStateFile),
+			MaxRetries:   props.IntPropertyValue(pathSubscriptionsLifecycleMaxRetries),
+			RetryBackoff: props.DurationPropertyValue(pathSubscriptionsLifecycleRetryBackoff),
+		},
 		Approval: &ApprovalConfig{
 			SubscriptionApprovalMode: props.StringPropertyValue(pathSubscriptionsApprovalMode),
 			SubscriptionApprovalWebhook: &WebhookConfiguration{
@@ -227,6 +257,10 @@ func NewSubscriptionConfig() SubscriptionConfig {
 			Webhook: NewWebhookConfig(),
 			SMTP:    &smtp{},
 		},
+		Lifecycle: &LifecycleConfig{
+			MaxRetries:   defaultLifecycleMaxRetries,
+			RetryBackoff: defaultLifecycleRetryBackoff,
+		},
 	}
 }
 
@@ -359,8 +393,35 @@ func (s *SubscriptionConfiguration) GetSubscriptionApprovalWebhookConfig() Webho
 	return s.Approval.SubscriptionApprovalWebhook
 }
 
+// GetLifecycleStateFile - Returns the file recording the subscription transitions, they are kept in memory when empty
+func (s *SubscriptionConfiguration) GetLifecycleStateFile() string {
+	if s.Lifecycle == nil {
+		return ""
+	}
+	return s.Lifecycle.StateFile
+}
+
+// GetLifecycleMaxRetries - Returns the number of retries of a subscription processor
+func (s *SubscriptionConfiguration) GetLifecycleMaxRetries() int {
+	if s.Lifecycle == nil {
+		return defaultLifecycleMaxRetries
+	}
+	return s.Lifecycle.MaxRetries
+}
+
+// GetLifecycleRetryBackoff - Returns the wait before the first retry of a subscription processor
+func (s *SubscriptionConfiguration) GetLifecycleRetryBackoff() time.Duration {
+	if s.Lifecycle == nil || s.Lifecycle.RetryBackoff <= 0 {
+		return defaultLifecycleRetryBackoff
+	}
+	return s.Lifecycle.RetryBackoff
+}
+
 // ValidateCfg - Validates the config, implementing IConfigInterface
 func (s *SubscriptionConfiguration) ValidateCfg() error {
+	if s.GetLifecycleMaxRetries() < 0 {
+		return ErrBadConfig.FormatError(pathSubscriptionsLifecycleMaxRetries)
+	}
 	if s.Notifications.Webhook.GetURL() != "" {
 		s.SetNotificationType(NotifyWebhook)
 		log.Debug("Webhook notification set")
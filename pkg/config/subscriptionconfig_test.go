@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Axway/agent-sdk/pkg/cmd/properties"
 	"github.com/spf13/cobra"
@@ -160,4 +161,33 @@ func TestSubscriptionSMTPConfig(t *testing.T) {
 func TestNewSubscriptionConfig(t *testing.T) {
 	cfg := NewSubscriptionConfig()
 	assert.NotNil(t, cfg)
+	assert.Equal(t, "", cfg.GetLifecycleStateFile())
+	assert.Equal(t, 5, cfg.GetLifecycleMaxRetries())
+	assert.Equal(t, 30*time.Second, cfg.GetLifecycleRetryBackoff())
+}
+
+func TestSubscriptionLifecycleConfig(t *testing.T) {
+	rootCmd := &cobra.Command{
+		Use: "test",
+	}
+	props := properties.NewProperties(rootCmd)
+	props.AddStringProperty(pathSubscriptionsLifecycleStateFile, "/tmp/subscriptions.log", "")
+	props.AddIntProperty(pathSubscriptionsLifecycleMaxRetries, 3, "")
+	props.AddDurationProperty(pathSubscriptionsLifecycleRetryBackoff, time.Minute, "")
+
+	cfg := ParseSubscriptionConfig(props)
+	assert.Nil(t, ValidateConfig(cfg))
+	assert.Equal(t, "/tmp/subscriptions.log", cfg.GetLifecycleStateFile())
+	assert.Equal(t, 3, cfg.GetLifecycleMaxRetries())
+	assert.Equal(t, time.Minute, cfg.GetLifecycleRetryBackoff())
+
+	// this one should be bad max retries
+	rootCmd = &cobra.Command{
+		Use: "test",
+	}
+	props = properties.NewProperties(rootCmd)
+	props.AddIntProperty(pathSubscriptionsLifecycleMaxRetries, -1, "")
+
+	cfg = ParseSubscriptionConfig(props)
+	assert.NotNil(t, ValidateConfig(cfg))
 }
@@ -15,6 +15,8 @@ const (
 	CONTAINS
 	EXISTS
 	ANY
+	IN
+	MATCHGLOB
 )
 
 var callTypeMap = map[string]CallType{
@@ -22,6 +24,8 @@ var callTypeMap = map[string]CallType{
 	"exists":     EXISTS,
 	"contains":   CONTAINS,
 	"matchregex": MATCHREGEX,
+	"in":         IN,
+	"matchglob":  MATCHGLOB,
 }
 
 // CallExpr - Interface for call expression in filter condition
@@ -37,7 +41,11 @@ func newCallExpr(callType CallType, filterType, name string, arguments []interfa
 		return nil, errors.New("Syntax Error, unrecognized argument(s)")
 	}
 
-	if callType == CONTAINS || callType == MATCHREGEX {
+	if callType == IN && len(arguments) == 0 {
+		return nil, errors.New("Syntax Error, missing argument")
+	}
+
+	if callType == CONTAINS || callType == MATCHREGEX || callType == MATCHGLOB {
 		if len(arguments) == 0 {
 			return nil, errors.New("Syntax Error, missing argument")
 		}
@@ -68,6 +76,14 @@ func newCallExpr(callType CallType, filterType, name string, arguments []interfa
 		callExpr = newContainsExpr(filterType, name, arguments[0].(string))
 	case MATCHREGEX:
 		callExpr, err = newMatchRegExExpr(filterType, name, arguments[0].(string))
+	case MATCHGLOB:
+		callExpr, err = newMatchGlobExpr(filterType, name, arguments[0].(string))
+	case IN:
+		inArgs := make([]string, 0, len(arguments))
+		for _, arg := range arguments {
+			inArgs = append(inArgs, arg.(string))
+		}
+		callExpr = newInExpr(filterType, name, inArgs)
 	}
 
 	return
@@ -1,10 +1,16 @@
 package filter
 
+import (
+	"strconv"
+	"strings"
+)
+
 // ComparableValue - Interface for RHS value operand
 type ComparableValue interface {
 	eq(interface{}) bool
 	neq(interface{}) bool
 	any(interface{}) bool
+	compare(string) (int, bool)
 	String() string
 }
 
@@ -37,6 +43,71 @@ func (scv *StringRHSValue) any(valuesToCompare interface{}) bool {
 	return false
 }
 
+// compare - compares the value with the RHS value as semantic versions, not comparable when either is not a version
+func (scv *StringRHSValue) compare(valueToCompare string) (int, bool) {
+	lhsVersion, lhsErr := parseVersion(valueToCompare)
+	rhsVersion, rhsErr := parseVersion(scv.value)
+	if lhsErr != nil || rhsErr != nil {
+		return 0, false
+	}
+	return lhsVersion.compare(rhsVersion), true
+}
+
 func (scv *StringRHSValue) String() string {
 	return scv.value
 }
+
+// NumberRHSValue - Represents the numeric RHS value in simple condition
+type NumberRHSValue struct {
+	literal string
+	value   float64
+}
+
+func newNumberRHSValue(literal string) (ComparableValue, error) {
+	value, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &NumberRHSValue{
+		literal: literal,
+		value:   value,
+	}, nil
+}
+
+func (ncv *NumberRHSValue) eq(valueToCompare interface{}) bool {
+	res, ok := ncv.compare(valueToCompare.(string))
+	return ok && res == 0
+}
+
+func (ncv *NumberRHSValue) neq(valueToCompare interface{}) bool {
+	return !ncv.eq(valueToCompare)
+}
+
+func (ncv *NumberRHSValue) any(valuesToCompare interface{}) bool {
+	values := valuesToCompare.([]string)
+	for _, valueEntry := range values {
+		if ncv.eq(valueEntry) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare - compares the value with the RHS value as numbers, not comparable when the value is not a number
+func (ncv *NumberRHSValue) compare(valueToCompare string) (int, bool) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(valueToCompare), 64)
+	if err != nil {
+		return 0, false
+	}
+	switch {
+	case value < ncv.value:
+		return -1, true
+	case value > ncv.value:
+		return 1, true
+	}
+	return 0, true
+}
+
+func (ncv *NumberRHSValue) String() string {
+	return ncv.literal
+}
@@ -8,6 +8,7 @@ import (
 // Condition - Interface for the filter condition
 type Condition interface {
 	Evaluate(data Data) bool
+	Explain(data Data) *Explanation
 	String() string
 }
 
@@ -27,6 +28,14 @@ func (sf *SimpleCondition) Evaluate(data Data) (res bool) {
 	callType := sf.LHSExpr.GetType()
 	switch callType {
 	case ANY:
+		if isRelationalOperator(sf.Operator) {
+			for _, value := range lhsValue.([]string) {
+				if sf.compare(value) {
+					return true
+				}
+			}
+			return false
+		}
 		res = sf.Value.any(lhsValue)
 		if sf.Operator == token.NEQ.String() {
 			res = !res
@@ -36,6 +45,9 @@ func (sf *SimpleCondition) Evaluate(data Data) (res bool) {
 			res = lhsValue.(bool)
 			lhsValue = strconv.FormatBool(res)
 		}
+		if isRelationalOperator(sf.Operator) {
+			return sf.compare(lhsValue.(string))
+		}
 		if sf.Operator != "" {
 			if sf.Operator == token.EQL.String() {
 				res = sf.Value.eq(lhsValue)
@@ -48,6 +60,33 @@ func (sf *SimpleCondition) Evaluate(data Data) (res bool) {
 	return res
 }
 
+// compare - evaluates the relational operator, numbers are compared numerically and strings as semantic versions
+func (sf *SimpleCondition) compare(value string) bool {
+	res, ok := sf.Value.compare(value)
+	if !ok {
+		return false
+	}
+	switch sf.Operator {
+	case token.LSS.String():
+		return res < 0
+	case token.LEQ.String():
+		return res <= 0
+	case token.GTR.String():
+		return res > 0
+	case token.GEQ.String():
+		return res >= 0
+	}
+	return false
+}
+
+func isRelationalOperator(operator string) bool {
+	switch operator {
+	case token.LSS.String(), token.LEQ.String(), token.GTR.String(), token.GEQ.String():
+		return true
+	}
+	return false
+}
+
 // String - string representation for simple condition
 func (sf *SimpleCondition) String() string {
 	str := sf.LHSExpr.String()
@@ -84,3 +123,18 @@ func (cf *CompoundCondition) String() string {
 	}
 	return ""
 }
+
+// NotCondition - Represents the negation of a condition
+type NotCondition struct {
+	Condition Condition
+}
+
+// Evaluate - evaluates the negated condition
+func (nf *NotCondition) Evaluate(data Data) bool {
+	return !nf.Condition.Evaluate(data)
+}
+
+// String - string representation for negated condition
+func (nf *NotCondition) String() string {
+	return "!" + nf.Condition.String()
+}
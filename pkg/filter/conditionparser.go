@@ -85,6 +85,10 @@ func (f *ConditionParser) parseExpr(expr ast.Expr) (Condition, error) {
 	bexpr, ok := expr.(*ast.BinaryExpr)
 	if ok {
 		return f.parseBinaryExpr(bexpr)
+	} else if uexpr, ok := expr.(*ast.UnaryExpr); ok {
+		return f.parseUnaryExpr(uexpr)
+	} else if pexpr, ok := expr.(*ast.ParenExpr); ok {
+		return f.parseExpr(pexpr.X)
 	} else if callExpr, ok := expr.(*ast.CallExpr); ok {
 		ce, err := f.parseCallExpr(callExpr)
 		if err != nil {
@@ -97,6 +101,19 @@ func (f *ConditionParser) parseExpr(expr ast.Expr) (Condition, error) {
 	return nil, ErrFilterExpression
 }
 
+func (f *ConditionParser) parseUnaryExpr(expr *ast.UnaryExpr) (Condition, error) {
+	if expr.Op != token.NOT {
+		return nil, ErrFilterOperator
+	}
+	condition, err := f.parseExpr(expr.X)
+	if err != nil {
+		return nil, err
+	}
+	return &NotCondition{
+		Condition: condition,
+	}, nil
+}
+
 func (f *ConditionParser) parseCallExpr(expr *ast.CallExpr) (CallExpr, error) {
 	funcSelectorExprt, ok := expr.Fun.(*ast.SelectorExpr)
 	if !ok {
@@ -218,12 +235,27 @@ func (f *ConditionParser) parseCallLHS(lhs *ast.CallExpr) (CallExpr, error) {
 	return ce, nil
 }
 
-func (f *ConditionParser) parseSimpleRHS(expr *ast.BinaryExpr) (filterValue ComparableValue) {
+func (f *ConditionParser) parseSimpleRHS(expr *ast.BinaryExpr) (filterValue ComparableValue, err error) {
 	literal, ok := expr.Y.(*ast.BasicLit)
 	if ok {
+		if literal.Kind == token.INT || literal.Kind == token.FLOAT {
+			filterValue, err = newNumberRHSValue(literal.Value)
+			if err != nil {
+				return nil, ErrFilterCondition
+			}
+			return
+		}
 		filterValue = newStringRHSValue(strings.Trim(literal.Value, `"`))
 	} else if identVal, ok := expr.Y.(*ast.Ident); ok {
 		filterValue = newStringRHSValue(identVal.Name)
+	} else if uexpr, ok := expr.Y.(*ast.UnaryExpr); ok && uexpr.Op == token.SUB {
+		// negative numbers
+		if literal, ok := uexpr.X.(*ast.BasicLit); ok && (literal.Kind == token.INT || literal.Kind == token.FLOAT) {
+			filterValue, err = newNumberRHSValue("-" + literal.Value)
+			if err != nil {
+				return nil, ErrFilterCondition
+			}
+		}
 	}
 	return
 }
@@ -238,7 +270,17 @@ func (f *ConditionParser) parseSimpleBinaryExpr(expr *ast.BinaryExpr) (Condition
 	if err != nil {
 		return nil, err
 	}
-	filterNode.Value = f.parseSimpleRHS(expr)
+
+	// values are ordered, the results of the other calls are not
+	callType := filterNode.LHSExpr.GetType()
+	if isRelationalOperator(filterNode.Operator) && callType != GETVALUE && callType != ANY {
+		return nil, ErrFilterOperator
+	}
+
+	filterNode.Value, err = f.parseSimpleRHS(expr)
+	if err != nil {
+		return nil, err
+	}
 	return filterNode, nil
 }
 
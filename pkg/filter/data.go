@@ -19,29 +19,33 @@ type AgentFilterData struct {
 
 // NewFilterData - Transforms the data to flat map which is used for filter evaluation
 func NewFilterData(tags interface{}, attr interface{}) Data {
-	vTags := reflect.ValueOf(tags)
-	tagsMap := make(map[string]string)
+	return &AgentFilterData{
+		tags: parseFilterDataMap(tags),
+		attr: parseFilterDataMap(attr),
+	}
+}
+
+func parseFilterDataMap(data interface{}) map[string]string {
+	vData := reflect.ValueOf(data)
+	dataMap := make(map[string]string)
 	// Todo address other types
-	if vTags.Kind() == reflect.Map {
-		for _, key := range vTags.MapKeys() {
-			value := vTags.MapIndex(key)
+	if vData.Kind() == reflect.Map {
+		for _, key := range vData.MapKeys() {
+			value := vData.MapIndex(key)
 			vInterface := reflect.ValueOf(value.Interface())
 			if vInterface.Kind() == reflect.Ptr {
 				vInterface = vInterface.Elem()
 			}
 			if vInterface.Kind() == reflect.String {
 				keyValue := vInterface.String()
-				tagsMap[key.String()] = keyValue
+				dataMap[key.String()] = keyValue
 			}
 			if vInterface.Kind() == reflect.Slice {
-				tagsMap[key.String()] = parseStringSliceFilterData(vInterface)
+				dataMap[key.String()] = parseStringSliceFilterData(vInterface)
 			}
 		}
 	}
-
-	return &AgentFilterData{
-		tags: tagsMap,
-	}
+	return dataMap
 }
 
 func parseStringSliceFilterData(v reflect.Value) string {
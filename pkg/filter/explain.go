@@ -0,0 +1,119 @@
+package filter
+
+import (
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// Explanation - the evaluation of a condition with its operands, results of the sub-conditions are in Conditions
+type Explanation struct {
+	Condition  string         `json:"condition"`
+	Operator   string         `json:"operator,omitempty"`
+	LHS        string         `json:"lhs,omitempty"`
+	LHSValue   string         `json:"lhsValue,omitempty"`
+	RHS        string         `json:"rhs,omitempty"`
+	Result     bool           `json:"result"`
+	Error      string         `json:"error,omitempty"`
+	Conditions []*Explanation `json:"conditions,omitempty"`
+}
+
+// String - the evaluation tree, one condition per line, sub-conditions indented
+func (e *Explanation) String() string {
+	sb := &strings.Builder{}
+	e.write(sb, "")
+	return sb.String()
+}
+
+func (e *Explanation) write(sb *strings.Builder, indent string) {
+	result := strconv.FormatBool(e.Result)
+	sb.WriteString(indent + result + strings.Repeat(" ", 6-len(result)) + e.Condition)
+	if e.Error != "" {
+		sb.WriteString("  [" + e.Error + "]")
+	} else if e.LHS != "" {
+		sb.WriteString("  [" + e.LHS + " = " + strconv.Quote(e.LHSValue) + "]")
+	}
+	sb.WriteString("\n")
+	for _, condition := range e.Conditions {
+		condition.write(sb, indent+"  ")
+	}
+}
+
+// Explain - evaluates the simple condition, with the value of its call expression
+func (sf *SimpleCondition) Explain(data Data) *Explanation {
+	explanation := &Explanation{
+		Condition: sf.String(),
+		Operator:  sf.Operator,
+		LHS:       sf.LHSExpr.String(),
+	}
+	if sf.Value != nil {
+		explanation.RHS = sf.Value.String()
+	}
+
+	lhsValue, err := sf.LHSExpr.Execute(data)
+	if err != nil {
+		explanation.Error = err.Error()
+		return explanation
+	}
+	switch value := lhsValue.(type) {
+	case string:
+		explanation.LHSValue = value
+	case bool:
+		explanation.LHSValue = strconv.FormatBool(value)
+	case []string:
+		explanation.LHSValue = strings.Join(value, ",")
+	}
+	explanation.Result = sf.Evaluate(data)
+	return explanation
+}
+
+// Explain - evaluates the compound condition and both of its conditions
+func (cf *CompoundCondition) Explain(data Data) *Explanation {
+	lhs := cf.LHSCondition.Explain(data)
+	rhs := cf.RHSCondition.Explain(data)
+	result := lhs.Result && rhs.Result
+	if cf.Operator == token.LOR.String() {
+		result = lhs.Result || rhs.Result
+	}
+	return &Explanation{
+		Condition:  cf.String(),
+		Operator:   cf.Operator,
+		Result:     result,
+		Conditions: []*Explanation{lhs, rhs},
+	}
+}
+
+// Explain - evaluates the negated condition
+func (nf *NotCondition) Explain(data Data) *Explanation {
+	condition := nf.Condition.Explain(data)
+	return &Explanation{
+		Condition:  nf.String(),
+		Operator:   token.NOT.String(),
+		Result:     !condition.Result,
+		Conditions: []*Explanation{condition},
+	}
+}
+
+// Explain - Performs the evaluation of the filter against the tags and attributes, returning the evaluation tree
+func (af *AgentFilter) Explain(tags interface{}, attributes interface{}) *Explanation {
+	if len(af.filterConditions) == 0 {
+		return &Explanation{Result: true}
+	}
+
+	fd := NewFilterData(tags, attributes)
+	if len(af.filterConditions) == 1 {
+		return af.filterConditions[0].Explain(fd)
+	}
+
+	// the filter matches when any of the conditions is true
+	explanation := &Explanation{Operator: token.LOR.String()}
+	conditions := make([]string, 0, len(af.filterConditions))
+	for _, filterCondition := range af.filterConditions {
+		conditionExplanation := filterCondition.Explain(fd)
+		explanation.Result = explanation.Result || conditionExplanation.Result
+		explanation.Conditions = append(explanation.Conditions, conditionExplanation)
+		conditions = append(conditions, filterCondition.String())
+	}
+	explanation.Condition = strings.Join(conditions, " "+token.LOR.String()+" ")
+	return explanation
+}
@@ -0,0 +1,43 @@
+package filter
+
+import (
+	"strings"
+)
+
+// InExpr - In implementation. Checks if the value of specified filter data is one of the arguments
+type InExpr struct {
+	FilterType string
+	Name       string
+	Args       []string
+}
+
+func newInExpr(filterType, name string, inArgs []string) CallExpr {
+	return &InExpr{
+		FilterType: filterType,
+		Name:       name,
+		Args:       inArgs,
+	}
+}
+
+// GetType - Returns the CallType
+func (e *InExpr) GetType() CallType {
+	return IN
+}
+
+// Execute - Returns true if the value of specified filter data is one of the arguments
+func (e *InExpr) Execute(data Data) (interface{}, error) {
+	valueToCompare, ok := data.GetValue(e.FilterType, e.Name)
+	if !ok {
+		return false, nil
+	}
+	for _, arg := range e.Args {
+		if valueToCompare == arg {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (e *InExpr) String() string {
+	return e.FilterType + "." + e.Name + ".In(\"" + strings.Join(e.Args, "\", \"") + "\")"
+}
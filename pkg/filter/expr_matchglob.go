@@ -0,0 +1,76 @@
+package filter
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// MatchGlobExpr - MatchGlob implementation. Matches the value of specified filter data with the glob pattern in argument,
+// * matches any characters, ? matches one character and [...] matches a character class
+type MatchGlobExpr struct {
+	FilterType string
+	Name       string
+	Pattern    string
+	regex      *regexp.Regexp
+}
+
+func newMatchGlobExpr(filterType, name, pattern string) (CallExpr, error) {
+	regex, err := globToRegExp(pattern)
+	if err != nil {
+		return nil, errors.New("Invalid glob pattern(" + err.Error() + ") in MatchGlob call")
+	}
+	return &MatchGlobExpr{
+		FilterType: filterType,
+		Name:       name,
+		Pattern:    pattern,
+		regex:      regex,
+	}, nil
+}
+
+// globToRegExp - converts the glob pattern to a regular expression matching the whole value
+func globToRegExp(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end == -1 {
+				return nil, errors.New("missing closing ]")
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+// GetType - Returns the CallType
+func (e *MatchGlobExpr) GetType() CallType {
+	return MATCHGLOB
+}
+
+// Execute - Returns true if the glob pattern in argument matches the value for specified filter data
+func (e *MatchGlobExpr) Execute(data Data) (interface{}, error) {
+	valueToMatch, ok := data.GetValue(e.FilterType, e.Name)
+	if !ok {
+		return false, nil
+	}
+	return e.regex.MatchString(valueToMatch), nil
+}
+
+func (e *MatchGlobExpr) String() string {
+	return e.FilterType + "." + e.Name + ".MatchGlob(\"" + e.Pattern + "\")"
+}
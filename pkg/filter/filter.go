@@ -9,6 +9,8 @@ import (
 // Filter - Interface for filter
 type Filter interface {
 	Evaluate(tags interface{}) bool
+	EvaluateWithAttributes(tags interface{}, attributes interface{}) bool
+	Explain(tags interface{}, attributes interface{}) *Explanation
 }
 
 // AgentFilter - Represents the filter
@@ -16,7 +18,7 @@ type AgentFilter struct {
 	filterConditions []Condition
 }
 
-var defaultSupportedExpr = []CallType{GETVALUE, MATCHREGEX, CONTAINS, EXISTS, ANY}
+var defaultSupportedExpr = []CallType{GETVALUE, MATCHREGEX, CONTAINS, EXISTS, ANY, IN, MATCHGLOB}
 
 var supportedExpr = defaultSupportedExpr
 
@@ -35,6 +37,10 @@ func SetSupportedCallExprTypes(callTypes []CallType) {
 		case EXISTS:
 			fallthrough
 		case ANY:
+			fallthrough
+		case IN:
+			fallthrough
+		case MATCHGLOB:
 			overriddentSupportedExpr = append(overriddentSupportedExpr, callType)
 		}
 	}
@@ -57,9 +63,15 @@ func NewFilter(filterConfig string) (filter Filter, err error) {
 }
 
 // Evaluate - Performs the evaluation of the filter against the data
-func (af *AgentFilter) Evaluate(tags interface{}) (result bool) {
+func (af *AgentFilter) Evaluate(tags interface{}) bool {
+	return af.EvaluateWithAttributes(tags, nil)
+}
+
+// EvaluateWithAttributes - Performs the evaluation of the filter against the tags and attributes, the attributes
+// are selected with attr, e.g. the ServiceAttributes of the service body
+func (af *AgentFilter) EvaluateWithAttributes(tags interface{}, attributes interface{}) (result bool) {
 	if af.filterConditions != nil && len(af.filterConditions) > 0 {
-		fd := NewFilterData(tags, nil)
+		fd := NewFilterData(tags, attributes)
 		for _, filterCondition := range af.filterConditions {
 			result = filterCondition.Evaluate(fd)
 			log.Debug("Filter condition evaluation [Condition: " + filterCondition.String() + ", Result: " + strconv.FormatBool(result) + "]")
@@ -1,6 +1,7 @@
 package filter
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -103,6 +104,78 @@ func TestCompoundFilter(t *testing.T) {
 	assertFilter(t, "tag.Any() == \"someotherval\" || tag.name2.Exists() && tag.name3.Contains(\"v-3\") || tag.name4.MatchRegEx(\"(val){1}\")", filterDataWithStringArrary, true)
 }
 
+var versionData = map[string]string{
+	"version": "2.1.0",
+	"beta":    "v3.0.0-beta.2",
+	"count":   "42",
+	"env":     "test",
+	"name":    "petstore-v2",
+}
+
+var versionAttributes = map[string]string{
+	"internal": "true",
+	"owner":    "payments",
+}
+
+func TestComparisonFilter(t *testing.T) {
+	SetSupportedCallExprTypes(defaultSupportedExpr)
+
+	// numbers
+	assertFilter(t, "tag.count > 41", versionData, true)
+	assertFilter(t, "tag.count >= 42.0", versionData, true)
+	assertFilter(t, "tag.count < 42", versionData, false)
+	assertFilter(t, "tag.count <= -1", versionData, false)
+	assertFilter(t, "tag.count == 42.0", versionData, true)
+	assertFilter(t, "tag.env > 1", versionData, false)
+
+	// semantic versions
+	assertFilter(t, "tag.version >= \"2.0\"", versionData, true)
+	assertFilter(t, "tag.version > \"2.1\"", versionData, false)
+	assertFilter(t, "tag.version < \"2.10\"", versionData, true)
+	assertFilter(t, "tag.beta < \"3.0.0\"", versionData, true)
+	assertFilter(t, "tag.beta > \"3.0.0-beta.1\"", versionData, true)
+	assertFilter(t, "tag.beta > \"3.0.0-beta.10\"", versionData, false)
+	assertFilter(t, "tag.Any() >= \"3\"", versionData, true)
+
+	// values that are not versions are not comparable
+	assertFilter(t, "tag.env < \"testing\"", versionData, false)
+	assertFilter(t, "tag.env >= \"testing\"", versionData, false)
+	assertFilter(t, "tag.env >= \"2.0\"", versionData, false)
+	assertFilter(t, "tag.missing < \"testing\"", versionData, false)
+}
+
+func TestExtendedFilter(t *testing.T) {
+	SetSupportedCallExprTypes(defaultSupportedExpr)
+
+	// not and grouping
+	assertFilter(t, "!tag.env.Exists()", versionData, false)
+	assertFilter(t, "!(tag.env == \"prod\")", versionData, true)
+	assertFilter(t, "!(tag.env == \"prod\" || tag.count > 40) && tag.version.Exists()", versionData, false)
+	assertFilter(t, "tag.name1 == \"value 1\" && (tag.name1 == \"value 1\")", filterData, true)
+
+	// in lists
+	assertFilter(t, "tag.env.In(\"dev\", \"test\")", versionData, true)
+	assertFilter(t, "tag.env.In(\"prod\")", versionData, false)
+	assertFilter(t, "tag.missing.In(\"prod\")", versionData, false)
+
+	// glob matching
+	assertFilter(t, "tag.name.MatchGlob(\"pet*\")", versionData, true)
+	assertFilter(t, "tag.name.MatchGlob(\"petstore-v?\")", versionData, true)
+	assertFilter(t, "tag.name.MatchGlob(\"petstore-v[!2]\")", versionData, false)
+	assertFilter(t, "tag.name.MatchGlob(\"store*\")", versionData, false)
+
+	// attribute selectors
+	agentFilter, err := NewFilter("tag.version >= \"2.0\" && !attr.internal.Exists()")
+	assert.Nil(t, err)
+	assert.True(t, agentFilter.Evaluate(versionData))
+	assert.False(t, agentFilter.EvaluateWithAttributes(versionData, versionAttributes))
+
+	agentFilter, err = NewFilter("attr.owner.In(\"payments\", \"orders\")")
+	assert.Nil(t, err)
+	assert.True(t, agentFilter.EvaluateWithAttributes(nil, versionAttributes))
+	assert.False(t, agentFilter.EvaluateWithAttributes(versionAttributes, nil))
+}
+
 func assertFilter(t *testing.T, filterConfig string, filterData interface{}, expectedResult bool) {
 	agentFilter, err := NewFilter(filterConfig)
 	assert.NotNil(t, agentFilter)
@@ -121,8 +194,9 @@ func TestFilterParsingError(t *testing.T) {
 	assertFilterSyntaxErr(t, "\"value\" == \"value\"", "Unrecognized condition")
 	assertFilterSyntaxErr(t, "tag.name1 & \"value\"", "Invalid operator")
 
-	// Unsupported condition grouping
-	assertFilterSyntaxErr(t, "tag.name1 == \"value 1\" && (tag.name1 == \"value 1\")", "Unrecognized expression")
+	// Unsupported conditions
+	assertFilterSyntaxErr(t, "-tag.name1.Exists()", "Invalid operator")
+	assertFilterSyntaxErr(t, "tag.name1.Exists() > true", "Invalid operator")
 	assertFilterSyntaxErr(t, "\"tag.name1 == value\"", "Unrecognized expression")
 
 	// Syntax Errors
@@ -141,10 +215,13 @@ func TestFilterParsingError(t *testing.T) {
 
 	// Missing arguments
 	assertFilterSyntaxErr(t, "tag.name.Contains()", "Syntax Error, missing argument")
+	assertFilterSyntaxErr(t, "tag.name.In()", "Syntax Error, missing argument")
+	assertFilterSyntaxErr(t, "tag.name.MatchGlob()", "Syntax Error, missing argument")
 	assertFilterSyntaxErr(t, "tag.name.MatchRegEx()", "Syntax Error, missing argument")
 
 	// Invalid Regular expression
 	assertFilterSyntaxErr(t, "tag.name.MatchRegEx(\".*[\")", "Invalid regular expression")
+	assertFilterSyntaxErr(t, "tag.name.MatchGlob(\"pet[\")", "Invalid glob pattern")
 
 }
 
@@ -168,3 +245,42 @@ func TestOverrideSupportedExpr(t *testing.T) {
 
 	assertFilterSyntaxErr(t, "tag.name1.Exists() && tag.name1.MatchRegEx(\"(val){1}\")", "unsupported condition")
 }
+
+func TestExplain(t *testing.T) {
+	SetSupportedCallExprTypes(defaultSupportedExpr)
+
+	agentFilter, err := NewFilter("tag.version >= \"2.0\" && !(attr.internal.Exists() || tag.missing == \"x\")")
+	assert.Nil(t, err)
+
+	explanation := agentFilter.Explain(versionData, versionAttributes)
+	assert.False(t, explanation.Result)
+	assert.Equal(t, "&&", explanation.Operator)
+	assert.Len(t, explanation.Conditions, 2)
+
+	version := explanation.Conditions[0]
+	assert.True(t, version.Result)
+	assert.Equal(t, "tag.version", version.LHS)
+	assert.Equal(t, "2.1.0", version.LHSValue)
+	assert.Equal(t, ">=", version.Operator)
+	assert.Equal(t, "2.0", version.RHS)
+
+	not := explanation.Conditions[1]
+	assert.False(t, not.Result)
+	assert.Equal(t, "!", not.Operator)
+	or := not.Conditions[0]
+	assert.True(t, or.Result)
+	assert.True(t, or.Conditions[0].Result)
+	assert.Equal(t, "true", or.Conditions[0].LHSValue)
+	assert.False(t, or.Conditions[1].Result)
+	assert.Contains(t, or.Conditions[1].Error, "not found")
+
+	assert.Equal(t, agentFilter.EvaluateWithAttributes(versionData, versionAttributes), explanation.Result)
+	lines := strings.Split(strings.TrimSpace(explanation.String()), "\n")
+	assert.Len(t, lines, 6)
+	assert.True(t, strings.HasPrefix(lines[1], "  true  (tag.version >= 2.0)"))
+	assert.Contains(t, lines[1], "[tag.version = \"2.1.0\"]")
+
+	// an empty filter matches
+	agentFilter, _ = NewFilter("")
+	assert.True(t, agentFilter.Explain(versionData, nil).Result)
+}
@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// version - a semantic version, missing minor and patch numbers are zero
+type version struct {
+	numbers    []int
+	prerelease []string
+}
+
+// parseVersion - parses versions like 2, 2.1, v2.1.3 and 2.1.3-beta.1, build metadata is ignored
+func parseVersion(value string) (*version, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "v")
+	if i := strings.Index(value, "+"); i != -1 {
+		value = value[:i]
+	}
+
+	v := &version{}
+	if i := strings.Index(value, "-"); i != -1 {
+		v.prerelease = strings.Split(value[i+1:], ".")
+		value = value[:i]
+	}
+	if value == "" {
+		return nil, errors.New("not a version")
+	}
+	for _, part := range strings.Split(value, ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return nil, errors.New("not a version")
+		}
+		v.numbers = append(v.numbers, number)
+	}
+	return v, nil
+}
+
+// compare - returns -1, 0 or 1 when the version is lower, equal or greater than the other version
+func (v *version) compare(other *version) int {
+	for i := 0; i < len(v.numbers) || i < len(other.numbers); i++ {
+		if res := compareInts(versionPart(v.numbers, i), versionPart(other.numbers, i)); res != 0 {
+			return res
+		}
+	}
+
+	// a release is greater than its pre-releases
+	switch {
+	case len(v.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.prerelease) && i < len(other.prerelease); i++ {
+		if res := comparePrereleaseIdentifiers(v.prerelease[i], other.prerelease[i]); res != 0 {
+			return res
+		}
+	}
+	return compareInts(len(v.prerelease), len(other.prerelease))
+}
+
+func versionPart(numbers []int, i int) int {
+	if i < len(numbers) {
+		return numbers[i]
+	}
+	return 0
+}
+
+// comparePrereleaseIdentifiers - numeric identifiers are compared numerically and are lower than alphanumeric ones
+func comparePrereleaseIdentifiers(a, b string) int {
+	aNumber, aErr := strconv.Atoi(a)
+	bNumber, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInts(aNumber, bNumber)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
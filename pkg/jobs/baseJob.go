@@ -9,16 +9,18 @@ import (
 
 type baseJob struct {
 	JobExecution
-	id         string       // UUID generated for this job
-	job        Job          // the job definition
-	status     JobStatus    // current job status
-	err        error        // the error thrown
-	statusLock sync.RWMutex // lock on preventing status write/read at the same time
-	failChan   chan string  // channel to send signal to pool of failure
-	jobLock    sync.Mutex   // lock used for signalling that the job is being executed
+	id         string        // UUID generated for this job
+	job        Job           // the job definition
+	status     JobStatus     // current job status
+	err        error         // the error thrown
+	statusLock sync.RWMutex  // lock on preventing status write/read at the same time
+	failChan   chan string   // channel to send signal to pool of failure
+	policy     FailurePolicy // what the pool does when the continuous job fails
+	jobLock    sync.RWMutex  // lock used for signalling that the job is being executed
+	stats      jobStats      // the execution metrics of the job
 }
 
-//newBaseJob - creates a single run job and sets up the structure for different job types
+// newBaseJob - creates a single run job and sets up the structure for different job types
 func newBaseJob(newJob Job, failJobChan chan string) (JobExecution, error) {
 	thisJob := baseJob{
 		id:       newUUID(),
@@ -32,7 +34,9 @@ func newBaseJob(newJob Job, failJobChan chan string) (JobExecution, error) {
 }
 
 func (b *baseJob) executeJob() {
+	start := b.stats.executionStarted()
 	b.err = b.job.Execute()
+	b.stats.executionEnded(start, b.err)
 	b.SetStatus(JobStatusFinished)
 	if b.err != nil {
 		b.SetStatus(JobStatusFailed)
@@ -47,31 +51,63 @@ func (b *baseJob) executeCronJob() {
 	b.jobLock.Lock()
 	defer b.jobLock.Unlock()
 
+	start := b.stats.executionStarted()
 	b.err = b.job.Execute()
+	b.stats.executionEnded(start, b.err)
 	if b.err != nil {
+		// set before the pool handles the failure, the pool stops the job unless the failure is isolated
+		b.SetStatus(b.getFailedStatus())
 		b.failChan <- b.id
-		b.SetStatus(JobStatusFailed)
 	}
 }
 
-//SetStatus - locks the job, execution can not take place until the Unlock func is called
+// executeSharedCronJob - executes the job like executeCronJob, alongside the other executions of the job
+func (b *baseJob) executeSharedCronJob() error {
+	// check status before execute
+	b.updateStatus()
+
+	// Lock the mutex for external syn with the job, shared with the other executions
+	b.jobLock.RLock()
+	defer b.jobLock.RUnlock()
+
+	start := b.stats.executionStarted()
+	err := b.job.Execute()
+	b.stats.executionEnded(start, err)
+	if err != nil {
+		b.SetStatus(b.getFailedStatus())
+		b.failChan <- b.id
+	}
+	return err
+}
+
+// getFailedStatus - returns the status of the continuous job after a failed execution, the job keeps running when its
+//
+//	failure is isolated
+func (b *baseJob) getFailedStatus() JobStatus {
+	if b.policy == FailurePolicyIsolate {
+		return JobStatusRunning
+	}
+	return JobStatusFailed
+}
+
+// SetStatus - locks the job, execution can not take place until the Unlock func is called
 func (b *baseJob) SetStatus(status JobStatus) {
 	b.statusLock.Lock()
 	defer b.statusLock.Unlock()
 	b.status = status
 }
 
-//Lock - locks the job, execution can not take place until the Unlock func is called
+// Lock - locks the job, execution can not take place until the Unlock func is called
 func (b *baseJob) Lock() {
 	b.jobLock.Lock()
 }
 
-//Unlock - unlocks the job, execution can now take place
+// Unlock - unlocks the job, execution can now take place
 func (b *baseJob) Unlock() {
 	b.jobLock.Unlock()
 }
 
-//GetStatusValue - returns the job status
+// GetStatusValue - returns the job status
 func (b *baseJob) updateStatus() JobStatus {
 	newStatus := JobStatusRunning // reset to running before checking
 	jobStatus := b.job.Status()   // get the current status
@@ -85,36 +121,48 @@ func (b *baseJob) updateStatus() JobStatus {
 	return b.status
 }
 
-//GetStatusValue - returns the job status
+// GetStatusValue - returns the job status
 func (b *baseJob) GetStatus() JobStatus {
 	b.statusLock.Lock()
 	defer b.statusLock.Unlock()
 	return b.status
 }
 
-//GetID - returns the ID for this job
+// GetID - returns the ID for this job
 func (b *baseJob) GetID() string {
 	return b.id
 }
 
-//GetJob - returns the Job interface
+// GetJob - returns the Job interface
 func (b *baseJob) GetJob() JobExecution {
 	return b
 }
 
-//Ready - checks that the job is ready
+// Ready - checks that the job is ready
 func (b *baseJob) Ready() bool {
 	return b.job.Ready()
 }
 
-//waitForReady - waits for the Ready func to return true
+// waitForReady - waits for the Ready func to return true
 func (b *baseJob) waitForReady() {
 	for !b.job.Ready() { // Wait for the job to be ready before starting
 		time.Sleep(time.Millisecond)
 	}
 }
 
-//start - waits for Ready to return true then calls the Execute function from the Job definition
+// waitForReadyOrStop - waits for the Ready func to return true, returns false when the job is stopped first
+func (b *baseJob) waitForReadyOrStop(stopChan chan bool) bool {
+	for !b.job.Ready() {
+		select {
+		case <-stopChan:
+			return false
+		case <-time.After(time.Millisecond):
+		}
+	}
+	return true
+}
+
+// start - waits for Ready to return true then calls the Execute function from the Job definition
 func (b *baseJob) start() {
 	log.Debugf("Starting %v job %v", JobTypeSingleRun, b.id)
 	b.waitForReady()
@@ -123,7 +171,7 @@ func (b *baseJob) start() {
 	b.executeJob()
 }
 
-//stop - noop in base
+// stop - noop in base
 func (b *baseJob) stop() {
 	log.Debugf("Stopping %v job %v", JobTypeSingleRun, b.id)
 	return
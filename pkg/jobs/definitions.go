@@ -1,6 +1,9 @@
 package jobs
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 const defaultRetryInterval = 30 * time.Second
 
@@ -11,6 +14,13 @@ type Job interface {
 	Ready() bool
 }
 
+//JobWithContext - a job executed with the context of the pool instead of calling Execute, the context is canceled
+//                 when the pool is shut down
+type JobWithContext interface {
+	Job
+	ExecuteWithContext(ctx context.Context) error
+}
+
 //JobExecution - the wrapper interface for every type of job
 //               controls the calling the methods defined in the Job interface
 type JobExecution interface {
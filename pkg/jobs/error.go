@@ -7,4 +7,12 @@ var (
 	ErrRegisteringJob    = errors.Newf(1600, "%v job registration failed")
 	ErrExecutingJob      = errors.Newf(1601, "Error in %v job %v execution")
 	ErrExecutingRetryJob = errors.Newf(1602, "Error in %v job %v execution, %v more retries")
+	ErrUpdatingJob       = errors.Newf(1603, "%v job %v can not be updated")
+	ErrJobDependency     = errors.Newf(1604, "job dependency %v is not registered")
+	ErrJobNotFound       = errors.Newf(1605, "job %v not found")
+	ErrJobControl        = errors.Newf(1606, "%v job %v can not be %v")
+	ErrJobNameNotUnique  = errors.Newf(1607, "several jobs have the name %v, use the job id")
+	ErrScheduleStateFile = errors.Newf(1608, "could not open the schedule state file %v")
+	ErrPoolShutdown      = errors.New(1609, "the jobs are shut down, the job can not be registered")
+	ErrShutdownTimeout   = errors.New(1610, "the jobs did not stop before the shutdown deadline")
 )
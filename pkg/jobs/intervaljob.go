@@ -1,6 +1,7 @@
 package jobs
 
 import (
+	"sync"
 	"time"
 
 	"github.com/Axway/agent-sdk/pkg/util/errors"
@@ -8,8 +9,11 @@ import (
 )
 
 type intervalJobProps struct {
-	interval time.Duration
-	stopChan chan bool
+	interval     time.Duration
+	intervalLock sync.RWMutex
+	stopChan     chan bool
+	updateChan   chan bool
+	triggerChan  chan bool
 }
 
 type intervalJob struct {
@@ -18,17 +22,20 @@ type intervalJob struct {
 }
 
 //newBaseJob - creates a single run job and sets up the structure for different job types
-func newIntervalJob(newJob Job, interval time.Duration, failJobChan chan string) (JobExecution, error) {
+func newIntervalJob(newJob Job, interval time.Duration, policy FailurePolicy, failJobChan chan string) (JobExecution, error) {
 	thisJob := intervalJob{
 		baseJob{
 			id:       newUUID(),
 			job:      newJob,
 			status:   JobStatusInitializing,
 			failChan: failJobChan,
+			policy:   policy,
 		},
 		intervalJobProps{
-			interval: interval,
-			stopChan: make(chan bool),
+			interval:    interval,
+			stopChan:    make(chan bool),
+			updateChan:  make(chan bool, 1),
+			triggerChan: make(chan bool, 1),
 		},
 	}
 
@@ -42,37 +49,75 @@ func (b *intervalJob) handleExecution() {
 	if b.err != nil {
 		b.err = errors.Wrap(ErrExecutingJob, b.err.Error()).FormatError(JobTypeInterval, b.id)
 		log.Error(b.err)
-		b.SetStatus(JobStatusStopped)
 	}
 }
 
 //start - calls the Execute function from the Job definition
 func (b *intervalJob) start() {
 	log.Debugf("Starting %v job %v", JobTypeInterval, b.id)
-	b.waitForReady()
+	if !b.waitForReadyOrStop(b.stopChan) {
+		return
+	}
 
 	// Execute the job now and then start the interval period
 	b.handleExecution()
 
-	ticker := time.NewTicker(b.interval)
+	ticker := time.NewTicker(b.getInterval())
 	defer ticker.Stop()
 	b.SetStatus(JobStatusRunning)
 	for {
 		// Non-blocking channel read, if stopped then exit
 		select {
 		case <-b.stopChan:
-			b.SetStatus(JobStatusStopped)
 			return
+		case <-b.updateChan:
+			ticker.Stop()
+			ticker = time.NewTicker(b.getInterval())
+		case <-b.triggerChan:
+			b.handleExecution()
+			ticker.Stop()
+			ticker = time.NewTicker(b.getInterval())
 		case <-ticker.C:
 			b.handleExecution()
 			ticker.Stop()
-			ticker = time.NewTicker(b.interval)
+			ticker = time.NewTicker(b.getInterval())
 		}
 	}
 }
 
+//getInterval - returns the interval between the executions
+func (b *intervalJob) getInterval() time.Duration {
+	b.intervalLock.RLock()
+	defer b.intervalLock.RUnlock()
+	return b.interval
+}
+
+//setInterval - changes the interval, the next execution is an interval after the change
+func (b *intervalJob) setInterval(interval time.Duration) {
+	b.intervalLock.Lock()
+	b.interval = interval
+	b.intervalLock.Unlock()
+
+	// Non-blocking channel write, the execution loop resets its ticker
+	select {
+	case b.updateChan <- true:
+	default:
+	}
+}
+
+//trigger - executes the job now, the next execution is an interval after this one
+func (b *intervalJob) trigger() {
+	// Non-blocking channel write, a trigger is already pending otherwise
+	select {
+	case b.triggerChan <- true:
+	default:
+	}
+}
+
 //stop - write to the stop channel to stop the execution loop
 func (b *intervalJob) stop() {
 	log.Debugf("Stopping %v job %v", JobTypeInterval, b.id)
 	b.stopChan <- true
+	// set once the execution loop exited, a restarted loop sets its own status
+	b.SetStatus(JobStatusStopped)
 }
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"github.com/Axway/agent-sdk/pkg/util/metrics"
+)
+
+//collectMetrics - returns the run and failure counts and the execution duration histogram of the jobs in the pool
+func (p *Pool) collectMetrics() []metrics.Family {
+	runs := metrics.Family{Name: "agent_job_runs", Help: "The executions of the job", Type: metrics.CounterType}
+	failures := metrics.Family{Name: "agent_job_failures", Help: "The executions of the job that returned an error", Type: metrics.CounterType}
+	paused := metrics.Family{Name: "agent_job_paused", Help: "1 when the job is paused, 0 otherwise", Type: metrics.GaugeType}
+	durations := metrics.Family{Name: "agent_job_duration_seconds", Help: "The execution durations of the job", Type: metrics.HistogramType}
+
+	for _, info := range p.GetJobsInfo() {
+		labels := metrics.Labels{"job": info.Name, "type": info.Type}
+		runs.Samples = append(runs.Samples, metrics.Sample{Labels: labels, Value: float64(info.RunCount)})
+		failures.Samples = append(failures.Samples, metrics.Sample{Labels: labels, Value: float64(info.FailureCount)})
+		pausedValue := 0.0
+		if info.Paused {
+			pausedValue = 1
+		}
+		paused.Samples = append(paused.Samples, metrics.Sample{Labels: labels, Value: pausedValue})
+
+		// the durations of the info are cumulative, the last bucket counts all executions
+		duration := metrics.Sample{
+			Labels:  labels,
+			Buckets: make([]metrics.Bucket, 0, len(durationBuckets)),
+			Count:   uint64(info.RunCount),
+			Sum:     info.DurationSum.Seconds(),
+		}
+		for i, upper := range durationBuckets {
+			duration.Buckets = append(duration.Buckets, metrics.Bucket{UpperBound: upper.Seconds(), Count: uint64(info.Durations[i].Count)})
+		}
+		durations.Samples = append(durations.Samples, duration)
+	}
+	return []metrics.Family{runs, failures, paused, durations}
+}
@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"context"
+	"time"
+)
+
+//DefaultJobGroup - the group of the jobs registered without a group
+const DefaultJobGroup = "default"
+
+const (
+	defaultMinRestartBackoff = time.Second
+	defaultMaxRestartBackoff = 5 * time.Minute
+)
+
+//FailurePolicy - integer to represent what the pool does when a continuous job fails
+type FailurePolicy int
+
+const (
+	//FailurePolicyEscalate - stops all jobs in the group of the failed job, restarted once they are all ready
+	FailurePolicyEscalate FailurePolicy = iota
+	//FailurePolicyIsolate - records the failure, the job keeps running on its interval or schedule
+	FailurePolicyIsolate
+	//FailurePolicyRestart - stops the failed job, restarted after a backoff doubled on each consecutive failure
+	FailurePolicyRestart
+)
+
+//failurePolicyToString - maps the FailurePolicy integer to a string representation
+var failurePolicyToString = map[FailurePolicy]string{
+	FailurePolicyEscalate: "Escalate",
+	FailurePolicyIsolate:  "Isolate",
+	FailurePolicyRestart:  "Restart",
+}
+
+func (p FailurePolicy) String() string {
+	return failurePolicyToString[p]
+}
+
+//jobOptions - the options of a job in the pool
+type jobOptions struct {
+	name         string
+	named        bool // the name was set with WithJobName
+	policy       FailurePolicy
+	group        string
+	dependencies []string
+	timeZone     string
+	jitter       time.Duration
+	catchUp      CatchUpPolicy
+	overlap      OverlapPolicy
+}
+
+//JobOption - an option of a job registration
+type JobOption func(*jobOptions)
+
+//WithJobName - sets the name of the job, reported in the job info, the name of the job type by default
+func WithJobName(name string) JobOption {
+	return func(o *jobOptions) {
+		o.name = name
+	}
+}
+
+//WithFailurePolicy - sets what the pool does when the continuous job fails, FailurePolicyEscalate by default
+func WithFailurePolicy(policy FailurePolicy) JobOption {
+	return func(o *jobOptions) {
+		o.policy = policy
+	}
+}
+
+//WithJobGroup - sets the group of the job, the jobs of a group are stopped together when one escalates its failure
+func WithJobGroup(group string) JobOption {
+	return func(o *jobOptions) {
+		if group != "" {
+			o.group = group
+		}
+	}
+}
+
+//WithJobDependencies - sets the jobs the job depends on, the job does not start until they are running and is
+//                      stopped when one of them is stopped by the pool
+func WithJobDependencies(jobIDs ...string) JobOption {
+	return func(o *jobOptions) {
+		o.dependencies = append(o.dependencies, jobIDs...)
+	}
+}
+
+func newJobOptions(opts []JobOption) *jobOptions {
+	options := &jobOptions{
+		policy: FailurePolicyEscalate,
+		group:  DefaultJobGroup,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+//jobFailures - the consecutive failures of a job, used for the restart backoff
+type jobFailures struct {
+	count       int
+	lastFailure time.Time
+}
+
+//dependentJob - wraps the job definition, the job is ready once the jobs it depends on are running
+type dependentJob struct {
+	Job
+	pool         *Pool
+	dependencies []string
+}
+
+//Ready - checks that the jobs the job depends on are running, then that the job is ready
+func (d *dependentJob) Ready() bool {
+	return d.pool.dependenciesRunning(d.dependencies) && d.Job.Ready()
+}
+
+//contextJob - wraps the job definition, the job is executed with the context of the pool
+type contextJob struct {
+	JobWithContext
+	pool *Pool
+}
+
+//Execute - executes the job with the context of the pool
+func (c *contextJob) Execute() error {
+	return c.ExecuteWithContext(c.pool.getContext())
+}
+
+//ExecuteWithContext - executes the job with the context
+func (c *contextJob) ExecuteWithContext(ctx context.Context) error {
+	return c.JobWithContext.ExecuteWithContext(ctx)
+}
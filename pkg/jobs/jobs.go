@@ -1,32 +1,54 @@
 package jobs
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/util/metrics"
+)
 
 //globalPool - the default job pool
 var globalPool *Pool
 
 func init() {
 	globalPool = newPool()
+	metrics.RegisterCollector("jobs", globalPool.collectMetrics)
 }
 
 //RegisterSingleRunJob - Runs a single run job in the globalPool
-func RegisterSingleRunJob(newJob Job) (string, error) {
-	return globalPool.RegisterSingleRunJob(newJob)
+func RegisterSingleRunJob(newJob Job, opts ...JobOption) (string, error) {
+	return globalPool.RegisterSingleRunJob(newJob, opts...)
 }
 
 //RegisterIntervalJob - Runs a job with a specific interval between each run in the globalPool
-func RegisterIntervalJob(newJob Job, interval time.Duration) (string, error) {
-	return globalPool.RegisterIntervalJob(newJob, interval)
+func RegisterIntervalJob(newJob Job, interval time.Duration, opts ...JobOption) (string, error) {
+	return globalPool.RegisterIntervalJob(newJob, interval, opts...)
 }
 
 //RegisterScheduledJob - Runs a job on a specific schedule in the globalPool
-func RegisterScheduledJob(newJob Job, schedule string) (string, error) {
-	return globalPool.RegisterScheduledJob(newJob, schedule)
+func RegisterScheduledJob(newJob Job, schedule string, opts ...JobOption) (string, error) {
+	return globalPool.RegisterScheduledJob(newJob, schedule, opts...)
+}
+
+//SetScheduleStateFile - Persists the last execution times of the scheduled jobs to the file, so that the executions
+//                       missed while the agent was stopped are caught up per the catch-up policy of the jobs
+func SetScheduleStateFile(stateFile string) error {
+	return globalPool.SetScheduleStateFile(stateFile)
+}
+
+//SetContext - Sets the parent of the context of the job executions in the globalPool
+func SetContext(ctx context.Context) {
+	globalPool.SetContext(ctx)
+}
+
+//Shutdown - Stops all continuous jobs in the globalPool, waiting for their running executions until the context is done
+func Shutdown(ctx context.Context) error {
+	return globalPool.Shutdown(ctx)
 }
 
 //RegisterRetryJob - Runs a job with a limited number of retries in the globalPool
-func RegisterRetryJob(newJob Job, retries int) (string, error) {
-	return globalPool.RegisterRetryJob(newJob, retries)
+func RegisterRetryJob(newJob Job, retries int, opts ...JobOption) (string, error) {
+	return globalPool.RegisterRetryJob(newJob, retries, opts...)
 }
 
 //UnregisterJob - Removes the specified job in the globalPool
@@ -34,6 +56,46 @@ func UnregisterJob(jobID string) {
 	globalPool.UnregisterJob(jobID)
 }
 
+//UpdateJobInterval - Changes the interval of an interval job in the globalPool
+func UpdateJobInterval(jobID string, interval time.Duration) error {
+	return globalPool.UpdateJobInterval(jobID, interval)
+}
+
+//UpdateJobSchedule - Changes the schedule of a scheduled job in the globalPool
+func UpdateJobSchedule(jobID string, schedule string) error {
+	return globalPool.UpdateJobSchedule(jobID, schedule)
+}
+
+//TriggerJob - Executes the continuous job now in the globalPool
+func TriggerJob(jobID string) error {
+	return globalPool.TriggerJob(jobID)
+}
+
+//PauseJob - Stops the continuous job in the globalPool until it is resumed
+func PauseJob(jobID string) error {
+	return globalPool.PauseJob(jobID)
+}
+
+//ResumeJob - Restarts the paused job in the globalPool
+func ResumeJob(jobID string) error {
+	return globalPool.ResumeJob(jobID)
+}
+
+//FindJobID - Returns the id of the job with the id or name in the globalPool
+func FindJobID(idOrName string) (string, error) {
+	return globalPool.FindJobID(idOrName)
+}
+
+//GetJobInfo - Returns the definition and the execution metrics of the job in the globalPool
+func GetJobInfo(jobID string) (JobInfo, error) {
+	return globalPool.GetJobInfo(jobID)
+}
+
+//GetJobsInfo - Returns the definition and the execution metrics of all jobs in the globalPool
+func GetJobsInfo() []JobInfo {
+	return globalPool.GetJobsInfo()
+}
+
 //JobLock - Locks the job, returns when the lock is granted
 func JobLock(id string) {
 	globalPool.JobLock(id)
@@ -0,0 +1,165 @@
+package jobs
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Axway/agent-sdk/pkg/cache"
+	"github.com/Axway/agent-sdk/pkg/util/errors"
+)
+
+//maxCatchUpExecutions - the most executions a scheduled job runs to catch up with the missed ones
+const maxCatchUpExecutions = 100
+
+//CatchUpPolicy - integer to represent what a scheduled job does with the executions missed while it was not running
+type CatchUpPolicy int
+
+const (
+	//CatchUpNone - default, the missed executions are skipped, the job runs at the next time of its schedule
+	CatchUpNone CatchUpPolicy = iota
+	//CatchUpOnce - the job runs once when it starts if any execution was missed
+	CatchUpOnce
+	//CatchUpAll - the job runs once per missed execution when it starts, up to 100 executions
+	CatchUpAll
+)
+
+//catchUpPolicyToString - maps the CatchUpPolicy integer to a string representation
+var catchUpPolicyToString = map[CatchUpPolicy]string{
+	CatchUpNone: "None",
+	CatchUpOnce: "Once",
+	CatchUpAll:  "All",
+}
+
+func (p CatchUpPolicy) String() string {
+	return catchUpPolicyToString[p]
+}
+
+//OverlapPolicy - integer to represent what a scheduled job does when its next time comes while it is still executing
+type OverlapPolicy int
+
+const (
+	//OverlapSkip - default, the execution is skipped, the job runs at the next time of its schedule
+	OverlapSkip OverlapPolicy = iota
+	//OverlapQueue - one execution is queued, it runs once the current execution ends
+	OverlapQueue
+	//OverlapAllow - the execution runs alongside the current one
+	OverlapAllow
+)
+
+//overlapPolicyToString - maps the OverlapPolicy integer to a string representation
+var overlapPolicyToString = map[OverlapPolicy]string{
+	OverlapSkip:  "Skip",
+	OverlapQueue: "Queue",
+	OverlapAllow: "Allow",
+}
+
+func (p OverlapPolicy) String() string {
+	return overlapPolicyToString[p]
+}
+
+//WithTimeZone - sets the IANA time zone of the schedule of a scheduled job, e.g. Europe/Paris, the local time zone by default
+func WithTimeZone(timeZone string) JobOption {
+	return func(o *jobOptions) {
+		o.timeZone = timeZone
+	}
+}
+
+//WithJitter - delays each execution of a scheduled job by a random duration up to the jitter, none by default
+func WithJitter(jitter time.Duration) JobOption {
+	return func(o *jobOptions) {
+		if jitter > 0 {
+			o.jitter = jitter
+		}
+	}
+}
+
+//WithCatchUp - sets what a scheduled job does with the executions missed while it was not running, CatchUpNone by default
+func WithCatchUp(policy CatchUpPolicy) JobOption {
+	return func(o *jobOptions) {
+		o.catchUp = policy
+	}
+}
+
+//WithOverlap - sets what a scheduled job does when its next time comes while it is still executing, OverlapSkip by default
+func WithOverlap(policy OverlapPolicy) JobOption {
+	return func(o *jobOptions) {
+		o.overlap = policy
+	}
+}
+
+//getLocation - returns the location of the time zone of the options, the local time zone when not set
+func (o *jobOptions) getLocation() (*time.Location, error) {
+	if o.timeZone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(o.timeZone)
+}
+
+//getStateKey - returns the name the last execution time of a scheduled job is kept by, empty when the job does not
+//              catch up
+func (o *jobOptions) getStateKey() string {
+	if o.catchUp == CatchUpNone {
+		return ""
+	}
+	return o.name
+}
+
+//scheduleState - the last execution times of the scheduled jobs, by job name, persisted once a state file is opened
+type scheduleState struct {
+	store cache.Cache
+	lock  sync.RWMutex
+}
+
+func newScheduleState() *scheduleState {
+	return &scheduleState{store: cache.New()}
+}
+
+//open - persists the execution times to the state file, the times already in the file are loaded
+func (s *scheduleState) open(stateFile string) error {
+	if dir := filepath.Dir(stateFile); dir != "" {
+		os.MkdirAll(dir, 0700)
+	}
+	backend, err := cache.NewLogBackend(stateFile)
+	if err != nil {
+		return errors.Wrap(ErrScheduleStateFile, err.Error()).FormatError(stateFile)
+	}
+
+	store := cache.New(cache.WithBackend(backend))
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	// keep the times recorded before the file was opened
+	for _, name := range s.store.GetKeys() {
+		if data, err := s.store.Get(name); err == nil {
+			if _, err := store.Get(name); err != nil {
+				store.Set(name, data)
+			}
+		}
+	}
+	s.store = store
+	return nil
+}
+
+//getLastRun - returns the scheduled time of the last execution of the job, zero when not known
+func (s *scheduleState) getLastRun(name string) time.Time {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	data, err := s.store.Get(name)
+	if err != nil {
+		return time.Time{}
+	}
+	value, _ := data.(string)
+	lastRun, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return lastRun
+}
+
+//setLastRun - records the scheduled time of the last execution of the job
+func (s *scheduleState) setLastRun(name string, lastRun time.Time) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	s.store.Set(name, lastRun.UTC().Format(time.RFC3339Nano))
+}
@@ -0,0 +1,132 @@
+package jobs
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+//durationBuckets - the upper bounds of the buckets of the execution duration histogram
+var durationBuckets = []time.Duration{
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	5 * time.Minute,
+}
+
+//DurationBucket - the number of executions that took at most the duration, the last bucket counts all executions
+type DurationBucket struct {
+	LessOrEqual string `json:"le"`
+	Count       int    `json:"count"`
+}
+
+//JobInfo - the definition and the execution metrics of a job
+type JobInfo struct {
+	ID            string           `json:"id"`
+	Name          string           `json:"name"`
+	Type          string           `json:"type"`
+	Status        string           `json:"status"`
+	Paused        bool             `json:"paused"`
+	Group         string           `json:"group,omitempty"`
+	FailurePolicy string           `json:"failurePolicy,omitempty"`
+	Interval      time.Duration    `json:"interval,omitempty"`
+	Schedule      string           `json:"schedule,omitempty"`
+	RunCount      int              `json:"runCount"`
+	FailureCount  int              `json:"failureCount"`
+	LastStart     *time.Time       `json:"lastStart,omitempty"`
+	LastEnd       *time.Time       `json:"lastEnd,omitempty"`
+	LastDuration  time.Duration    `json:"lastDuration"`
+	LastError     string           `json:"lastError,omitempty"`
+	DurationSum   time.Duration    `json:"durationSum"`
+	Durations     []DurationBucket `json:"durations"`
+}
+
+//jobStats - the execution metrics of a job
+type jobStats struct {
+	lock         sync.Mutex
+	runCount     int
+	failureCount int
+	lastStart    time.Time
+	lastEnd      time.Time
+	lastDuration time.Duration
+	lastError    string
+	durationSum  time.Duration
+	buckets      []int // a count per duration bucket, then the count of the executions longer than the last bucket
+}
+
+//executionStarted - records the start of an execution
+func (s *jobStats) executionStarted() time.Time {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.lastStart = time.Now()
+	return s.lastStart
+}
+
+//executionEnded - records the end of the execution that started at the time
+func (s *jobStats) executionEnded(start time.Time, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.lastEnd = time.Now()
+	s.lastDuration = s.lastEnd.Sub(start)
+	s.durationSum += s.lastDuration
+	s.runCount++
+	s.lastError = ""
+	if err != nil {
+		s.failureCount++
+		s.lastError = err.Error()
+	}
+
+	if s.buckets == nil {
+		s.buckets = make([]int, len(durationBuckets)+1)
+	}
+	bucket := len(durationBuckets)
+	for i, upper := range durationBuckets {
+		if s.lastDuration <= upper {
+			bucket = i
+			break
+		}
+	}
+	s.buckets[bucket]++
+}
+
+//fillInfo - sets the execution metrics of the job info
+func (s *jobStats) fillInfo(info *JobInfo) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	info.RunCount = s.runCount
+	info.FailureCount = s.failureCount
+	info.LastDuration = s.lastDuration
+	info.LastError = s.lastError
+	info.DurationSum = s.durationSum
+	if !s.lastStart.IsZero() {
+		lastStart := s.lastStart
+		info.LastStart = &lastStart
+	}
+	if !s.lastEnd.IsZero() {
+		lastEnd := s.lastEnd
+		info.LastEnd = &lastEnd
+	}
+
+	// cumulative counts
+	info.Durations = make([]DurationBucket, 0, len(durationBuckets)+1)
+	count := 0
+	for i, upper := range durationBuckets {
+		if s.buckets != nil {
+			count += s.buckets[i]
+		}
+		info.Durations = append(info.Durations, DurationBucket{LessOrEqual: upper.String(), Count: count})
+	}
+	info.Durations = append(info.Durations, DurationBucket{LessOrEqual: "+Inf", Count: s.runCount})
+}
+
+//defaultJobName - the name of the job type, used when the job is registered without a name
+func defaultJobName(job Job) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", job), "*")
+}
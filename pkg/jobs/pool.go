@@ -1,26 +1,45 @@
 package jobs
 
 import (
+	"context"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	corecfg "github.com/Axway/agent-sdk/pkg/config"
+	"github.com/Axway/agent-sdk/pkg/util/errors"
 	"github.com/Axway/agent-sdk/pkg/util/log"
 )
 
 var statusConfig corecfg.StatusConfig
 
-//Pool - represents a pool of jobs that are related in such a way that when one is not running none of them should be
+//Pool - represents a pool of jobs, the failure of a continuous job is handled per its failure policy, the jobs of a
+//       group are stopped together when one of them escalates its failure
 type Pool struct {
-	jobs            map[string]JobExecution // All jobs that are in this pool
-	cronJobs        map[string]JobExecution // Jobs that run continuously, not just ran once
-	poolStatus      PoolStatus              // Holds the current status of the pool of jobs
-	failedJob       string                  // Holds the ID of the job that is the reason for a non-running status
-	jobsMapLock     sync.Mutex
-	cronJobsMapLock sync.Mutex
-	failJobChan     chan string
-	stopJobsChan    chan bool
-	retryInterval   time.Duration
+	jobs              map[string]JobExecution // All jobs that are in this pool
+	jobNames          map[string]string       // The names of the jobs
+	cronJobs          map[string]JobExecution // Jobs that run continuously, not just ran once
+	jobOptions        map[string]*jobOptions  // The failure policy, group and dependencies of the jobs
+	stoppedJobs       map[string]bool         // The continuous jobs stopped by the pool, waiting to be restarted
+	stoppedGroups     map[string]bool         // The groups stopped by the pool, waiting for all their jobs to be ready
+	pausedJobs        map[string]bool         // The continuous jobs paused, not restarted until resumed
+	groupStarts       map[string]time.Time    // The last restart of the groups, failures are deferred for the retry interval
+	deferredGroups    map[string]bool         // The groups with a deferred failure
+	failures          map[string]*jobFailures // The consecutive failures of the jobs
+	scheduleState     *scheduleState          // The last execution times of the scheduled jobs
+	ctx               context.Context         // The context of the job executions, canceled on shutdown
+	cancel            context.CancelFunc      // Cancels the context of the job executions
+	ctxLock           sync.RWMutex            // Lock on the context of the job executions
+	isShutdown        bool                    // The jobs are stopped, none is registered or restarted
+	poolStatus        PoolStatus              // Holds the current status of the pool of jobs
+	failedJob         string                  // Holds the ID of the job that is the reason for a non-running status
+	jobsMapLock       sync.Mutex
+	cronJobsMapLock   sync.Mutex
+	failJobChan       chan string
+	retryInterval     time.Duration
+	minRestartBackoff time.Duration
+	maxRestartBackoff time.Duration
 }
 
 func newPool() *Pool {
@@ -29,20 +48,31 @@ func newPool() *Pool {
 		interval = GetStatusConfig().GetHealthCheckInterval()
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
 	newPool := Pool{
-		jobs:          make(map[string]JobExecution),
-		cronJobs:      make(map[string]JobExecution),
-		poolStatus:    PoolStatusInitializing,
-		failedJob:     "",
-		failJobChan:   make(chan string),
-		stopJobsChan:  make(chan bool),
-		retryInterval: interval,
+		jobs:              make(map[string]JobExecution),
+		jobNames:          make(map[string]string),
+		cronJobs:          make(map[string]JobExecution),
+		jobOptions:        make(map[string]*jobOptions),
+		stoppedJobs:       make(map[string]bool),
+		stoppedGroups:     make(map[string]bool),
+		pausedJobs:        make(map[string]bool),
+		groupStarts:       make(map[string]time.Time),
+		deferredGroups:    make(map[string]bool),
+		failures:          make(map[string]*jobFailures),
+		scheduleState:     newScheduleState(),
+		ctx:               ctx,
+		cancel:            cancel,
+		poolStatus:        PoolStatusRunning,
+		failedJob:         "",
+		failJobChan:       make(chan string),
+		retryInterval:     interval,
+		minRestartBackoff: defaultMinRestartBackoff,
+		maxRestartBackoff: defaultMaxRestartBackoff,
 	}
 
-	// start routine that catches all failures whenever written and acts on first
+	// start routine that catches all failures whenever written and acts on them
 	go newPool.catchFails()
-	// start the pool watcher
-	go newPool.watchJobs()
 
 	return &newPool
 }
@@ -58,66 +88,218 @@ func GetStatusConfig() corecfg.StatusConfig {
 }
 
 //recordJob - Adds a job to the jobs map
-func (p *Pool) recordJob(job JobExecution) string {
+func (p *Pool) recordJob(job JobExecution, name string) string {
 	p.jobsMapLock.Lock()
 	defer p.jobsMapLock.Unlock()
 	p.jobs[job.GetID()] = job
+	p.jobNames[job.GetID()] = name
 	return job.GetID()
 }
 
 //recordCronJob - Adds a job to the cron jobs map
-func (p *Pool) recordCronJob(job JobExecution) string {
+func (p *Pool) recordCronJob(job JobExecution, options *jobOptions) string {
 	p.cronJobsMapLock.Lock()
 	defer p.cronJobsMapLock.Unlock()
 	p.cronJobs[job.GetID()] = job
-	return p.recordJob(job)
+	p.jobOptions[job.GetID()] = options
+	return p.recordJob(job, options.name)
 }
 
 //recordJob - Removes the specified job from jobs map
 func (p *Pool) removeJob(jobID string) {
+	p.cronJobsMapLock.Lock()
+	stopped := p.stoppedJobs[jobID]
+	delete(p.cronJobs, jobID)
+	delete(p.jobOptions, jobID)
+	delete(p.stoppedJobs, jobID)
+	delete(p.pausedJobs, jobID)
+	delete(p.failures, jobID)
+	p.cronJobsMapLock.Unlock()
+
 	p.jobsMapLock.Lock()
 	defer p.jobsMapLock.Unlock()
 	job, ok := p.jobs[jobID]
 	if ok {
-		job.stop()
+		if !stopped {
+			job.stop()
+		}
 		delete(p.jobs, jobID)
+		delete(p.jobNames, jobID)
+	}
+}
+
+//prepareJob - validates the options of the job, the job waits for the jobs it depends on
+func (p *Pool) prepareJob(newJob Job, opts []JobOption) (Job, *jobOptions, error) {
+	p.cronJobsMapLock.Lock()
+	isShutdown := p.isShutdown
+	p.cronJobsMapLock.Unlock()
+	if isShutdown {
+		return nil, nil, ErrPoolShutdown
+	}
+
+	options := newJobOptions(opts)
+	options.named = options.name != ""
+	if options.name == "" {
+		options.name = defaultJobName(newJob)
+	}
+	if contextJobDef, ok := newJob.(JobWithContext); ok {
+		newJob = &contextJob{JobWithContext: contextJobDef, pool: p}
+	}
+	if len(options.dependencies) == 0 {
+		return newJob, options, nil
+	}
+
+	p.jobsMapLock.Lock()
+	defer p.jobsMapLock.Unlock()
+	for _, id := range options.dependencies {
+		if _, ok := p.jobs[id]; !ok {
+			return nil, nil, ErrJobDependency.FormatError(id)
+		}
+	}
+	return &dependentJob{Job: newJob, pool: p, dependencies: options.dependencies}, options, nil
+}
+
+//SetContext - Sets the parent of the context of the job executions, the jobs implementing JobWithContext are executed
+//             with it
+func (p *Pool) SetContext(ctx context.Context) {
+	p.ctxLock.Lock()
+	defer p.ctxLock.Unlock()
+	p.ctx, p.cancel = context.WithCancel(ctx)
+}
+
+//getContext - returns the context of the job executions
+func (p *Pool) getContext() context.Context {
+	p.ctxLock.RLock()
+	defer p.ctxLock.RUnlock()
+	return p.ctx
+}
+
+//Shutdown - Stops all continuous jobs, waiting for their running executions until the context is done, then cancels
+//           the context of the job executions. No job is registered or restarted once the pool is shut down
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.cronJobsMapLock.Lock()
+	if p.isShutdown {
+		p.cronJobsMapLock.Unlock()
+		return nil
+	}
+	p.isShutdown = true
+	p.poolStatus = PoolStatusStopped
+	stop := make([]JobExecution, 0)
+	for id, job := range p.cronJobs {
+		if !p.stoppedJobs[id] {
+			p.stoppedJobs[id] = true
+			stop = append(stop, job)
+		}
+	}
+	p.cronJobsMapLock.Unlock()
+
+	log.Debugf("Stopping %d running jobs", len(stop))
+	stopped := make(chan bool)
+	go func() {
+		wg := &sync.WaitGroup{}
+		for _, job := range stop {
+			wg.Add(1)
+			go func(job JobExecution) {
+				defer wg.Done()
+				job.stop()
+			}(job)
+		}
+		wg.Wait()
+		close(stopped)
+	}()
+
+	p.ctxLock.RLock()
+	defer p.ctxLock.RUnlock()
+	defer p.cancel()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ErrShutdownTimeout
 	}
 }
 
 //RegisterSingleRunJob - Runs a single run job
-func (p *Pool) RegisterSingleRunJob(newJob Job) (string, error) {
+func (p *Pool) RegisterSingleRunJob(newJob Job, opts ...JobOption) (string, error) {
+	newJob, options, err := p.prepareJob(newJob, opts)
+	if err != nil {
+		return "", err
+	}
 	job, err := newBaseJob(newJob, p.failJobChan)
 	if err != nil {
 		return "", err
 	}
-	return p.recordJob(job), nil
+	return p.recordJob(job, options.name), nil
 }
 
 //RegisterIntervalJob - Runs a job with a specific interval between each run
-func (p *Pool) RegisterIntervalJob(newJob Job, interval time.Duration) (string, error) {
-	job, err := newIntervalJob(newJob, interval, p.failJobChan)
+func (p *Pool) RegisterIntervalJob(newJob Job, interval time.Duration, opts ...JobOption) (string, error) {
+	newJob, options, err := p.prepareJob(newJob, opts)
 	if err != nil {
 		return "", err
 	}
-	return p.recordCronJob(job), nil
+	job, err := newIntervalJob(newJob, interval, options.policy, p.failJobChan)
+	if err != nil {
+		return "", err
+	}
+	return p.recordCronJob(job, options), nil
 }
 
 //RegisterScheduledJob - Runs a job on a specific schedule
-func (p *Pool) RegisterScheduledJob(newJob Job, schedule string) (string, error) {
-	job, err := newScheduledJob(newJob, schedule, p.failJobChan)
+func (p *Pool) RegisterScheduledJob(newJob Job, schedule string, opts ...JobOption) (string, error) {
+	newJob, options, err := p.prepareJob(newJob, opts)
 	if err != nil {
 		return "", err
 	}
-	return p.recordCronJob(job), nil
+	if err := p.validateCatchUp(options); err != nil {
+		return "", err
+	}
+	job, err := newScheduledJob(newJob, schedule, options, p.scheduleState, p.failJobChan)
+	if err != nil {
+		return "", err
+	}
+	return p.recordCronJob(job, options), nil
+}
+
+//validateCatchUp - the last execution times of the scheduled jobs that catch up are kept by job name, the job needs a
+//                  name of its own set with WithJobName
+func (p *Pool) validateCatchUp(options *jobOptions) error {
+	if options.catchUp == CatchUpNone {
+		return nil
+	}
+	if !options.named {
+		err := fmt.Errorf("the %v catch-up policy requires a job name set with WithJobName", options.catchUp)
+		return errors.Wrap(ErrRegisteringJob, err.Error()).FormatError(JobTypeScheduled)
+	}
+
+	p.cronJobsMapLock.Lock()
+	defer p.cronJobsMapLock.Unlock()
+	for _, other := range p.jobOptions {
+		if other.catchUp != CatchUpNone && other.name == options.name {
+			err := fmt.Errorf("the name %v is used by another job that catches up", options.name)
+			return errors.Wrap(ErrRegisteringJob, err.Error()).FormatError(JobTypeScheduled)
+		}
+	}
+	return nil
+}
+
+//SetScheduleStateFile - Persists the last execution times of the scheduled jobs to the file, so that the executions
+//                       missed while the agent was stopped are caught up per the catch-up policy of the jobs
+func (p *Pool) SetScheduleStateFile(stateFile string) error {
+	return p.scheduleState.open(stateFile)
 }
 
 //RegisterRetryJob - Runs a job with a limited number of retries
-func (p *Pool) RegisterRetryJob(newJob Job, retries int) (string, error) {
+func (p *Pool) RegisterRetryJob(newJob Job, retries int, opts ...JobOption) (string, error) {
+	newJob, options, err := p.prepareJob(newJob, opts)
+	if err != nil {
+		return "", err
+	}
 	job, err := newRetryJob(newJob, retries, p.failJobChan)
 	if err != nil {
 		return "", err
 	}
-	return p.recordJob(job), nil
+	return p.recordJob(job, options.name), nil
 }
 
 //UnregisterJob - Removes the specified job
@@ -125,6 +307,196 @@ func (p *Pool) UnregisterJob(jobID string) {
 	p.removeJob(jobID)
 }
 
+//UpdateJobInterval - Changes the interval of an interval job, the next execution is an interval after the change
+func (p *Pool) UpdateJobInterval(jobID string, interval time.Duration) error {
+	p.jobsMapLock.Lock()
+	job, ok := p.jobs[jobID].(*intervalJob)
+	p.jobsMapLock.Unlock()
+	if !ok || interval <= 0 {
+		return ErrUpdatingJob.FormatError(JobTypeInterval, jobID)
+	}
+	job.setInterval(interval)
+	return nil
+}
+
+//UpdateJobSchedule - Changes the schedule of a scheduled job
+func (p *Pool) UpdateJobSchedule(jobID string, schedule string) error {
+	p.jobsMapLock.Lock()
+	job, ok := p.jobs[jobID].(*scheduleJob)
+	p.jobsMapLock.Unlock()
+	if !ok {
+		return ErrUpdatingJob.FormatError(JobTypeScheduled, jobID)
+	}
+	if err := job.setSchedule(schedule); err != nil {
+		return errors.Wrap(ErrUpdatingJob, err.Error()).FormatError(JobTypeScheduled, jobID)
+	}
+	return nil
+}
+
+//triggerableJob - the continuous jobs that can be executed now
+type triggerableJob interface {
+	trigger()
+}
+
+//TriggerJob - Executes the continuous job now, the job must be running
+func (p *Pool) TriggerJob(jobID string) error {
+	p.cronJobsMapLock.Lock()
+	defer p.cronJobsMapLock.Unlock()
+	job, ok := p.cronJobs[jobID]
+	if !ok {
+		return p.notFoundOrNotContinuous(jobID, "triggered")
+	}
+	if p.stoppedJobs[jobID] {
+		return ErrJobControl.FormatError(p.getJobName(jobID), jobID, "triggered while stopped")
+	}
+	job.(triggerableJob).trigger()
+	return nil
+}
+
+//PauseJob - Stops the continuous job, the job is not restarted until it is resumed
+func (p *Pool) PauseJob(jobID string) error {
+	p.cronJobsMapLock.Lock()
+	job, ok := p.cronJobs[jobID]
+	if !ok {
+		defer p.cronJobsMapLock.Unlock()
+		return p.notFoundOrNotContinuous(jobID, "paused")
+	}
+	running := !p.stoppedJobs[jobID]
+	p.pausedJobs[jobID] = true
+	p.stoppedJobs[jobID] = true
+	p.cronJobsMapLock.Unlock()
+
+	if running {
+		log.Debugf("Pausing job with id %v", jobID)
+		job.stop()
+	}
+	return nil
+}
+
+//ResumeJob - Restarts the paused job, unless its group is stopped, then it is restarted with its group
+func (p *Pool) ResumeJob(jobID string) error {
+	p.cronJobsMapLock.Lock()
+	defer p.cronJobsMapLock.Unlock()
+	options, ok := p.jobOptions[jobID]
+	if !ok {
+		return p.notFoundOrNotContinuous(jobID, "resumed")
+	}
+	if !p.pausedJobs[jobID] {
+		return nil
+	}
+	log.Debugf("Resuming job with id %v", jobID)
+	delete(p.pausedJobs, jobID)
+	if !p.stoppedGroups[options.group] {
+		p.startJob(jobID)
+	}
+	return nil
+}
+
+//notFoundOrNotContinuous - returns the error for the control of a job that is not a continuous job
+func (p *Pool) notFoundOrNotContinuous(jobID, action string) error {
+	p.jobsMapLock.Lock()
+	defer p.jobsMapLock.Unlock()
+	if _, ok := p.jobs[jobID]; !ok {
+		return ErrJobNotFound.FormatError(jobID)
+	}
+	return ErrJobControl.FormatError(p.jobNames[jobID], jobID, action)
+}
+
+func (p *Pool) getJobName(jobID string) string {
+	p.jobsMapLock.Lock()
+	defer p.jobsMapLock.Unlock()
+	return p.jobNames[jobID]
+}
+
+//GetJobInfo - Returns the definition and the execution metrics of the job
+func (p *Pool) GetJobInfo(jobID string) (JobInfo, error) {
+	p.cronJobsMapLock.Lock()
+	defer p.cronJobsMapLock.Unlock()
+	p.jobsMapLock.Lock()
+	defer p.jobsMapLock.Unlock()
+
+	job, ok := p.jobs[jobID]
+	if !ok {
+		return JobInfo{}, ErrJobNotFound.FormatError(jobID)
+	}
+	info := JobInfo{
+		ID:     jobID,
+		Name:   p.jobNames[jobID],
+		Status: job.GetStatus().String(),
+		Paused: p.pausedJobs[jobID],
+	}
+	if info.Paused {
+		info.Status = JobStatusStopped.String()
+	}
+
+	switch j := job.(type) {
+	case *intervalJob:
+		info.Type = JobTypeInterval
+		info.Interval = j.getInterval()
+	case *scheduleJob:
+		info.Type = JobTypeScheduled
+		info.Schedule = j.getSchedule()
+	case *retryJob:
+		info.Type = JobTypeRetry
+	default:
+		info.Type = JobTypeSingleRun
+	}
+	if options, ok := p.jobOptions[jobID]; ok {
+		info.Group = options.group
+		info.FailurePolicy = options.policy.String()
+	}
+	if b, ok := job.GetJob().(*baseJob); ok {
+		b.stats.fillInfo(&info)
+	}
+	return info, nil
+}
+
+//GetJobsInfo - Returns the definition and the execution metrics of all jobs, sorted by name
+func (p *Pool) GetJobsInfo() []JobInfo {
+	p.jobsMapLock.Lock()
+	ids := make([]string, 0, len(p.jobs))
+	for id := range p.jobs {
+		ids = append(ids, id)
+	}
+	p.jobsMapLock.Unlock()
+
+	infos := make([]JobInfo, 0, len(ids))
+	for _, id := range ids {
+		if info, err := p.GetJobInfo(id); err == nil {
+			infos = append(infos, info)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Name == infos[j].Name {
+			return infos[i].ID < infos[j].ID
+		}
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+//FindJobID - Returns the id of the job with the id or name, the name must match a single job
+func (p *Pool) FindJobID(idOrName string) (string, error) {
+	p.jobsMapLock.Lock()
+	defer p.jobsMapLock.Unlock()
+	if _, ok := p.jobs[idOrName]; ok {
+		return idOrName, nil
+	}
+	found := ""
+	for id, name := range p.jobNames {
+		if name == idOrName {
+			if found != "" {
+				return "", ErrJobNameNotUnique.FormatError(idOrName)
+			}
+			found = id
+		}
+	}
+	if found == "" {
+		return "", ErrJobNotFound.FormatError(idOrName)
+	}
+	return found, nil
+}
+
 //GetJob - Returns the Job based on the id
 func (p *Pool) GetJob(id string) JobExecution {
 	return p.jobs[id].GetJob()
@@ -145,64 +517,214 @@ func (p *Pool) GetJobStatus(id string) string {
 	return p.jobs[id].GetStatus().String()
 }
 
-//GetStatus - returns the status of the pool of jobs
+//GetStatus - returns the status of the pool of jobs, stopped while a group of jobs is stopped
 func (p *Pool) GetStatus() string {
+	p.cronJobsMapLock.Lock()
+	defer p.cronJobsMapLock.Unlock()
 	return p.poolStatus.String()
 }
 
-//startAll - starts all jobs defined in the cronJobs map, used by watchJobs
-//           other jobs are single run and never restarted
-func (p *Pool) startAll() {
-	// Check that all are ready before starting
-	log.Debug("Checking for all cron jobs to be ready")
-	for _, job := range p.cronJobs {
-		if !job.Ready() {
-			return
+//dependenciesRunning - checks that the jobs are running, or finished for the single run jobs
+func (p *Pool) dependenciesRunning(jobIDs []string) bool {
+	p.jobsMapLock.Lock()
+	defer p.jobsMapLock.Unlock()
+	for _, id := range jobIDs {
+		job, ok := p.jobs[id]
+		if !ok {
+			return false
+		}
+		if status := job.GetStatus(); status != JobStatusRunning && status != JobStatusFinished {
+			return false
+		}
+	}
+	return true
+}
+
+//markStopped - marks the job, and the jobs that depend on it, as stopped by the pool, returns the jobs to stop.
+//              The lock is held by the caller
+func (p *Pool) markStopped(jobID string) []JobExecution {
+	job, ok := p.cronJobs[jobID]
+	if !ok || p.stoppedJobs[jobID] {
+		return nil
+	}
+	p.stoppedJobs[jobID] = true
+	stop := []JobExecution{job}
+	for id, options := range p.jobOptions {
+		if contains(options.dependencies, jobID) {
+			stop = append(stop, p.markStopped(id)...)
+		}
+	}
+	return stop
+}
+
+//startJob - starts the job stopped by the pool, then the jobs that depend on it. The lock is held by the caller
+func (p *Pool) startJob(jobID string) {
+	job, ok := p.cronJobs[jobID]
+	if !ok || !p.stoppedJobs[jobID] || p.pausedJobs[jobID] || p.isShutdown {
+		return
+	}
+	delete(p.stoppedJobs, jobID)
+	go job.start()
+	for id, options := range p.jobOptions {
+		if contains(options.dependencies, jobID) && !p.stoppedGroups[options.group] {
+			p.startJob(id)
 		}
 	}
-	log.Debug("Starting all cron jobs")
-	p.poolStatus = PoolStatusRunning
-	for _, job := range p.cronJobs {
-		go job.start()
+}
+
+//recordFailure - counts the consecutive failures of the job, the count is reset when the job ran longer than the
+//                maximum backoff since its last failure. The lock is held by the caller
+func (p *Pool) recordFailure(jobID string) int {
+	failures, ok := p.failures[jobID]
+	if !ok || time.Since(failures.lastFailure) > p.maxRestartBackoff {
+		failures = &jobFailures{}
+		p.failures[jobID] = failures
 	}
+	failures.count++
+	failures.lastFailure = time.Now()
+	return failures.count
 }
 
-//stopAll - stops all jobs defined in the cronJobs map, used by watchJobs
-//           other jobs are single run and should not need stopped
-func (p *Pool) stopAll() {
-	log.Debug("Stopping all cron jobs")
-	p.poolStatus = PoolStatusStopped
-	for _, job := range p.cronJobs {
-		job.stop()
+//getRestartBackoff - the wait before restarting the job, doubled on each consecutive failure
+func (p *Pool) getRestartBackoff(failures int) time.Duration {
+	backoff := p.minRestartBackoff
+	for i := 1; i < failures && backoff < p.maxRestartBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.maxRestartBackoff {
+		backoff = p.maxRestartBackoff
 	}
+	return backoff
 }
 
-//catchFails - catches all writes to the failJobChan and only sends one stop signal
+//catchFails - catches all writes to the failJobChan, each failure is handled per the failure policy of the job
 func (p *Pool) catchFails() {
 	for {
-		// continue rading all failed jobs, only stop the first time
 		failedJob := <-p.failJobChan
-		if p.poolStatus == PoolStatusRunning {
-			p.failedJob = failedJob // this is the job for the current fail loop
-			p.stopJobsChan <- true
+		go p.handleFailure(failedJob)
+	}
+}
+
+//handleFailure - applies the failure policy of the failed job, failures of jobs already stopped are ignored
+func (p *Pool) handleFailure(jobID string) {
+	p.cronJobsMapLock.Lock()
+	options, ok := p.jobOptions[jobID]
+	if !ok || p.stoppedJobs[jobID] {
+		// not a continuous job, or already stopped
+		p.cronJobsMapLock.Unlock()
+		return
+	}
+
+	var stop []JobExecution
+	var restart func()
+	switch options.policy {
+	case FailurePolicyIsolate:
+		failures := p.recordFailure(jobID)
+		log.Debugf("Job with id %v failed %d times, the job keeps running", jobID, failures)
+	case FailurePolicyRestart:
+		backoff := p.getRestartBackoff(p.recordFailure(jobID))
+		log.Debugf("Job with id %v failed, restarting the job in %v", jobID, backoff)
+		stop = p.markStopped(jobID)
+		restart = func() { p.restartJob(jobID, backoff) }
+	default:
+		group := options.group
+		if wait := p.retryInterval - time.Since(p.groupStarts[group]); wait > 0 {
+			// the group was restarted recently, the failure is handled after the retry interval
+			if !p.deferredGroups[group] {
+				p.deferredGroups[group] = true
+				go p.deferFailure(jobID, wait)
+			}
+			p.cronJobsMapLock.Unlock()
+			return
+		}
+		p.recordFailure(jobID)
+		log.Debugf("Job with id %v failed, stop all jobs of group %v", jobID, group)
+		p.failedJob = jobID
+		p.poolStatus = PoolStatusStopped
+		p.stoppedGroups[group] = true
+		for id, o := range p.jobOptions {
+			if o.group == group {
+				stop = append(stop, p.markStopped(id)...)
+			}
 		}
+		restart = func() { p.restartGroup(group) }
+	}
+	p.cronJobsMapLock.Unlock()
+
+	for _, job := range stop {
+		job.stop()
 	}
+	// restarted once all the jobs have stopped
+	if restart != nil {
+		restart()
+	}
+}
+
+//deferFailure - handles the failure of the job after the wait
+func (p *Pool) deferFailure(jobID string, wait time.Duration) {
+	time.Sleep(wait)
+	p.cronJobsMapLock.Lock()
+	if options, ok := p.jobOptions[jobID]; ok {
+		delete(p.deferredGroups, options.group)
+	}
+	p.cronJobsMapLock.Unlock()
+	p.handleFailure(jobID)
 }
 
-//watchJobs - the main loop of a pool of jobs, constantly checks for status of jobs and acts accordingly
-func (p *Pool) watchJobs() {
-	p.poolStatus = PoolStatusRunning
+//restartJob - restarts the job after the backoff once it is ready, the jobs of a stopped group are restarted with
+//             their group
+func (p *Pool) restartJob(jobID string, backoff time.Duration) {
+	time.Sleep(backoff)
 	for {
-		if p.poolStatus == PoolStatusRunning {
-			// The pool is running, wait for any signal that a job went down
-			<-p.stopJobsChan
-			log.Debugf("Job with id %v failed, stop all jobs", p.failedJob)
-			p.stopAll()
-		} else {
-			log.Debug("Pool not running, start all jobs")
-			// attempt to restart all jobs
-			p.startAll()
-			time.Sleep(p.retryInterval)
+		p.cronJobsMapLock.Lock()
+		job, ok := p.cronJobs[jobID]
+		if !ok || !p.stoppedJobs[jobID] || p.stoppedGroups[p.jobOptions[jobID].group] {
+			p.cronJobsMapLock.Unlock()
+			return
+		}
+		if job.Ready() {
+			log.Debugf("Restarting job with id %v", jobID)
+			p.startJob(jobID)
+			p.cronJobsMapLock.Unlock()
+			return
+		}
+		p.cronJobsMapLock.Unlock()
+		time.Sleep(p.retryInterval)
+	}
+}
+
+//restartGroup - restarts all jobs of the group once they are all ready, checked on the retry interval
+func (p *Pool) restartGroup(group string) {
+	for !p.startGroup(group) {
+		time.Sleep(p.retryInterval)
+	}
+}
+
+//startGroup - starts all jobs of the group when they are all ready, returns true when the group was started
+func (p *Pool) startGroup(group string) bool {
+	p.cronJobsMapLock.Lock()
+	defer p.cronJobsMapLock.Unlock()
+
+	// Check that all are ready before starting
+	log.Debugf("Checking for all cron jobs of group %v to be ready", group)
+	for id, options := range p.jobOptions {
+		// the jobs with dependencies wait for them once started
+		if options.group == group && len(options.dependencies) == 0 && !p.pausedJobs[id] && !p.cronJobs[id].Ready() {
+			return false
+		}
+	}
+
+	log.Debugf("Starting all cron jobs of group %v", group)
+	delete(p.stoppedGroups, group)
+	p.groupStarts[group] = time.Now()
+	if len(p.stoppedGroups) == 0 {
+		p.poolStatus = PoolStatusRunning
+		p.failedJob = ""
+	}
+	for id, options := range p.jobOptions {
+		if options.group == group {
+			p.startJob(id)
 		}
 	}
+	return true
 }
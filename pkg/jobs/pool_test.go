@@ -1,9 +1,13 @@
 package jobs
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/Axway/agent-sdk/pkg/util/metrics"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -63,3 +67,334 @@ func TestPoolCoordination(t *testing.T) {
 	assert.True(t, failJob.wasFailed, "The fail job never reported as failed")
 	assert.True(t, failJob.wasRestored, "The fail job was not restored after failure")
 }
+
+type policyJobImpl struct {
+	Job
+	lock       sync.Mutex
+	executions int
+	failing    bool
+	ready      bool
+}
+
+func newPolicyJob(failing bool) *policyJobImpl {
+	return &policyJobImpl{failing: failing, ready: true}
+}
+
+func (j *policyJobImpl) Execute() error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.executions++
+	if j.failing {
+		// not ready until the test restores the job
+		j.ready = false
+		return fmt.Errorf("FAIL")
+	}
+	return nil
+}
+
+func (j *policyJobImpl) Status() error {
+	return nil
+}
+
+func (j *policyJobImpl) Ready() bool {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.ready
+}
+
+func (j *policyJobImpl) getExecutions() int {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.executions
+}
+
+func (j *policyJobImpl) restore() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.failing = false
+	j.ready = true
+}
+
+func (p *Pool) isJobStopped(id string) bool {
+	p.cronJobsMapLock.Lock()
+	defer p.cronJobsMapLock.Unlock()
+	return p.stoppedJobs[id]
+}
+
+func TestPoolFailurePolicies(t *testing.T) {
+	testPool := newPool()
+	testPool.retryInterval = 50 * time.Millisecond
+	testPool.minRestartBackoff = 100 * time.Millisecond
+
+	// the isolated job keeps running on its interval
+	isolatedJob := newPolicyJob(true)
+	isolatedID, _ := testPool.RegisterIntervalJob(&alwaysReadyJob{isolatedJob}, 10*time.Millisecond, WithFailurePolicy(FailurePolicyIsolate))
+
+	// the restarted job is stopped, then restarted after the backoff
+	restartJob := newPolicyJob(true)
+	restartID, _ := testPool.RegisterIntervalJob(&alwaysReadyJob{restartJob}, 10*time.Millisecond, WithFailurePolicy(FailurePolicyRestart))
+
+	otherJob := newPolicyJob(false)
+	testPool.RegisterIntervalJob(otherJob, 10*time.Millisecond)
+
+	time.Sleep(250 * time.Millisecond)
+	assert.Equal(t, PoolStatusRunning.String(), testPool.GetStatus())
+	assert.GreaterOrEqual(t, isolatedJob.getExecutions(), 10, "The isolated job did not keep running")
+	assert.False(t, testPool.isJobStopped(isolatedID))
+	// the isolated job reports running, the jobs depending on it keep running
+	assert.Equal(t, JobStatusRunning, testPool.GetJob(isolatedID).GetStatus())
+	assert.True(t, testPool.dependenciesRunning([]string{isolatedID}))
+	assert.GreaterOrEqual(t, otherJob.getExecutions(), 10, "The other job did not keep running")
+
+	// 100ms then 200ms backoff
+	assert.GreaterOrEqual(t, restartJob.getExecutions(), 2, "The restart job was not restarted")
+	assert.LessOrEqual(t, restartJob.getExecutions(), 3, "The restart job was restarted without backoff")
+	assert.Equal(t, 200*time.Millisecond, testPool.getRestartBackoff(2))
+	testPool.maxRestartBackoff = 300 * time.Millisecond
+	assert.Equal(t, 300*time.Millisecond, testPool.getRestartBackoff(5))
+
+	testPool.UnregisterJob(restartID)
+	testPool.UnregisterJob(isolatedID)
+}
+
+// alwaysReadyJob - the job is ready while failing
+type alwaysReadyJob struct {
+	*policyJobImpl
+}
+
+func (j *alwaysReadyJob) Ready() bool {
+	return true
+}
+
+func TestPoolGroupsAndDependencies(t *testing.T) {
+	testPool := newPool()
+	testPool.retryInterval = 20 * time.Millisecond
+
+	failJob := newPolicyJob(true)
+	failID, _ := testPool.RegisterIntervalJob(failJob, 10*time.Millisecond, WithJobGroup("gateway"))
+	groupJob := newPolicyJob(false)
+	groupID, _ := testPool.RegisterIntervalJob(groupJob, 10*time.Millisecond, WithJobGroup("gateway"))
+
+	// depends on a job of the gateway group
+	dependentJob := newPolicyJob(false)
+	dependentID, err := testPool.RegisterIntervalJob(dependentJob, 10*time.Millisecond, WithJobGroup("central"), WithJobDependencies(groupID))
+	assert.Nil(t, err)
+	centralJob := newPolicyJob(false)
+	testPool.RegisterIntervalJob(centralJob, 10*time.Millisecond, WithJobGroup("central"))
+
+	_, err = testPool.RegisterIntervalJob(newPolicyJob(false), time.Second, WithJobDependencies("unknown"))
+	assert.NotNil(t, err)
+
+	// the gateway group and the dependent job are stopped, the other group keeps running
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, PoolStatusStopped.String(), testPool.GetStatus())
+	assert.True(t, testPool.isJobStopped(failID))
+	assert.True(t, testPool.isJobStopped(groupID))
+	assert.True(t, testPool.isJobStopped(dependentID))
+	groupExecutions := groupJob.getExecutions()
+	dependentExecutions := dependentJob.getExecutions()
+	centralExecutions := centralJob.getExecutions()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, groupExecutions, groupJob.getExecutions())
+	assert.Equal(t, dependentExecutions, dependentJob.getExecutions())
+	assert.Greater(t, centralJob.getExecutions(), centralExecutions)
+
+	// restarted once the failed job is ready
+	failJob.restore()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, PoolStatusRunning.String(), testPool.GetStatus())
+	assert.False(t, testPool.isJobStopped(failID))
+	assert.False(t, testPool.isJobStopped(dependentID))
+	assert.Greater(t, groupJob.getExecutions(), groupExecutions)
+	assert.Greater(t, dependentJob.getExecutions(), dependentExecutions)
+}
+
+func TestUpdateJobIntervalAndSchedule(t *testing.T) {
+	testPool := newPool()
+	job := newPolicyJob(false)
+	intervalID, _ := testPool.RegisterIntervalJob(job, time.Hour)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 1, job.getExecutions())
+
+	assert.Nil(t, testPool.UpdateJobInterval(intervalID, 10*time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+	assert.Greater(t, job.getExecutions(), 3)
+	assert.NotNil(t, testPool.UpdateJobInterval(intervalID, 0))
+	assert.NotNil(t, testPool.UpdateJobInterval("unknown", time.Second))
+	assert.NotNil(t, testPool.UpdateJobSchedule(intervalID, "@daily"))
+
+	scheduledID, _ := testPool.RegisterScheduledJob(newPolicyJob(false), "@yearly")
+	assert.NotNil(t, testPool.UpdateJobSchedule(scheduledID, "bad schedule"))
+	assert.Nil(t, testPool.UpdateJobSchedule(scheduledID, "@daily"))
+	scheduled := testPool.jobs[scheduledID].(*scheduleJob)
+	assert.Equal(t, "@daily", scheduled.schedule)
+	assert.True(t, time.Until(scheduled.getNextExecution(time.Now())) <= 24*time.Hour)
+
+	testPool.UnregisterJob(intervalID)
+	testPool.UnregisterJob(scheduledID)
+}
+
+func TestJobInfoAndControl(t *testing.T) {
+	testPool := newPool()
+	job := newPolicyJob(false)
+	intervalID, _ := testPool.RegisterIntervalJob(job, time.Hour, WithJobName("resync"), WithJobGroup("discovery"))
+	singleID, _ := testPool.RegisterSingleRunJob(newPolicyJob(false))
+	time.Sleep(20 * time.Millisecond)
+
+	info, err := testPool.GetJobInfo(intervalID)
+	assert.Nil(t, err)
+	assert.Equal(t, "resync", info.Name)
+	assert.Equal(t, JobTypeInterval, info.Type)
+	assert.Equal(t, JobStatusRunning.String(), info.Status)
+	assert.Equal(t, "discovery", info.Group)
+	assert.Equal(t, FailurePolicyEscalate.String(), info.FailurePolicy)
+	assert.Equal(t, time.Hour, info.Interval)
+	assert.Equal(t, 1, info.RunCount)
+	assert.NotNil(t, info.LastStart)
+	assert.NotNil(t, info.LastEnd)
+	assert.Len(t, info.Durations, len(durationBuckets)+1)
+	assert.Equal(t, 1, info.Durations[0].Count)
+	assert.Equal(t, "+Inf", info.Durations[len(durationBuckets)].LessOrEqual)
+
+	infos := testPool.GetJobsInfo()
+	assert.Len(t, infos, 2)
+	assert.Equal(t, "jobs.policyJobImpl", infos[0].Name)
+	assert.Equal(t, JobTypeSingleRun, infos[0].Type)
+	assert.Equal(t, JobStatusFinished.String(), infos[0].Status)
+
+	id, err := testPool.FindJobID("resync")
+	assert.Nil(t, err)
+	assert.Equal(t, intervalID, id)
+	_, err = testPool.FindJobID("unknown")
+	assert.NotNil(t, err)
+
+	// trigger an execution now
+	assert.Nil(t, testPool.TriggerJob(intervalID))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 2, job.getExecutions())
+	assert.NotNil(t, testPool.TriggerJob(singleID))
+	assert.NotNil(t, testPool.TriggerJob("unknown"))
+
+	// paused jobs are not triggered
+	assert.Nil(t, testPool.PauseJob(intervalID))
+	info, _ = testPool.GetJobInfo(intervalID)
+	assert.True(t, info.Paused)
+	assert.Equal(t, JobStatusStopped.String(), info.Status)
+	assert.NotNil(t, testPool.TriggerJob(intervalID))
+
+	// resumed, the job executes on start
+	assert.Nil(t, testPool.ResumeJob(intervalID))
+	time.Sleep(20 * time.Millisecond)
+	info, _ = testPool.GetJobInfo(intervalID)
+	assert.False(t, info.Paused)
+	assert.Equal(t, JobStatusRunning.String(), info.Status)
+	assert.Equal(t, 3, info.RunCount)
+
+	// failures are recorded
+	job.lock.Lock()
+	job.failing = true
+	job.lock.Unlock()
+	testPool.TriggerJob(intervalID)
+	time.Sleep(20 * time.Millisecond)
+	info, _ = testPool.GetJobInfo(intervalID)
+	assert.Equal(t, 1, info.FailureCount)
+	assert.Equal(t, "FAIL", info.LastError)
+
+	// the metrics of the jobs
+	families := testPool.collectMetrics()
+	assert.Len(t, families, 4)
+	resyncLabels := metrics.Labels{"job": "resync", "type": JobTypeInterval}
+	for _, family := range families {
+		for _, sample := range family.Samples {
+			if sample.Labels["job"] != "resync" {
+				continue
+			}
+			assert.Equal(t, resyncLabels, sample.Labels)
+			switch family.Name {
+			case "agent_job_runs":
+				assert.Equal(t, float64(4), sample.Value)
+			case "agent_job_failures":
+				assert.Equal(t, float64(1), sample.Value)
+			case "agent_job_paused":
+				assert.Equal(t, float64(0), sample.Value)
+			case "agent_job_duration_seconds":
+				assert.Equal(t, uint64(4), sample.Count)
+				assert.Len(t, sample.Buckets, len(durationBuckets))
+				assert.Equal(t, uint64(4), sample.Buckets[0].Count)
+			}
+		}
+	}
+
+	testPool.UnregisterJob(intervalID)
+}
+
+type contextJobImpl struct {
+	Job
+	runTime  time.Duration
+	lock     sync.Mutex
+	canceled bool
+}
+
+func (j *contextJobImpl) ExecuteWithContext(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		j.lock.Lock()
+		j.canceled = true
+		j.lock.Unlock()
+	case <-time.After(j.runTime):
+	}
+	return nil
+}
+
+func (j *contextJobImpl) Status() error {
+	return nil
+}
+
+func (j *contextJobImpl) Ready() bool {
+	return true
+}
+
+func (j *contextJobImpl) isCanceled() bool {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.canceled
+}
+
+func TestPoolShutdown(t *testing.T) {
+	// the running executions end before the deadline
+	testPool := newPool()
+	job := &contextJobImpl{runTime: 50 * time.Millisecond}
+	jobID, err := testPool.RegisterIntervalJob(job, time.Hour)
+	assert.Nil(t, err)
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(t, testPool.Shutdown(ctx))
+	assert.Equal(t, JobStatusStopped, testPool.GetJob(jobID).GetStatus())
+	assert.False(t, job.isCanceled())
+	assert.Nil(t, testPool.Shutdown(ctx))
+
+	// no job is registered or restarted once shut down
+	_, err = testPool.RegisterSingleRunJob(job)
+	assert.Equal(t, ErrPoolShutdown, err)
+	assert.Nil(t, testPool.ResumeJob(jobID))
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, JobStatusStopped, testPool.GetJob(jobID).GetStatus())
+
+	// the context of the running executions is canceled at the deadline
+	testPool = newPool()
+	parent, cancelParent := context.WithCancel(context.Background())
+	defer cancelParent()
+	testPool.SetContext(parent)
+	job = &contextJobImpl{runTime: time.Hour}
+	_, err = testPool.RegisterScheduledJob(job, "* * * * * * *")
+	assert.Nil(t, err)
+	time.Sleep(1100 * time.Millisecond)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, ErrShutdownTimeout, testPool.Shutdown(ctx))
+	time.Sleep(10 * time.Millisecond)
+	assert.True(t, job.isCanceled())
+}
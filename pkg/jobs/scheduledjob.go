@@ -1,6 +1,9 @@
 package jobs
 
 import (
+	"math"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/Axway/agent-sdk/pkg/util/log"
@@ -10,9 +13,20 @@ import (
 )
 
 type scheduleJobProps struct {
-	schedule string
-	cronExp  *cronexpr.Expression
-	stopChan chan bool
+	stateKey     string // the name the last execution time is kept by, empty when the job does not catch up
+	schedule     string
+	cronExp      *cronexpr.Expression
+	location     *time.Location
+	jitter       time.Duration
+	catchUp      CatchUpPolicy
+	overlap      OverlapPolicy
+	state        *scheduleState
+	scheduleLock sync.RWMutex
+	stopChan     chan bool
+	updateChan   chan bool
+	triggerChan  chan bool
+	doneChan     chan bool
+	executions   sync.WaitGroup
 }
 
 type scheduleJob struct {
@@ -21,11 +35,15 @@ type scheduleJob struct {
 }
 
 //newScheduledJob - creates a job that is ran at a specific time (@hourly,@daily,@weekly,min hour dow dom)
-func newScheduledJob(newJob Job, schedule string, failJobChan chan string) (JobExecution, error) {
+func newScheduledJob(newJob Job, schedule string, options *jobOptions, state *scheduleState, failJobChan chan string) (JobExecution, error) {
 	exp, err := cronexpr.Parse(schedule)
 	if err != nil {
 		return nil, errors.Wrap(ErrRegisteringJob, err.Error()).FormatError("scheduled")
 	}
+	location, err := options.getLocation()
+	if err != nil {
+		return nil, errors.Wrap(ErrRegisteringJob, err.Error()).FormatError("scheduled")
+	}
 
 	thisJob := scheduleJob{
 		baseJob{
@@ -33,11 +51,21 @@ func newScheduledJob(newJob Job, schedule string, failJobChan chan string) (JobE
 			job:      newJob,
 			status:   JobStatusInitializing,
 			failChan: failJobChan,
+			policy:   options.policy,
 		},
 		scheduleJobProps{
-			cronExp:  exp,
-			schedule: schedule,
-			stopChan: make(chan bool),
+			stateKey:    options.getStateKey(),
+			cronExp:     exp,
+			schedule:    schedule,
+			location:    location,
+			jitter:      options.jitter,
+			catchUp:     options.catchUp,
+			overlap:     options.overlap,
+			state:       state,
+			stopChan:    make(chan bool),
+			updateChan:  make(chan bool, 1),
+			triggerChan: make(chan bool, 1),
+			doneChan:    make(chan bool, 1),
 		},
 	}
 
@@ -45,39 +73,204 @@ func newScheduledJob(newJob Job, schedule string, failJobChan chan string) (JobE
 	return &thisJob, nil
 }
 
-func (b *scheduleJob) getNextExecution() time.Duration {
-	nextTime := b.cronExp.Next(time.Now())
-	return nextTime.Sub(time.Now())
+//getNextExecution - returns the next time of the schedule after the time, in the time zone of the schedule
+func (b *scheduleJob) getNextExecution(after time.Time) time.Time {
+	b.scheduleLock.RLock()
+	defer b.scheduleLock.RUnlock()
+	return b.cronExp.Next(after.In(b.location))
+}
+
+//getWait - returns the wait until the next time of the schedule, delayed by a random jitter
+func (b *scheduleJob) getWait(next time.Time) time.Duration {
+	if next.IsZero() {
+		// the schedule has no next time
+		return time.Duration(math.MaxInt64)
+	}
+	wait := time.Until(next)
+	if b.jitter > 0 {
+		wait += time.Duration(rand.Int63n(int64(b.jitter)))
+	}
+	return wait
+}
+
+//setSchedule - changes the schedule, the next execution is the next time of the new schedule
+func (b *scheduleJob) setSchedule(schedule string) error {
+	exp, err := cronexpr.Parse(schedule)
+	if err != nil {
+		return err
+	}
+	b.scheduleLock.Lock()
+	b.schedule = schedule
+	b.cronExp = exp
+	b.scheduleLock.Unlock()
+
+	// Non-blocking channel write, the execution loop resets its timer
+	select {
+	case b.updateChan <- true:
+	default:
+	}
+	return nil
+}
+
+func (b *scheduleJob) handleExecution() {
+	if b.overlap == OverlapAllow {
+		// the executions run alongside each other, the error is not kept on the job
+		if err := b.executeSharedCronJob(); err != nil {
+			log.Error(errors.Wrap(ErrExecutingJob, err.Error()).FormatError(JobTypeScheduled, b.id))
+		}
+		return
+	}
+
+	b.executeCronJob()
+	if b.err != nil {
+		b.err = errors.Wrap(ErrExecutingJob, b.err.Error()).FormatError(JobTypeScheduled, b.id)
+	}
+}
+
+//dispatch - executes the job in its own routine, an execution on the schedule is recorded as the last run
+func (b *scheduleJob) dispatch(scheduled time.Time) {
+	if !scheduled.IsZero() && b.stateKey != "" {
+		b.state.setLastRun(b.stateKey, scheduled)
+	}
+	b.executions.Add(1)
+	go func() {
+		defer b.executions.Done()
+		b.handleExecution()
+		if b.overlap != OverlapAllow {
+			// only one execution at a time, the buffered channel write does not block
+			b.doneChan <- true
+		}
+	}()
+}
+
+//runCatchUp - executes the job for the executions missed since its last run, per its catch-up policy. Returns false
+//             when the job was stopped meanwhile
+func (b *scheduleJob) runCatchUp() bool {
+	if b.stateKey == "" {
+		return true
+	}
+	lastRun := b.state.getLastRun(b.stateKey)
+	if lastRun.IsZero() {
+		return true
+	}
+
+	// the most recent missed executions
+	now := time.Now()
+	missed := make([]time.Time, 0)
+	for next := b.getNextExecution(lastRun); !next.IsZero() && !next.After(now); next = b.getNextExecution(next) {
+		missed = append(missed, next)
+		if len(missed) > maxCatchUpExecutions {
+			missed = missed[1:]
+		}
+	}
+	if len(missed) == 0 {
+		return true
+	}
+	if b.catchUp == CatchUpOnce {
+		missed = missed[len(missed)-1:]
+	}
+
+	log.Debugf("Catching up %d missed executions of %v job %v", len(missed), JobTypeScheduled, b.id)
+	for _, scheduled := range missed {
+		// Non-blocking channel read, if stopped then exit
+		select {
+		case <-b.stopChan:
+			return false
+		default:
+		}
+		b.state.setLastRun(b.stateKey, scheduled)
+		b.handleExecution()
+	}
+	return true
+}
+
+//getSchedule - returns the schedule of the job
+func (b *scheduleJob) getSchedule() string {
+	b.scheduleLock.RLock()
+	defer b.scheduleLock.RUnlock()
+	return b.schedule
+}
+
+//trigger - executes the job now, the schedule is not changed
+func (b *scheduleJob) trigger() {
+	// Non-blocking channel write, a trigger is already pending otherwise
+	select {
+	case b.triggerChan <- true:
+	default:
+	}
 }
 
 //start - calls the Execute function from the Job definition
 func (b *scheduleJob) start() {
 	log.Debugf("Starting %v job %v", JobTypeScheduled, b.id)
-	b.waitForReady()
+	if !b.waitForReadyOrStop(b.stopChan) {
+		return
+	}
+
+	// Non-blocking channel read, drops the end of an execution of the previous loop
+	select {
+	case <-b.doneChan:
+	default:
+	}
 
-	ticker := time.NewTicker(b.getNextExecution())
-	defer ticker.Stop()
 	b.SetStatus(JobStatusRunning)
+	if !b.runCatchUp() {
+		return
+	}
+
+	next := b.getNextExecution(time.Now())
+	timer := time.NewTimer(b.getWait(next))
+	defer func() { timer.Stop() }()
+
+	running := false              // an execution is running, unless they are allowed to overlap
+	queued := false               // an execution waits for the running one to end
+	var queuedScheduled time.Time // the scheduled time of the queued execution, zero when triggered
+	run := func(scheduled time.Time) {
+		switch {
+		case !running || b.overlap == OverlapAllow:
+			running = b.overlap != OverlapAllow
+			b.dispatch(scheduled)
+		case b.overlap == OverlapQueue || scheduled.IsZero():
+			// a trigger is queued whatever the overlap policy
+			if !queued || !scheduled.IsZero() {
+				queuedScheduled = scheduled
+			}
+			queued = true
+		default:
+			log.Debugf("Skipping the execution of %v job %v at %v, the previous execution is running", JobTypeScheduled, b.id, scheduled)
+		}
+	}
+
 	for {
 		// Non-blocking channel read, if stopped then exit
 		select {
 		case <-b.stopChan:
-			b.SetStatus(JobStatusStopped)
 			return
-		case <-ticker.C:
-			b.executeCronJob()
-			if b.err != nil {
-				b.err = errors.Wrap(ErrExecutingJob, b.err.Error()).FormatError(JobTypeScheduled, b.id)
-				b.SetStatus(JobStatusStopped)
+		case <-b.updateChan:
+			timer.Stop()
+			next = b.getNextExecution(time.Now())
+			timer = time.NewTimer(b.getWait(next))
+		case <-b.triggerChan:
+			run(time.Time{})
+		case <-b.doneChan:
+			running = false
+			if queued {
+				queued = false
+				run(queuedScheduled)
 			}
-			ticker.Stop()
-			ticker = time.NewTicker(b.getNextExecution())
+		case <-timer.C:
+			run(next)
+			next = b.getNextExecution(time.Now())
+			timer = time.NewTimer(b.getWait(next))
 		}
 	}
 }
 
-//stop - write to the stop channel to stop the execution loop
+//stop - write to the stop channel to stop the execution loop, then wait for the running executions to end
 func (b *scheduleJob) stop() {
 	log.Debugf("Stopping %v job %v", JobTypeScheduled, b.id)
 	b.stopChan <- true
+	b.executions.Wait()
+	// set once the execution loop exited, a restarted loop sets its own status
+	b.SetStatus(JobStatusStopped)
 }
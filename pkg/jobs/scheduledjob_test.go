@@ -1,6 +1,8 @@
 package jobs
 
 import (
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -57,3 +59,163 @@ func TestScheduledJob(t *testing.T) {
 	assert.Equal(t, jobStatusToString[JobStatusStopped], status)
 	assert.LessOrEqual(t, 3, job.executions)
 }
+
+type overlapJobImpl struct {
+	Job
+	lock       sync.Mutex
+	runTime    time.Duration
+	executions int
+	running    int
+	concurrent int
+}
+
+func (j *overlapJobImpl) Execute() error {
+	j.lock.Lock()
+	j.executions++
+	j.running++
+	if j.running > j.concurrent {
+		j.concurrent = j.running
+	}
+	j.lock.Unlock()
+
+	time.Sleep(j.runTime)
+
+	j.lock.Lock()
+	j.running--
+	j.lock.Unlock()
+	return nil
+}
+
+func (j *overlapJobImpl) Status() error {
+	return nil
+}
+
+func (j *overlapJobImpl) Ready() bool {
+	return true
+}
+
+func (j *overlapJobImpl) getExecutions() (int, int) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.executions, j.concurrent
+}
+
+func TestScheduledJobTimeZone(t *testing.T) {
+	testPool := newPool()
+	job := &overlapJobImpl{}
+
+	_, err := testPool.RegisterScheduledJob(job, "@daily", WithTimeZone("Mars/Olympus_Mons"))
+	assert.NotNil(t, err, "expected an error with an unknown time zone")
+
+	jobID, err := testPool.RegisterScheduledJob(job, "@daily", WithTimeZone("America/New_York"), WithJitter(time.Minute))
+	assert.Nil(t, err)
+	defer testPool.UnregisterJob(jobID)
+
+	location, _ := time.LoadLocation("America/New_York")
+	scheduled := testPool.jobs[jobID].(*scheduleJob)
+	next := scheduled.getNextExecution(time.Now())
+	assert.Equal(t, location, next.Location())
+	assert.Equal(t, 0, next.Hour())
+
+	// the jitter delays the execution up to a minute
+	wait := scheduled.getWait(next)
+	assert.True(t, wait >= time.Until(next)-time.Second)
+	assert.True(t, wait <= time.Until(next)+time.Minute)
+}
+
+func TestScheduledJobCatchUp(t *testing.T) {
+	newYear := "0 0 0 1 1 * *"
+	now := time.Now().UTC()
+	lastRun := time.Date(now.Year()-3, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		policy     CatchUpPolicy
+		executions int
+	}{
+		"none": {policy: CatchUpNone, executions: 0},
+		"once": {policy: CatchUpOnce, executions: 1},
+		"all":  {policy: CatchUpAll, executions: 3},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			stateFile := filepath.Join(t.TempDir(), "jobs", "schedules.log")
+			testPool := newPool()
+			assert.Nil(t, testPool.SetScheduleStateFile(stateFile))
+			testPool.scheduleState.setLastRun("new-year-"+name, lastRun)
+
+			job := &overlapJobImpl{}
+			jobID, err := testPool.RegisterScheduledJob(job, newYear, WithJobName("new-year-"+name), WithTimeZone("UTC"), WithCatchUp(tc.policy))
+			assert.Nil(t, err)
+			time.Sleep(50 * time.Millisecond)
+			executions, _ := job.getExecutions()
+			assert.Equal(t, tc.executions, executions)
+			testPool.UnregisterJob(jobID)
+
+			// the last run is persisted, a restarted agent has nothing to catch up
+			expected := lastRun
+			if tc.executions > 0 {
+				expected = time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+			}
+			state := newScheduleState()
+			assert.Nil(t, state.open(stateFile))
+			assert.True(t, expected.Equal(state.getLastRun("new-year-"+name)))
+		})
+	}
+}
+
+func TestScheduledJobCatchUpName(t *testing.T) {
+	testPool := newPool()
+	now := time.Now().UTC()
+	testPool.scheduleState.setLastRun(defaultJobName(&overlapJobImpl{}), time.Date(now.Year()-1, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	// the catch-up requires a name set with WithJobName
+	_, err := testPool.RegisterScheduledJob(&overlapJobImpl{}, "@yearly", WithCatchUp(CatchUpOnce))
+	assert.NotNil(t, err)
+
+	jobID, err := testPool.RegisterScheduledJob(&overlapJobImpl{}, "@yearly", WithJobName("report"), WithCatchUp(CatchUpOnce))
+	assert.Nil(t, err)
+	defer testPool.UnregisterJob(jobID)
+
+	// the name is used by another job that catches up
+	_, err = testPool.RegisterScheduledJob(&overlapJobImpl{}, "@yearly", WithJobName("report"), WithCatchUp(CatchUpAll))
+	assert.NotNil(t, err)
+
+	// a job without catch-up neither reads nor records the last run of the job type
+	job := &overlapJobImpl{}
+	otherID, err := testPool.RegisterScheduledJob(job, "@yearly", WithJobName("report"))
+	assert.Nil(t, err)
+	defer testPool.UnregisterJob(otherID)
+	time.Sleep(50 * time.Millisecond)
+	executions, _ := job.getExecutions()
+	assert.Equal(t, 0, executions)
+}
+
+func TestScheduledJobOverlap(t *testing.T) {
+	testCases := map[string]struct {
+		policy     OverlapPolicy
+		executions int
+		concurrent int
+	}{
+		"skip":  {policy: OverlapSkip, executions: 1, concurrent: 1},
+		"queue": {policy: OverlapQueue, executions: 2, concurrent: 1},
+		"allow": {policy: OverlapAllow, executions: 2, concurrent: 2},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			testPool := newPool()
+			job := &overlapJobImpl{runTime: 1200 * time.Millisecond}
+			jobID, err := testPool.RegisterScheduledJob(job, "* * * * * * *", WithOverlap(tc.policy))
+			assert.Nil(t, err)
+
+			// wait for the first execution, the next one is a second later, while the first one runs
+			for executions, _ := job.getExecutions(); executions == 0; executions, _ = job.getExecutions() {
+				time.Sleep(time.Millisecond)
+			}
+			time.Sleep(1600 * time.Millisecond)
+			executions, concurrent := job.getExecutions()
+			assert.Equal(t, tc.executions, executions)
+			assert.Equal(t, tc.concurrent, concurrent)
+			testPool.UnregisterJob(jobID)
+		})
+	}
+}
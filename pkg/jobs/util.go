@@ -6,3 +6,12 @@ func newUUID() string {
 	id, _ := uuid.NewRandom()
 	return id.String()
 }
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
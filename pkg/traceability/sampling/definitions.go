@@ -7,8 +7,45 @@ const (
 	globalCounter = "global"
 )
 
+//SampleDecisionKey - the key used in the metadata for the sampling decision, allowing the counts to be re-weighted
+const SampleDecisionKey = "sampleDecision"
+
+// Reasons for the sampling decision
+const (
+	ReasonPercentage  = "percentage"  // sampled, or not, by the percentage of the rule or of the config
+	ReasonError       = "error"       // error transactions are all sampled
+	ReasonSlow        = "slow"        // transactions slower than the threshold are all sampled
+	ReasonRateLimited = "rateLimited" // not sampled, the rate cap was reached
+)
+
 //TransactionDetails - details about the transaction that are used for sampling
 type TransactionDetails struct {
-	Status string
-	APIID  string
+	Status       string
+	APIID        string
+	Path         string
+	StatusDetail string // the status code of the transaction, e.g. 200
+	Application  string
+	Team         string
+	Duration     int // the duration in milliseconds
+}
+
+//Decision - the sampling decision of a transaction
+type Decision struct {
+	Sampled    bool   `json:"sampled"`
+	Rule       string `json:"rule,omitempty"` // the name of the matching rule, empty when no rule matched
+	Percentage int    `json:"percentage"`     // the percentage of the transactions sampled like this one
+	Reason     string `json:"reason"`
+}
+
+//MetaData - returns the decision to stamp in the event metadata
+func (d Decision) MetaData() map[string]interface{} {
+	meta := map[string]interface{}{
+		"sampled":    d.Sampled,
+		"percentage": d.Percentage,
+		"reason":     d.Reason,
+	}
+	if d.Rule != "" {
+		meta["rule"] = d.Rule
+	}
+	return meta
 }
@@ -3,6 +3,7 @@ package sampling
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/elastic/beats/v7/libbeat/publisher"
 )
@@ -12,9 +13,12 @@ var agentSamples *sample
 
 // Sampling - configures the sampling of events the agent sends to Amplify
 type Sampling struct {
-	Percentage      int  `config:"percentage"    validate:"min=0, max=100"`
-	PerAPI          bool `config:"per_api"`
-	ReportAllErrors bool `config:"reportAllErrors" yaml:"reportAllErrors"`
+	Percentage      int           `config:"percentage"    validate:"min=0, max=100"`
+	PerAPI          bool          `config:"per_api"`
+	ReportAllErrors bool          `config:"reportAllErrors" yaml:"reportAllErrors"`
+	Rules           []Rule        `config:"rules" yaml:"rules"`                 // evaluated in order, the first matching rule sets the percentage
+	RateLimit       float64       `config:"rateLimit" yaml:"rateLimit"`         // the maximum sampled transactions per second per API, 0 for no limit
+	SlowThreshold   time.Duration `config:"slowThreshold" yaml:"slowThreshold"` // transactions taking at least this long are always sampled, 0 to disable
 }
 
 //DefaultConfig - returns a default sampling config where all transactions are sent
@@ -32,9 +36,21 @@ func SetupSampling(cfg Sampling) error {
 	if cfg.Percentage < 0 || cfg.Percentage > countMax {
 		return fmt.Errorf("sampling percentage must be between 0 and 100")
 	}
+	if cfg.RateLimit < 0 {
+		return fmt.Errorf("sampling rateLimit must not be negative")
+	}
+	rules := make([]Rule, len(cfg.Rules))
+	for i, rule := range cfg.Rules {
+		if err := rule.validate(i); err != nil {
+			return err
+		}
+		rules[i] = rule
+	}
+	cfg.Rules = rules
 	agentSamples = &sample{
 		config:        cfg,
 		currentCounts: make(map[string]int),
+		buckets:       make(map[string]*tokenBucket),
 		counterLock:   sync.Mutex{},
 	}
 	return nil
@@ -48,6 +64,14 @@ func ShouldSampleTransaction(details TransactionDetails) (bool, error) {
 	return agentSamples.ShouldSampleTransaction(details), nil
 }
 
+// SampleTransaction - receives the transaction details and returns the sampling decision, with the matching rule
+func SampleTransaction(details TransactionDetails) (Decision, error) {
+	if agentSamples == nil {
+		return Decision{}, ErrGlobalSamplingCfg
+	}
+	return agentSamples.SampleTransaction(details), nil
+}
+
 // FilterEvents - returns an array of events that are part of the sample
 func FilterEvents(events []publisher.Event) ([]publisher.Event, error) {
 	if agentSamples == nil {
@@ -0,0 +1,70 @@
+package sampling
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Rule - a sampling rule, the first rule matching all of its set properties applies its percentage to the transaction
+type Rule struct {
+	Name          string        `config:"name" yaml:"name"`
+	APIID         string        `config:"apiID" yaml:"apiID"`
+	Path          string        `config:"path" yaml:"path"`               // the path, or a path prefix ending with *
+	StatusClass   string        `config:"statusClass" yaml:"statusClass"` // 1xx, 2xx, 3xx, 4xx or 5xx
+	Application   string        `config:"application" yaml:"application"`
+	Team          string        `config:"team" yaml:"team"`
+	Percentage    *int          `config:"percentage" yaml:"percentage"`       // 100 when not set, the matching transactions are all sampled
+	RateLimit     float64       `config:"rateLimit" yaml:"rateLimit"`         // the maximum sampled transactions per second per API, 0 for no limit
+	SlowThreshold time.Duration `config:"slowThreshold" yaml:"slowThreshold"` // overrides the slow threshold of the config when set
+}
+
+func (r *Rule) validate(index int) error {
+	if r.Name == "" {
+		r.Name = fmt.Sprintf("rule%d", index+1)
+	}
+	if r.Percentage == nil {
+		percentage := countMax
+		r.Percentage = &percentage
+	}
+	if *r.Percentage < 0 || *r.Percentage > countMax {
+		return ErrSamplingRuleCfg.FormatError(r.Name, "percentage must be between 0 and 100")
+	}
+	if r.RateLimit < 0 {
+		return ErrSamplingRuleCfg.FormatError(r.Name, "rateLimit must not be negative")
+	}
+	if r.StatusClass != "" {
+		class := strings.ToLower(r.StatusClass)
+		if len(class) != 3 || class[0] < '1' || class[0] > '5' || class[1:] != "xx" {
+			return ErrSamplingRuleCfg.FormatError(r.Name, "statusClass must be one of 1xx, 2xx, 3xx, 4xx or 5xx")
+		}
+	}
+	return nil
+}
+
+// matches - returns true when the transaction matches all the properties set on the rule
+func (r *Rule) matches(details TransactionDetails) bool {
+	if r.APIID != "" && r.APIID != details.APIID {
+		return false
+	}
+	if r.Path != "" && !matchPath(r.Path, details.Path) {
+		return false
+	}
+	if r.StatusClass != "" && (details.StatusDetail == "" || details.StatusDetail[0] != r.StatusClass[0]) {
+		return false
+	}
+	if r.Application != "" && r.Application != details.Application {
+		return false
+	}
+	if r.Team != "" && r.Team != details.Team {
+		return false
+	}
+	return true
+}
+
+func matchPath(pattern, path string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(path, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == path
+}
@@ -2,7 +2,9 @@ package sampling
 
 import (
 	"sync"
+	"time"
 
+	"github.com/Axway/agent-sdk/pkg/util/metrics"
 	"github.com/elastic/beats/v7/libbeat/publisher"
 )
 
@@ -10,23 +12,103 @@ import (
 type sample struct {
 	config        Sampling
 	currentCounts map[string]int
+	buckets       map[string]*tokenBucket
 	counterLock   sync.Mutex
+	now           func() time.Time
 }
 
 // ShouldSampleTransaction - receives the transaction details and returns true to sample it false to not
 func (s *sample) ShouldSampleTransaction(details TransactionDetails) bool {
+	return s.SampleTransaction(details).Sampled
+}
+
+// SampleTransaction - receives the transaction details and returns the sampling decision, recorded in the metrics
+func (s *sample) SampleTransaction(details TransactionDetails) Decision {
+	decision := s.decide(details)
+	result := "drop"
+	if decision.Sampled {
+		result = "keep"
+	}
+	metrics.IncCounter("agent_sampling_decisions", "The sampling decisions on the transactions, by result and reason",
+		metrics.Labels{"decision": result, "reason": decision.Reason}, 1)
+	return decision
+}
+
+// decide - returns the sampling decision of the transaction
+func (s *sample) decide(details TransactionDetails) Decision {
 	hasFailedStatus := details.Status == "Failure"
 	// sample the transaction if reportAllErrors is set to `true` and the trasaction summary's status is an error
 	if hasFailedStatus && s.config.ReportAllErrors {
-		return true
+		return Decision{Sampled: true, Percentage: countMax, Reason: ReasonError}
 	}
+
+	decision := Decision{Percentage: s.config.Percentage, Reason: ReasonPercentage}
+	counterName := globalCounter
+	rateLimit := s.config.RateLimit
+	slowThreshold := s.config.SlowThreshold
+	if rule := s.matchRule(details); rule != nil {
+		decision.Rule = rule.Name
+		decision.Percentage = *rule.Percentage
+		counterName = "rule:" + rule.Name
+		if rule.RateLimit > 0 {
+			rateLimit = rule.RateLimit
+		}
+		if rule.SlowThreshold > 0 {
+			slowThreshold = rule.SlowThreshold
+		}
+	}
+
+	// always keep the slow transactions
+	if slowThreshold > 0 && time.Duration(details.Duration)*time.Millisecond >= slowThreshold {
+		decision.Sampled = true
+		decision.Percentage = countMax
+		decision.Reason = ReasonSlow
+		return decision
+	}
+
 	if s.config.PerAPI && details.APIID != "" {
-		return s.shouldSampleWithCounter(details.APIID)
+		if decision.Rule == "" {
+			counterName = details.APIID
+		} else {
+			counterName += "/" + details.APIID
+		}
+	}
+	decision.Sampled = s.shouldSampleWithCounter(counterName, decision.Percentage)
+
+	if decision.Sampled && rateLimit > 0 && !s.takeToken(decision.Rule+"/"+details.APIID, rateLimit) {
+		decision.Sampled = false
+		decision.Reason = ReasonRateLimited
+	}
+	return decision
+}
+
+// matchRule - returns the first rule matching the transaction, nil when none match
+func (s *sample) matchRule(details TransactionDetails) *Rule {
+	for i := range s.config.Rules {
+		if s.config.Rules[i].matches(details) {
+			return &s.config.Rules[i]
+		}
+	}
+	return nil
+}
+
+// takeToken - returns true when the rate cap of the bucket allows one more sampled transaction
+func (s *sample) takeToken(bucketName string, rate float64) bool {
+	s.counterLock.Lock()
+	defer s.counterLock.Unlock()
+	now := time.Now()
+	if s.now != nil {
+		now = s.now()
+	}
+	bucket, found := s.buckets[bucketName]
+	if !found {
+		bucket = newTokenBucket(rate, now)
+		s.buckets[bucketName] = bucket
 	}
-	return s.shouldSampleWithCounter(globalCounter)
+	return bucket.take(now)
 }
 
-func (s *sample) shouldSampleWithCounter(counterName string) bool {
+func (s *sample) shouldSampleWithCounter(counterName string, percentage int) bool {
 	s.counterLock.Lock()
 	defer s.counterLock.Unlock()
 	// check if counter needs initiated
@@ -36,7 +118,7 @@ func (s *sample) shouldSampleWithCounter(counterName string) bool {
 
 	// Only sampling on percentage, not currently looking at the details
 	shouldSample := false
-	if s.currentCounts[counterName] < s.config.Percentage {
+	if s.currentCounts[counterName] < percentage {
 		shouldSample = true
 	}
 	s.currentCounts[counterName]++
@@ -52,7 +134,7 @@ func (s *sample) shouldSampleWithCounter(counterName string) bool {
 
 // FilterEvents - returns an array of events that are part of the sample
 func (s *sample) FilterEvents(events []publisher.Event) []publisher.Event {
-	if s.config.Percentage == countMax {
+	if s.config.Percentage == countMax && len(s.config.Rules) == 0 && s.config.RateLimit == 0 {
 		return events // all events are sampled by default
 	}
 
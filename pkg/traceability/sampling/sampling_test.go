@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Axway/agent-sdk/pkg/util/metrics"
 	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/common"
 	"github.com/elastic/beats/v7/libbeat/publisher"
@@ -61,6 +62,44 @@ func TestSamplingConfig(t *testing.T) {
 				Percentage: 50,
 			},
 		},
+		{
+			name:        "Good Config, Rules",
+			errExpected: false,
+			config: Sampling{
+				Percentage: 10,
+				Rules: []Rule{
+					{Name: "errors", StatusClass: "5xx", Percentage: percentage(100)},
+					{Path: "/health*", Percentage: percentage(0)},
+				},
+			},
+			expectedConfig: Sampling{
+				Percentage: 10,
+			},
+		},
+		{
+			name:        "Bad Config, Rule Percentage",
+			errExpected: true,
+			config: Sampling{
+				Percentage: 10,
+				Rules:      []Rule{{Percentage: percentage(101)}},
+			},
+		},
+		{
+			name:        "Bad Config, Rule Status Class",
+			errExpected: true,
+			config: Sampling{
+				Percentage: 10,
+				Rules:      []Rule{{StatusClass: "200", Percentage: percentage(50)}},
+			},
+		},
+		{
+			name:        "Bad Config, Negative Rate Limit",
+			errExpected: true,
+			config: Sampling{
+				Percentage: 10,
+				RateLimit:  -1,
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -340,3 +379,196 @@ func TestFilterEvents(t *testing.T) {
 		})
 	}
 }
+
+func TestSampleTransaction(t *testing.T) {
+	testCases := []struct {
+		name           string
+		config         Sampling
+		details        TransactionDetails
+		numberOfTests  int
+		expectedSample int
+		expectedRule   string
+		expectedReason string
+	}{
+		{
+			name: "No Rule Matches",
+			config: Sampling{
+				Percentage: 10,
+				Rules:      []Rule{{Name: "orders", APIID: "orders", Percentage: percentage(50)}},
+			},
+			details:        TransactionDetails{APIID: "pets", StatusDetail: "200"},
+			numberOfTests:  100,
+			expectedSample: 10,
+			expectedReason: ReasonPercentage,
+		},
+		{
+			name: "API Rule",
+			config: Sampling{
+				Percentage: 10,
+				Rules:      []Rule{{Name: "orders", APIID: "orders", Percentage: percentage(50)}},
+			},
+			details:        TransactionDetails{APIID: "orders", StatusDetail: "200"},
+			numberOfTests:  100,
+			expectedSample: 50,
+			expectedRule:   "orders",
+			expectedReason: ReasonPercentage,
+		},
+		{
+			name: "First Matching Rule",
+			config: Sampling{
+				Percentage: 10,
+				Rules: []Rule{
+					{Name: "server-errors", StatusClass: "5xx", Percentage: percentage(100)},
+					{Name: "orders", APIID: "orders", Percentage: percentage(50)},
+				},
+			},
+			details:        TransactionDetails{APIID: "orders", StatusDetail: "503"},
+			numberOfTests:  100,
+			expectedSample: 100,
+			expectedRule:   "server-errors",
+			expectedReason: ReasonPercentage,
+		},
+		{
+			name: "Path Prefix, Application and Team",
+			config: Sampling{
+				Percentage: 100,
+				Rules:      []Rule{{Path: "/health*", Application: "monitor", Team: "ops", Percentage: percentage(0)}},
+			},
+			details:        TransactionDetails{Path: "/health/live", Application: "monitor", Team: "ops"},
+			numberOfTests:  100,
+			expectedSample: 0,
+			expectedRule:   "rule1",
+			expectedReason: ReasonPercentage,
+		},
+		{
+			name: "Slow Transactions",
+			config: Sampling{
+				Percentage:    0,
+				SlowThreshold: time.Second,
+			},
+			details:        TransactionDetails{Duration: 1500},
+			numberOfTests:  100,
+			expectedSample: 100,
+			expectedReason: ReasonSlow,
+		},
+		{
+			name: "Slow Transactions, Rule Threshold",
+			config: Sampling{
+				Percentage:    0,
+				SlowThreshold: time.Second,
+				Rules:         []Rule{{Name: "reports", APIID: "reports", Percentage: percentage(0), SlowThreshold: 5 * time.Second}},
+			},
+			details:        TransactionDetails{APIID: "reports", Duration: 1500},
+			numberOfTests:  100,
+			expectedSample: 0,
+			expectedRule:   "reports",
+			expectedReason: ReasonPercentage,
+		},
+		{
+			name: "Rule Without Percentage",
+			config: Sampling{
+				Percentage: 0,
+				Rules:      []Rule{{Name: "orders", APIID: "orders"}},
+			},
+			details:        TransactionDetails{APIID: "orders"},
+			numberOfTests:  100,
+			expectedSample: 100,
+			expectedRule:   "orders",
+			expectedReason: ReasonPercentage,
+		},
+		{
+			name: "Errors Before Rules",
+			config: Sampling{
+				Percentage:      0,
+				ReportAllErrors: true,
+				Rules:           []Rule{{Name: "none", Percentage: percentage(0)}},
+			},
+			details:        TransactionDetails{Status: "Failure"},
+			numberOfTests:  100,
+			expectedSample: 100,
+			expectedReason: ReasonError,
+		},
+		{
+			name: "Rate Limited",
+			config: Sampling{
+				Percentage: 100,
+				Rules:      []Rule{{Name: "capped", APIID: "orders", Percentage: percentage(100), RateLimit: 5}},
+			},
+			details:        TransactionDetails{APIID: "orders"},
+			numberOfTests:  100,
+			expectedSample: 5,
+			expectedRule:   "capped",
+			expectedReason: ReasonRateLimited,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := SetupSampling(test.config)
+			assert.Nil(t, err)
+			// freeze the time so the rate limit does not refill
+			now := time.Now()
+			agentSamples.now = func() time.Time { return now }
+
+			keptBefore, droppedBefore := countDecisions()
+			sampled := 0
+			var decision Decision
+			for i := 0; i < test.numberOfTests; i++ {
+				decision, err = SampleTransaction(test.details)
+				assert.Nil(t, err)
+				if decision.Sampled {
+					sampled++
+				}
+			}
+			assert.Equal(t, test.expectedSample, sampled)
+			assert.Equal(t, test.expectedRule, decision.Rule)
+			assert.Equal(t, test.expectedReason, decision.Reason)
+
+			// the decisions are recorded in the metrics
+			kept, dropped := countDecisions()
+			assert.Equal(t, float64(sampled), kept-keptBefore)
+			assert.Equal(t, float64(test.numberOfTests-sampled), dropped-droppedBefore)
+		})
+	}
+}
+
+// percentage - returns a pointer to the percentage of a rule
+func percentage(value int) *int {
+	return &value
+}
+
+// countDecisions - returns the kept and dropped transactions recorded in the metrics
+func countDecisions() (kept, dropped float64) {
+	for _, family := range metrics.GetDefaultRegistry().Gather() {
+		if family.Name != "agent_sampling_decisions" {
+			continue
+		}
+		for _, sample := range family.Samples {
+			if sample.Labels["decision"] == "keep" {
+				kept += sample.Value
+			} else {
+				dropped += sample.Value
+			}
+		}
+	}
+	return kept, dropped
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	bucket := newTokenBucket(2, now)
+	assert.True(t, bucket.take(now))
+	assert.True(t, bucket.take(now))
+	assert.False(t, bucket.take(now))
+
+	// half a second refills one token
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, bucket.take(now))
+	assert.False(t, bucket.take(now))
+
+	// the tokens do not accumulate past one second of events
+	now = now.Add(time.Minute)
+	assert.True(t, bucket.take(now))
+	assert.True(t, bucket.take(now))
+	assert.False(t, bucket.take(now))
+}
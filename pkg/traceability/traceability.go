@@ -3,11 +3,13 @@ package traceability
 import (
 	"net/url"
 	"reflect"
+	"time"
 	"unsafe"
 
 	"github.com/Axway/agent-sdk/pkg/agent"
 	"github.com/Axway/agent-sdk/pkg/traceability/sampling"
 	"github.com/Axway/agent-sdk/pkg/util/log"
+	"github.com/Axway/agent-sdk/pkg/util/metrics"
 
 	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/common"
@@ -189,7 +191,7 @@ func (client *Client) Publish(batch publisher.Batch) error {
 	log.Infof("Publishing %d events", publishCount)
 	//update the local activity timestamp for the event to compare against
 	agent.UpdateLocalActivityTime()
-	err = client.transportClient.Publish(batch)
+	err = client.transportClient.Publish(&metricsBatch{Batch: batch, start: time.Now()})
 	if err != nil {
 		return err
 	}
@@ -197,6 +199,41 @@ func (client *Client) Publish(batch publisher.Batch) error {
 	return nil
 }
 
+// metricsBatch - records the published events and the ack latency when the batch is acknowledged, and the failure
+// when the batch is retried
+type metricsBatch struct {
+	publisher.Batch
+	start time.Time
+}
+
+func (b *metricsBatch) recordPublished(count int) {
+	metrics.IncCounter("agent_traceability_published_events", "The events published and acknowledged", nil, int64(count))
+	metrics.ObserveHistogram("agent_traceability_ack_duration_seconds", "The time until the published batches of events are acknowledged", nil, nil, time.Since(b.start).Seconds())
+}
+
+func (b *metricsBatch) recordFailure() {
+	metrics.IncCounter("agent_traceability_publish_failures", "The batches of events that could not be published and are retried", nil, 1)
+}
+
+// ACK - all events were published
+func (b *metricsBatch) ACK() {
+	b.recordPublished(len(b.Events()))
+	b.Batch.ACK()
+}
+
+// Retry - none of the events were published
+func (b *metricsBatch) Retry() {
+	b.recordFailure()
+	b.Batch.Retry()
+}
+
+// RetryEvents - the events were not published, the others were
+func (b *metricsBatch) RetryEvents(events []publisher.Event) {
+	b.recordPublished(len(b.Events()) - len(events))
+	b.recordFailure()
+	b.Batch.RetryEvents(events)
+}
+
 func (client *Client) String() string {
 	return traceabilityStr
 }
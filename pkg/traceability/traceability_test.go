@@ -14,6 +14,7 @@ import (
 	"github.com/Axway/agent-sdk/pkg/agent"
 	"github.com/Axway/agent-sdk/pkg/config"
 	"github.com/Axway/agent-sdk/pkg/traceability/sampling"
+	"github.com/Axway/agent-sdk/pkg/util/metrics"
 	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/common"
 	"github.com/elastic/beats/v7/libbeat/outputs"
@@ -279,12 +280,15 @@ func TestHTTPTransportWithJSONEncoding(t *testing.T) {
 	assert.NotNil(t, group)
 	traceabilityClient := group.Clients[0].(*Client)
 	batch := createBatch("{\"f1\":\"test\"}")
+	published, _ := metrics.GetOrRegisterCounter("agent_traceability_published_events", "", nil)
+	publishedBefore := published.Count()
 	traceabilityClient.Connect()
 	agent.StartPeriodicStatusUpdate()
 	err = traceabilityClient.Publish(batch)
 	traceabilityClient.Close()
 
 	assert.Nil(t, err)
+	assert.Equal(t, publishedBefore+1, published.Count())
 	publishedMessages := s.GetMessages()
 	assert.NotNil(t, publishedMessages)
 	assert.Equal(t, 1, len(publishedMessages))
@@ -391,10 +395,13 @@ func TestHTTPTransportRetries(t *testing.T) {
 	batch := createBatch("somemessage")
 
 	s.responseStatus = 404
+	failures, _ := metrics.GetOrRegisterCounter("agent_traceability_publish_failures", "", nil)
+	failuresBefore := failures.Count()
 	traceabilityClient.Connect()
 	err = traceabilityClient.Publish(batch)
 	traceabilityClient.Close()
 	assert.NotNil(t, err)
+	assert.Equal(t, failuresBefore+1, failures.Count())
 	assert.False(t, batch.acked)
 	assert.Equal(t, 1, batch.retryCount)
 
@@ -97,14 +97,16 @@ func (e *Generator) CreateEvents(summaryEvent LogEvent, detailEvents []LogEvent,
 	events := make([]beat.Event, 0)
 
 	// Add this to sample or not
-	shouldSample, err := sampling.ShouldSampleTransaction(e.createSamplingTransactionDetails(summaryEvent))
+	decision, err := sampling.SampleTransaction(e.createSamplingTransactionDetails(summaryEvent))
 	if err != nil {
 		return events, err
 	}
-	if shouldSample {
-		if metaData == nil {
-			metaData = common.MapStr{}
-		}
+	if metaData == nil {
+		metaData = common.MapStr{}
+	}
+	// stamp the decision, with the matching rule, so the sampled counts can be re-weighted
+	metaData.Put(sampling.SampleDecisionKey, common.MapStr(decision.MetaData()))
+	if decision.Sampled {
 		metaData.Put(sampling.SampleKey, true)
 	}
 
@@ -124,20 +126,28 @@ func (e *Generator) CreateEvents(summaryEvent LogEvent, detailEvents []LogEvent,
 
 // createSamplingTransactionDetails -
 func (e *Generator) createSamplingTransactionDetails(summaryEvent LogEvent) sampling.TransactionDetails {
-	var status string
-	var apiID string
-
-	if summaryEvent.TransactionSummary != nil {
-		status = summaryEvent.TransactionSummary.Status
-		if summaryEvent.TransactionSummary.Proxy != nil {
-			apiID = summaryEvent.TransactionSummary.Proxy.ID
+	details := sampling.TransactionDetails{}
+
+	summary := summaryEvent.TransactionSummary
+	if summary != nil {
+		details.Status = summary.Status
+		details.StatusDetail = summary.StatusDetail
+		details.Duration = summary.Duration
+		if summary.Proxy != nil {
+			details.APIID = summary.Proxy.ID
+		}
+		if summary.EntryPoint != nil {
+			details.Path = summary.EntryPoint.Path
+		}
+		if summary.Application != nil {
+			details.Application = summary.Application.ID
+		}
+		if summary.Team != nil {
+			details.Team = summary.Team.ID
 		}
 	}
 
-	return sampling.TransactionDetails{
-		Status: status,
-		APIID:  apiID,
-	}
+	return details
 }
 
 // healthcheck -
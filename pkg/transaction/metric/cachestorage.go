@@ -1,15 +1,19 @@
 package metric
 
 import (
+	"encoding/json"
 	"flag"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/Axway/agent-sdk/pkg/agent"
 	"github.com/Axway/agent-sdk/pkg/cache"
 	"github.com/Axway/agent-sdk/pkg/traceability"
+	metrics "github.com/rcrowley/go-metrics"
 )
 
 const (
@@ -22,9 +26,10 @@ const (
 type storageCache interface {
 	initialize()
 	updateUsage(usageCount int)
-	// updateMetric(apiStatusMetric metrics.Histogram, apiMetric *APIMetric)
-	// removeMetric(apiMetric *APIMetric)
+	updateMetric(apiStatusMetric metrics.Histogram, apiMetric *APIMetric)
+	removeMetric(apiMetric *APIMetric)
 	save()
+	size() int
 }
 
 type cacheStorage struct {
@@ -47,10 +52,15 @@ func newStorageCache(collector *collector, cacheFilePath string) storageCache {
 	return storageCache
 }
 
+// size - returns the items in the storage cache
+func (c *cacheStorage) size() int {
+	return len(c.storage.GetKeys())
+}
+
 func (c *cacheStorage) initialize() {
 	storageCache := cache.Load(c.cacheFilePath)
 	c.loadUsage(storageCache)
-	// c.loadAPIMetric(storageCache)
+	c.loadAPIMetric(storageCache)
 
 	// Not a job as the loop requires signal processing
 	if !c.isInitialized && flag.Lookup("test.v") == nil {
@@ -86,56 +96,68 @@ func (c *cacheStorage) updateUsage(usageCount int) {
 	c.storage.Set(usageCountKey, usageCount)
 }
 
-// func (c *cacheStorage) loadAPIMetric(storageCache cache.Cache) {
-// 	cacheKeys := storageCache.GetKeys()
-// 	for _, cacheKey := range cacheKeys {
-// 		if strings.Contains(cacheKey, metricKeyPrefix) {
-// 			cacheItem, _ := storageCache.Get(cacheKey)
-
-// 			buffer, _ := json.Marshal(cacheItem)
-// 			var apiMetric cachedMetric
-// 			json.Unmarshal(buffer, &apiMetric)
-
-// 			storageCache.Set(cacheKey, apiMetric)
-
-// 			var apiStatusMetric *APIMetric
-// 			for _, duration := range apiMetric.Values {
-// 				apiStatusMetric = c.collector.updateMetric(apiMetric.API.ID, apiMetric.API.Name, apiMetric.StatusCode, duration)
-// 			}
-// 			if apiStatusMetric != nil {
-// 				apiStatusMetric.StartTime = apiMetric.StartTime
-// 			}
-// 		}
-// 	}
-// }
-
-// func (c *cacheStorage) updateMetric(apiStatusMetric metrics.Histogram, apiMetric *APIMetric) {
-// 	if !c.isInitialized {
-// 		return
-// 	}
-
-// 	c.storageLock.Lock()
-// 	defer c.storageLock.Unlock()
-
-// 	cachedAPIMetric := cachedMetric{
-// 		API:        apiMetric.API,
-// 		StatusCode: apiMetric.StatusCode,
-// 		Count:      apiStatusMetric.Count(),
-// 		Values:     apiStatusMetric.Sample().Values(),
-// 		StartTime:  apiMetric.StartTime,
-// 	}
-// 	c.storage.Set(metricKeyPrefix + apiMetric.API.ID + "."+apiMetric.StatusCode, cachedAPIMetric)
-// }
-
-// func (c *cacheStorage) removeMetric(apiMetric *APIMetric) {
-// 	if !c.isInitialized {
-// 		return
-// 	}
-
-// 	c.storageLock.Lock()
-// 	defer c.storageLock.Unlock()
-// 	c.storage.Delete(metricKeyPrefix + apiMetric.API.ID + "." + apiMetric.StatusCode)
-// }
+func (c *cacheStorage) loadAPIMetric(storageCache cache.Cache) {
+	cacheKeys := storageCache.GetKeys()
+	for _, cacheKey := range cacheKeys {
+		if strings.HasPrefix(cacheKey, metricKeyPrefix) {
+			cacheItem, _ := storageCache.Get(cacheKey)
+
+			buffer, _ := json.Marshal(cacheItem)
+			var apiMetric cachedMetric
+			json.Unmarshal(buffer, &apiMetric)
+
+			// the stored metrics are restored whether or not the metric event client is set yet, they are published
+			// once it is set
+			var apiStatusMetric *APIMetric
+			if agent.GetCentralConfig().CanPublishMetricEvent() {
+				for _, duration := range apiMetric.Values {
+					apiStatusMetric = c.collector.addMetric(apiMetric.API.ID, apiMetric.API.Name, apiMetric.StatusCode, duration, apiMetric.AppName, apiMetric.TeamName)
+				}
+			}
+			if apiStatusMetric == nil {
+				// not restored, not kept in the storage either
+				storageCache.Delete(cacheKey)
+				continue
+			}
+			apiStatusMetric.StartTime = apiMetric.StartTime
+			storageCache.Set(cacheKey, apiMetric)
+		}
+	}
+}
+
+func (c *cacheStorage) updateMetric(apiStatusMetric metrics.Histogram, apiMetric *APIMetric) {
+	if !c.isInitialized {
+		return
+	}
+
+	c.storageLock.Lock()
+	defer c.storageLock.Unlock()
+
+	cachedAPIMetric := cachedMetric{
+		API:        apiMetric.API,
+		AppName:    apiMetric.AppName,
+		TeamName:   apiMetric.TeamName,
+		StatusCode: apiMetric.StatusCode,
+		Count:      apiStatusMetric.Count(),
+		Values:     apiStatusMetric.Sample().Values(),
+		StartTime:  apiMetric.StartTime,
+	}
+	c.storage.Set(getCachedMetricKey(apiMetric), cachedAPIMetric)
+}
+
+func (c *cacheStorage) removeMetric(apiMetric *APIMetric) {
+	if !c.isInitialized {
+		return
+	}
+
+	c.storageLock.Lock()
+	defer c.storageLock.Unlock()
+	c.storage.Delete(getCachedMetricKey(apiMetric))
+}
+
+func getCachedMetricKey(apiMetric *APIMetric) string {
+	return metricKeyPrefix + apiMetric.API.ID + "." + getStatusMetricKey(apiMetric.StatusCode, apiMetric.AppName, apiMetric.TeamName)
+}
 
 func (c *cacheStorage) save() {
 	if !c.isInitialized {
@@ -7,6 +7,10 @@ type ResponseMetrics struct {
 	Max int64   `json:"max"`
 	Min int64   `json:"min"`
 	Avg float64 `json:"avg"`
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
 }
 
 // ObservationDetails - Holds start and end timestamp for interval
@@ -24,6 +28,8 @@ type APIDetails struct {
 // APIMetric - struct to hold metric specific for status code based API transactions
 type APIMetric struct {
 	API         APIDetails         `json:"api"`
+	AppName     string             `json:"appName,omitempty"`
+	TeamName    string             `json:"teamName,omitempty"`
 	StatusCode  string             `json:"statusCode"`
 	Count       int64              `json:"count"`
 	Response    ResponseMetrics    `json:"response"`
@@ -34,28 +40,30 @@ type APIMetric struct {
 // cachedMetric - struct to hold metric specific that gets cached and used for agent recovery
 type cachedMetric struct {
 	API        APIDetails `json:"api"`
+	AppName    string     `json:"appName,omitempty"`
+	TeamName   string     `json:"teamName,omitempty"`
 	StatusCode string     `json:"statusCode"`
 	Count      int64      `json:"count"`
 	Values     []int64    `json:"values"`
 	StartTime  time.Time  `json:"startTime"`
 }
 
-// // V4EventDistribution - represents V7 distribution
-// type V4EventDistribution struct {
-// 	Environment string `json:"environment"`
-// 	Version     string `json:"version"`
-// }
+// V4EventDistribution - represents V7 distribution
+type V4EventDistribution struct {
+	Environment string `json:"environment"`
+	Version     string `json:"version"`
+}
 
-// // V4Event - represents V7 event
-// type V4Event struct {
-// 	ID           string              `json:"id"`
-// 	Timestamp    int64               `json:"timestamp"`
-// 	Event        string              `json:"event"`
-// 	App          string              `json:"app"` // ORG GUID
-// 	Version      string              `json:"version"`
-// 	Distribution V4EventDistribution `json:"distribution"`
-// 	Data         interface{}         `json:"data"`
-// }
+// V4Event - represents V7 event
+type V4Event struct {
+	ID           string              `json:"id"`
+	Timestamp    int64               `json:"timestamp"`
+	Event        string              `json:"event"`
+	App          string              `json:"app"` // ORG GUID
+	Version      string              `json:"version"`
+	Distribution V4EventDistribution `json:"distribution"`
+	Data         interface{}         `json:"data"`
+}
 
 // LighthouseUsageReport -Lighthouse Usage report
 type LighthouseUsageReport struct {
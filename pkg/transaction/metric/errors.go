@@ -0,0 +1,8 @@
+package metric
+
+import "github.com/Axway/agent-sdk/pkg/util/errors"
+
+// Metric errors
+var (
+	ErrMetricEventClientNotSet = errors.New(1560, "the metric event client has not been set, unable to publish metric events")
+)
@@ -1,7 +1,9 @@
 package metric
 
 import (
+	"context"
 	"flag"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,7 +12,9 @@ import (
 	"github.com/Axway/agent-sdk/pkg/jobs"
 	"github.com/Axway/agent-sdk/pkg/traceability"
 	"github.com/Axway/agent-sdk/pkg/util/log"
+	sdkmetrics "github.com/Axway/agent-sdk/pkg/util/metrics"
 	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 	metrics "github.com/rcrowley/go-metrics"
 )
 
@@ -58,34 +62,34 @@ func (qi *usageEventQueueItem) GetMetric() interface{} {
 	return qi.metric
 }
 
-// type metricEventPublishItem interface {
-// 	publishQueueItem
-// 	GetAPIID() string
-// 	GetStatusCode() string
-// }
+type metricEventPublishItem interface {
+	publishQueueItem
+	GetAPIID() string
+	GetStatusKey() string
+}
 
-// type metricEventQueueItem struct {
-// 	metricEventPublishItem
-// 	event     V4Event
-// 	metric    metrics.Histogram
-// 	apiMetric *APIMetric
-// }
+type metricEventQueueItem struct {
+	metricEventPublishItem
+	event     V4Event
+	metric    metrics.Histogram
+	apiMetric *APIMetric
+}
 
-// func (qi *metricEventQueueItem) GetEvent() interface{} {
-// 	return qi.event
-// }
+func (qi *metricEventQueueItem) GetEvent() interface{} {
+	return qi.event
+}
 
-// func (qi *metricEventQueueItem) GetMetric() interface{} {
-// 	return qi.metric
-// }
+func (qi *metricEventQueueItem) GetMetric() interface{} {
+	return qi.metric
+}
 
-// func (qi *metricEventQueueItem) GetAPIID() string {
-// 	return qi.apiMetric.API.ID
-// }
+func (qi *metricEventQueueItem) GetAPIID() string {
+	return qi.apiMetric.API.ID
+}
 
-// func (qi *metricEventQueueItem) GetStatusCode() string {
-// 	return qi.apiMetric.StatusCode
-// }
+func (qi *metricEventQueueItem) GetStatusKey() string {
+	return getStatusMetricKey(qi.apiMetric.StatusCode, qi.apiMetric.AppName, qi.apiMetric.TeamName)
+}
 
 var globalMetricCollector Collector
 
@@ -115,15 +119,33 @@ func createMetricCollector() Collector {
 
 	if flag.Lookup("test.v") == nil {
 		var err error
-		metricCollector.jobID, err = jobs.RegisterIntervalJob(metricCollector, agent.GetCentralConfig().GetEventAggregationInterval())
+		// a failed collection does not stop the other agent jobs, the collector is restarted with backoff
+		metricCollector.jobID, err = jobs.RegisterIntervalJob(metricCollector, agent.GetCentralConfig().GetEventAggregationInterval(), jobs.WithJobName("metric-collector"), jobs.WithFailurePolicy(jobs.FailurePolicyRestart))
 		if err != nil {
 			panic(err)
 		}
+
+		// the metrics collected since the last save are not lost on shutdown
+		agent.RegisterShutdownHook("flushing the metric cache", func(ctx context.Context) error {
+			metricCollector.storage.save()
+			return nil
+		})
+		sdkmetrics.RegisterCollector("metric-cache", metricCollector.collectCacheMetrics)
 	}
 
 	return metricCollector
 }
 
+// collectCacheMetrics - returns the items in the cache of the usage and API metrics
+func (c *collector) collectCacheMetrics() []sdkmetrics.Family {
+	return []sdkmetrics.Family{{
+		Name:    "agent_cache_items",
+		Help:    "The items in the cache",
+		Type:    sdkmetrics.GaugeType,
+		Samples: []sdkmetrics.Sample{{Labels: sdkmetrics.Labels{"cache": "metrics"}, Value: float64(c.storage.size())}},
+	}}
+}
+
 // Status - returns the status of the metric collector
 func (c *collector) Status() error {
 	return nil
@@ -163,7 +185,7 @@ func (c *collector) AddMetric(apiID, apiName, statusCode string, duration int64,
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	c.updateUsage(1)
-	// c.updateMetric(apiID, apiName, statusCode, duration)
+	c.updateMetric(apiID, apiName, statusCode, duration, appName, teamName)
 }
 
 func (c *collector) updateUsage(count int64) {
@@ -172,33 +194,54 @@ func (c *collector) updateUsage(count int64) {
 	c.storage.updateUsage(int(transactionCount.Count()))
 }
 
-// func (c *collector) updateMetric(apiID, apiName, statusCode string, duration int64) *APIMetric {
-// 	apiStatusDuration := c.getOrRegisterHistogram("transaction.status." + apiID + "." + statusCode)
-
-// 	apiStatusMap, ok := c.metricMap[apiID]
-// 	if !ok {
-// 		apiStatusMap = make(map[string]*APIMetric)
-// 		c.metricMap[apiID] = apiStatusMap
-// 	}
-
-// 	_, ok = apiStatusMap[statusCode]
-// 	if !ok {
-// 		// First api metric for api+statuscode,
-// 		// setup the start time to be used for reporting metric event
-// 		apiStatusMap[statusCode] = &APIMetric{
-// 			API: APIDetails{
-// 				Name: apiName,
-// 				ID:   apiID,
-// 			},
-// 			StatusCode: statusCode,
-// 			StartTime:  time.Now(),
-// 		}
-// 	}
-
-// 	apiStatusDuration.Update(duration)
-// 	c.storage.updateMetric(apiStatusDuration, apiStatusMap[statusCode])
-// 	return apiStatusMap[statusCode]
-// }
+func (c *collector) updateMetric(apiID, apiName, statusCode string, duration int64, appName, teamName string) *APIMetric {
+	// the API metrics are only drained when published, they are not collected until the metric event client is set
+	if !agent.GetCentralConfig().CanPublishMetricEvent() || getMetricEventClient() == nil {
+		return nil
+	}
+	return c.addMetric(apiID, apiName, statusCode, duration, appName, teamName)
+}
+
+// addMetric - records the duration in the metric of the API, status code, app and team
+func (c *collector) addMetric(apiID, apiName, statusCode string, duration int64, appName, teamName string) *APIMetric {
+	apiStatusMap, ok := c.metricMap[apiID]
+	if !ok {
+		apiStatusMap = make(map[string]*APIMetric)
+		c.metricMap[apiID] = apiStatusMap
+	}
+
+	statusKey := getStatusMetricKey(statusCode, appName, teamName)
+	apiStatusMetric, ok := apiStatusMap[statusKey]
+	if !ok {
+		// First api metric for api+statuscode+app+team,
+		// setup the start time to be used for reporting metric event
+		apiStatusMetric = &APIMetric{
+			API: APIDetails{
+				Name: apiName,
+				ID:   apiID,
+			},
+			AppName:    appName,
+			TeamName:   teamName,
+			StatusCode: statusCode,
+			StartTime:  time.Now(),
+		}
+		apiStatusMap[statusKey] = apiStatusMetric
+	}
+
+	apiStatusDuration := c.getOrRegisterHistogram(getHistogramName(apiID, statusKey))
+	apiStatusDuration.Update(duration)
+	c.storage.updateMetric(apiStatusDuration, apiStatusMetric)
+	return apiStatusMetric
+}
+
+// getStatusMetricKey - returns the key identifying the metric for the status code, app and team within an API
+func getStatusMetricKey(statusCode, appName, teamName string) string {
+	return strings.Join([]string{statusCode, appName, teamName}, "|")
+}
+
+func getHistogramName(apiID, statusKey string) string {
+	return "transaction.status." + apiID + "." + statusKey
+}
 
 func (c *collector) cleanup() {
 	c.publishItemQueue = make([]publishQueueItem, 0)
@@ -227,6 +270,13 @@ func (c *collector) generateEvents() {
 	}
 
 	c.registry.Each(c.processUsageFromRegistry)
+	if agent.GetCentralConfig().CanPublishMetricEvent() {
+		if getMetricEventClient() != nil {
+			c.processTransactionMetrics()
+		} else {
+			log.Errorf("%s, the API metrics are not collected", ErrMetricEventClientNotSet.Error())
+		}
+	}
 	if len(c.publishItemQueue) == 0 {
 		log.Infof("No usage/metric event generated as no transactions recorded [start timestamp: %d, end timestamp: %d]", convertTimeToMillis(c.startTime), convertTimeToMillis(c.endTime))
 	}
@@ -241,8 +291,6 @@ func (c *collector) processUsageFromRegistry(name string, metric interface{}) {
 			log.Info("Publishing the usage event is turned off")
 		}
 	}
-
-	// c.processTransactionMetric(name, metric)
 }
 
 func (c *collector) generateUsageEvent(transactionCounter metrics.Counter, orgGUID string) {
@@ -278,55 +326,57 @@ func (c *collector) generateLighthouseUsageEvent(transactionCount metrics.Counte
 	log.Infof("Generated usage events [start timestamp: %d, end timestamp: %d]", convertTimeToMillis(c.startTime), convertTimeToMillis(c.endTime))
 }
 
-// func (c *collector) processTransactionMetric(metricName string, metric interface{}) {
-// 	elements := strings.Split(metricName, ".")
-// 	if len(elements) > 2 {
-// 		apiID := elements[2]
-// 		apiStatusMap, ok := c.metricMap[apiID]
-// 		if ok {
-// 			if strings.HasPrefix(metricName, "transaction.status") {
-// 				statusCode := elements[3]
-// 				statusCodeDetail, ok := apiStatusMap[statusCode]
-// 				if ok {
-// 					statusMetric := (metric.(metrics.Histogram))
-// 					c.setEventMetricsFromHistogram(statusCodeDetail, statusMetric)
-// 					c.generateAPIStatusMetricEvent(statusMetric, statusCodeDetail)
-// 				}
-// 			}
-// 		}
-// 	}
-// }
-
-// func (c *collector) setEventMetricsFromHistogram(apiStatusDetails *APIMetric, histogram metrics.Histogram) {
-// 	apiStatusDetails.Count = histogram.Count()
-// 	apiStatusDetails.Response.Max = histogram.Max()
-// 	apiStatusDetails.Response.Min = histogram.Min()
-// 	apiStatusDetails.Response.Avg = histogram.Mean()
-// }
-
-// func (c *collector) generateAPIStatusMetricEvent(histogram metrics.Histogram, apiStatusMetric *APIMetric) {
-// 	apiStatusMetric.Observation.Start = convertTimeToMillis(apiStatusMetric.StartTime)
-// 	apiStatusMetric.Observation.End = convertTimeToMillis(c.endTime)
-// 	apiStatusMetricEventID, _ := uuid.NewV4()
-// 	apiStatusMetricEvent := V4Event{
-// 		ID:        apiStatusMetricEventID.String(),
-// 		Timestamp: apiStatusMetric.StartTime.UnixNano() / 1e6,
-// 		Event:     "api.transaction.status.metric",
-// 		App:       c.orgGUID,
-// 		Version:   "4",
-// 		Distribution: V4EventDistribution{
-// 			Environment: agent.GetCentralConfig().GetEnvironmentID(),
-// 			Version:     "1",
-// 		},
-// 		Data: apiStatusMetric,
-// 	}
-// 	queueItem := &metricEventQueueItem{
-// 		event:     apiStatusMetricEvent,
-// 		metric:    histogram,
-// 		apiMetric: apiStatusMetric,
-// 	}
-// 	c.publishItemQueue = append(c.publishItemQueue, queueItem)
-// }
+func (c *collector) processTransactionMetrics() {
+	for apiID, apiStatusMap := range c.metricMap {
+		for statusKey, apiStatusMetric := range apiStatusMap {
+			histogram := c.registry.Get(getHistogramName(apiID, statusKey))
+			if histogram == nil {
+				continue
+			}
+			statusMetric := histogram.(metrics.Histogram)
+			if statusMetric.Count() == 0 {
+				continue
+			}
+			c.setEventMetricsFromHistogram(apiStatusMetric, statusMetric)
+			c.generateAPIStatusMetricEvent(statusMetric, apiStatusMetric)
+		}
+	}
+}
+
+func (c *collector) setEventMetricsFromHistogram(apiStatusDetails *APIMetric, histogram metrics.Histogram) {
+	percentiles := histogram.Percentiles([]float64{0.5, 0.9, 0.95, 0.99})
+	apiStatusDetails.Count = histogram.Count()
+	apiStatusDetails.Response.Max = histogram.Max()
+	apiStatusDetails.Response.Min = histogram.Min()
+	apiStatusDetails.Response.Avg = histogram.Mean()
+	apiStatusDetails.Response.P50 = percentiles[0]
+	apiStatusDetails.Response.P90 = percentiles[1]
+	apiStatusDetails.Response.P95 = percentiles[2]
+	apiStatusDetails.Response.P99 = percentiles[3]
+}
+
+func (c *collector) generateAPIStatusMetricEvent(histogram metrics.Histogram, apiStatusMetric *APIMetric) {
+	apiStatusMetric.Observation.Start = convertTimeToMillis(apiStatusMetric.StartTime)
+	apiStatusMetric.Observation.End = convertTimeToMillis(c.endTime)
+	apiStatusMetricEvent := V4Event{
+		ID:        uuid.New().String(),
+		Timestamp: convertTimeToMillis(apiStatusMetric.StartTime),
+		Event:     "api.transaction.status.metric",
+		App:       c.orgGUID,
+		Version:   "4",
+		Distribution: V4EventDistribution{
+			Environment: agent.GetCentralConfig().GetEnvironmentID(),
+			Version:     "1",
+		},
+		Data: *apiStatusMetric,
+	}
+	queueItem := &metricEventQueueItem{
+		event:     apiStatusMetricEvent,
+		metric:    histogram,
+		apiMetric: apiStatusMetric,
+	}
+	c.publishItemQueue = append(c.publishItemQueue, queueItem)
+}
 
 func (c *collector) getOrRegisterCounter(name string) metrics.Counter {
 	counter := c.registry.Get(name)
@@ -363,12 +413,12 @@ func (c *collector) publishEvents() {
 }
 
 func (c *collector) cleanupCounters(eventQueueItem publishQueueItem) {
-	// // Check metricEventPublishItem interface first since usageEventPublishItem will pass for metric event item as well
-	// metricEventItem, ok := eventQueueItem.(metricEventPublishItem)
-	// if ok {
-	// 	c.cleanupMetricCounter(metricEventItem)
-	// 	return
-	// }
+	// Check metricEventPublishItem interface first since usageEventPublishItem will pass for metric event item as well
+	metricEventItem, ok := eventQueueItem.(metricEventPublishItem)
+	if ok {
+		c.cleanupMetricCounter(metricEventItem)
+		return
+	}
 
 	usageEventItem, ok := eventQueueItem.(usageEventPublishItem)
 	if ok {
@@ -387,21 +437,25 @@ func (c *collector) cleanupUsageCounter(usageEventItem usageEventPublishItem) {
 	}
 }
 
-// func (c *collector) cleanupMetricCounter(metricEventItem metricEventPublishItem) {
-// 	itemMetric := metricEventItem.GetMetric()
-// 	histogram, ok := itemMetric.(metrics.Histogram)
-// 	if ok {
-// 		// Clean up entry in api status metric map and histogram counter
-// 		apiStatusMap, ok := c.metricMap[metricEventItem.GetAPIID()]
-// 		if ok {
-// 			c.storage.removeMetric(apiStatusMap[metricEventItem.GetStatusCode()])
-// 			delete(apiStatusMap, metricEventItem.GetStatusCode())
-// 			if len(apiStatusMap) != 0 {
-// 				c.metricMap[metricEventItem.GetAPIID()] = apiStatusMap
-// 			} else {
-// 				delete(c.metricMap, metricEventItem.GetAPIID())
-// 			}
-// 			histogram.Clear()
-// 		}
-// 	}
-// }
+func (c *collector) cleanupMetricCounter(metricEventItem metricEventPublishItem) {
+	itemMetric := metricEventItem.GetMetric()
+	histogram, ok := itemMetric.(metrics.Histogram)
+	if ok {
+		// Clean up entry in api status metric map and histogram counter
+		apiID := metricEventItem.GetAPIID()
+		statusKey := metricEventItem.GetStatusKey()
+		apiStatusMap, ok := c.metricMap[apiID]
+		if !ok {
+			return
+		}
+		if apiStatusMetric, ok := apiStatusMap[statusKey]; ok {
+			c.storage.removeMetric(apiStatusMetric)
+			delete(apiStatusMap, statusKey)
+			if len(apiStatusMap) == 0 {
+				delete(c.metricMap, apiID)
+			}
+			histogram.Clear()
+			c.registry.Unregister(getHistogramName(apiID, statusKey))
+		}
+	}
+}
@@ -9,10 +9,16 @@ import (
 	"mime/multipart"
 	"net/textproto"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/Axway/agent-sdk/pkg/agent"
 	"github.com/Axway/agent-sdk/pkg/api"
+	"github.com/Axway/agent-sdk/pkg/traceability"
+	"github.com/Axway/agent-sdk/pkg/traceability/sampling"
 	"github.com/Axway/agent-sdk/pkg/util/log"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
 	"github.com/google/uuid"
 )
 
@@ -25,12 +31,69 @@ type metricPublisher struct {
 	apiClient api.Client
 }
 
+var (
+	metricEventClient     beat.Client
+	metricEventClientLock sync.RWMutex
+)
+
+// SetMetricEventClient - sets the beat client used by the collector to publish the API metric events, the API metrics
+// are not collected until it is set
+func SetMetricEventClient(client beat.Client) {
+	metricEventClientLock.Lock()
+	defer metricEventClientLock.Unlock()
+	metricEventClient = client
+}
+
+// getMetricEventClient - returns the beat client publishing the API metric events, nil when not set
+func getMetricEventClient() beat.Client {
+	metricEventClientLock.RLock()
+	defer metricEventClientLock.RUnlock()
+	return metricEventClient
+}
+
 func (pj *metricPublisher) publishEvent(event interface{}) error {
-	lighthouseUsageEvent, ok := event.(LighthouseUsageEvent)
-	if ok {
-		return pj.publishToLighthouse(lighthouseUsageEvent)
+	switch publishEvent := event.(type) {
+	case LighthouseUsageEvent:
+		return pj.publishToLighthouse(publishEvent)
+	case V4Event:
+		return pj.publishMetricEvent(publishEvent)
+	}
+	log.Error("event was not a lighthouse or metric event")
+	return nil
+}
+
+func (pj *metricPublisher) publishMetricEvent(event V4Event) error {
+	client := getMetricEventClient()
+	if client == nil {
+		return ErrMetricEventClientNotSet
+	}
+
+	serializedEvent, err := json.Marshal(event)
+	if err != nil {
+		return err
 	}
-	log.Error("event was not a lighthouse event")
+
+	eventFields := common.MapStr{
+		"message": string(serializedEvent),
+	}
+	if !traceability.IsHTTPTransport() {
+		token, err := agent.GetCentralAuthToken()
+		if err != nil {
+			return err
+		}
+		eventFields["fields"] = map[string]string{
+			"token":             token,
+			"axway-target-flow": "api-central-v8",
+		}
+	}
+
+	log.Debugf("Payload for Metric event : %s\n", string(serializedEvent))
+	client.Publish(beat.Event{
+		Timestamp: time.Now(),
+		// Metric events are aggregates, they should never be dropped by transaction sampling
+		Meta:   common.MapStr{sampling.SampleKey: true},
+		Fields: eventFields,
+	})
 	return nil
 }
 
@@ -95,7 +158,7 @@ func (pj *metricPublisher) createFilePart(w *multipart.Writer, filename string)
 func newMetricPublisher() publisher {
 	centralCfg := agent.GetCentralConfig()
 	publisher := &metricPublisher{
-		apiClient: api.NewClient(centralCfg.GetTLSConfig(), centralCfg.GetProxyURL()),
+		apiClient: api.NewClient(centralCfg.GetTLSConfig(), centralCfg.GetProxyURL(), api.WithRetryPolicy(api.DefaultRetryPolicy()), api.WithContext(agent.GetContext())),
 	}
 
 	return publisher
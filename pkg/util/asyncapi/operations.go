@@ -0,0 +1,110 @@
+package asyncapi
+
+// OperationInfo - an operation of a channel, with its messages, security and protocol bindings
+type OperationInfo struct {
+	Channel         string // the address of the channel, or topic
+	Action          string // publish or subscribe for 2.x, send or receive for 3.x
+	OperationID     string
+	Summary         string
+	Description     string
+	Messages        []string // the names of the messages
+	Security        []string // the names of the security schemes
+	ChannelBindings *ChannelBindings
+	Bindings        *OperationBindings
+}
+
+// GetOperations - returns the operations of the channels, 2.x operations are ordered by channel and 3.x operations
+// by operation name
+func (d *Document) GetOperations() []OperationInfo {
+	operations := make([]OperationInfo, 0)
+	if d.MajorVersion() == 3 {
+		for _, name := range sortedKeys(d.Operations) {
+			op := d.Operations[name]
+			channelName := refName(op.Channel.Ref)
+			channel := d.Channels[channelName]
+			info := newOperationInfo(op, op.Action, channel)
+			if info.OperationID == "" {
+				info.OperationID = name
+			}
+			info.Channel = channelName
+			if channel != nil && channel.Address != "" {
+				info.Channel = channel.Address
+			}
+			if len(op.Messages) > 0 {
+				info.Messages = messageNames(op.Messages)
+			} else if channel != nil {
+				info.Messages = sortedMessageNames(channel.Messages)
+			}
+			operations = append(operations, info)
+		}
+		return operations
+	}
+
+	for _, name := range sortedKeys(d.Channels) {
+		channel := d.Channels[name]
+		if channel == nil {
+			continue
+		}
+		for _, action := range []string{ActionPublish, ActionSubscribe} {
+			op := channel.Publish
+			if action == ActionSubscribe {
+				op = channel.Subscribe
+			}
+			if op == nil {
+				continue
+			}
+			info := newOperationInfo(op, action, channel)
+			info.Channel = name
+			if op.Message != nil {
+				info.Messages = messageNames([]*Message{op.Message})
+			}
+			operations = append(operations, info)
+		}
+	}
+	return operations
+}
+
+func newOperationInfo(op *Operation, action string, channel *Channel) OperationInfo {
+	info := OperationInfo{
+		Action:      action,
+		OperationID: op.OperationID,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Messages:    make([]string, 0),
+		Security:    securitySchemeNames(op.Security),
+		Bindings:    op.Bindings,
+	}
+	if channel != nil {
+		info.ChannelBindings = channel.Bindings
+	}
+	return info
+}
+
+// messageNames - returns the names of the messages, the one of messages are flattened
+func messageNames(messages []*Message) []string {
+	names := make([]string, 0)
+	for _, message := range messages {
+		switch {
+		case message == nil:
+		case len(message.OneOf) > 0:
+			names = append(names, messageNames(message.OneOf)...)
+		case message.Ref != "":
+			names = append(names, refName(message.Ref))
+		case message.Name != "":
+			names = append(names, message.Name)
+		case message.MessageID != "":
+			names = append(names, message.MessageID)
+		default:
+			names = append(names, message.Title)
+		}
+	}
+	return names
+}
+
+func sortedMessageNames(messages map[string]*Message) []string {
+	keys := make(map[string]interface{}, len(messages))
+	for key := range messages {
+		keys[key] = nil
+	}
+	return sortedKeys(keys)
+}
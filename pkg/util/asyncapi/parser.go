@@ -0,0 +1,209 @@
+package asyncapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Operation actions, 2.x publish and subscribe operations are described from the point of view of the client
+const (
+	ActionPublish   = "publish"
+	ActionSubscribe = "subscribe"
+	ActionSend      = "send"
+	ActionReceive   = "receive"
+)
+
+var variableRegEx = regexp.MustCompile(`{([^}]+)}`)
+
+// Parse - parses and validates an AsyncAPI 2.x or 3.x document, in YAML or JSON
+func Parse(spec []byte) (*Document, error) {
+	doc := &Document{}
+	if err := yaml.Unmarshal(spec, doc); err != nil {
+		// json documents with tabs are not valid yaml documents
+		doc = &Document{}
+		if json.Unmarshal(spec, doc) != nil {
+			return nil, err
+		}
+	}
+	if err := doc.validate(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// MajorVersion - returns the major version of the specification, 2 or 3
+func (d *Document) MajorVersion() int {
+	switch {
+	case strings.HasPrefix(d.AsyncAPI, "2."):
+		return 2
+	case strings.HasPrefix(d.AsyncAPI, "3."):
+		return 3
+	}
+	return 0
+}
+
+func asyncAPIParseError(version string, msg string, args ...interface{}) error {
+	return fmt.Errorf("invalid asyncapi %s specification. %s", version, fmt.Sprintf(msg, args...))
+}
+
+func (d *Document) validate() error {
+	if d.MajorVersion() == 0 {
+		return asyncAPIParseError("2.x or 3.x", "'asyncapi' key is invalid.")
+	}
+	version := d.AsyncAPI
+	if d.Info == nil {
+		return asyncAPIParseError(version, "'info' key not found.")
+	}
+	if d.Info.Title == "" {
+		return asyncAPIParseError(version, "'info.title' key not found.")
+	}
+	if d.Info.Version == "" {
+		return asyncAPIParseError(version, "'info.version' key not found.")
+	}
+	if d.MajorVersion() == 2 && d.Channels == nil {
+		return asyncAPIParseError(version, "'channels' key not found.")
+	}
+
+	for _, name := range sortedKeys(d.Servers) {
+		if err := d.validateServer(name, d.Servers[name]); err != nil {
+			return err
+		}
+	}
+	for _, channelName := range sortedKeys(d.Channels) {
+		channel := d.Channels[channelName]
+		if channel == nil {
+			continue
+		}
+		for _, op := range []*Operation{channel.Publish, channel.Subscribe} {
+			if op != nil {
+				if err := d.validateSecurity(op.Security, "channels."+channelName); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, name := range sortedKeys(d.Operations) {
+		if err := d.validateOperation(name, d.Operations[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Document) validateServer(name string, server *Server) error {
+	key := "servers." + name
+	if server == nil {
+		return asyncAPIParseError(d.AsyncAPI, "'%s' is empty.", key)
+	}
+	if server.Protocol == "" {
+		return asyncAPIParseError(d.AsyncAPI, "'%s.protocol' key not found.", key)
+	}
+
+	address := server.URL
+	if d.MajorVersion() == 3 {
+		if server.Host == "" {
+			return asyncAPIParseError(d.AsyncAPI, "'%s.host' key not found.", key)
+		}
+		address = server.Host + server.Pathname
+	} else if server.URL == "" {
+		return asyncAPIParseError(d.AsyncAPI, "'%s.url' key not found.", key)
+	}
+	for _, match := range variableRegEx.FindAllStringSubmatch(address, -1) {
+		variable, ok := server.Variables[match[1]]
+		if !ok || variable == nil {
+			return asyncAPIParseError(d.AsyncAPI, "'%s.variables.%s' key not found.", key, match[1])
+		}
+		if variable.Default == "" && len(variable.Enum) == 0 {
+			return asyncAPIParseError(d.AsyncAPI, "'%s.variables.%s' has no default value.", key, match[1])
+		}
+	}
+	return d.validateSecurity(server.Security, key)
+}
+
+func (d *Document) validateOperation(name string, op *Operation) error {
+	key := "operations." + name
+	if op == nil {
+		return asyncAPIParseError(d.AsyncAPI, "'%s' is empty.", key)
+	}
+	if op.Action != ActionSend && op.Action != ActionReceive {
+		return asyncAPIParseError(d.AsyncAPI, "'%s.action' must be send or receive.", key)
+	}
+	if op.Channel == nil || op.Channel.Ref == "" {
+		return asyncAPIParseError(d.AsyncAPI, "'%s.channel' key not found.", key)
+	}
+	if _, ok := d.Channels[refName(op.Channel.Ref)]; !ok {
+		return asyncAPIParseError(d.AsyncAPI, "'%s.channel' references an unknown channel %s.", key, op.Channel.Ref)
+	}
+	return d.validateSecurity(op.Security, key)
+}
+
+// validateSecurity - checks that the security requirements reference schemes of the components
+func (d *Document) validateSecurity(requirements []map[string]interface{}, key string) error {
+	for _, name := range securitySchemeNames(requirements) {
+		if d.GetSecurityScheme(name) == nil {
+			return asyncAPIParseError(d.AsyncAPI, "'%s.security' references an unknown security scheme %s.", key, name)
+		}
+	}
+	return nil
+}
+
+// GetSecurityScheme - returns the security scheme of the components with the name, nil when not found
+func (d *Document) GetSecurityScheme(name string) *SecurityScheme {
+	if d.Components == nil {
+		return nil
+	}
+	return d.Components.SecuritySchemes[name]
+}
+
+// securitySchemeNames - returns the names of the schemes of the requirements, 2.x requirements are keyed by the
+// scheme name and 3.x requirements reference the scheme, inline 3.x schemes have no name
+func securitySchemeNames(requirements []map[string]interface{}) []string {
+	names := make([]string, 0)
+	for _, requirement := range requirements {
+		if ref, ok := requirement["$ref"].(string); ok {
+			names = append(names, refName(ref))
+			continue
+		}
+		if _, ok := requirement["type"]; ok {
+			continue
+		}
+		names = append(names, sortedKeys(requirement)...)
+	}
+	return names
+}
+
+// refName - returns the last segment of a local reference, e.g. userSignUp for #/components/messages/userSignUp
+func refName(ref string) string {
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	// json pointers escape / and ~ in the names
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := make([]string, 0)
+	switch typed := m.(type) {
+	case map[string]*Server:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	case map[string]*Channel:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	case map[string]*Operation:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	case map[string]interface{}:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+package asyncapi
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAsyncAPI2(t *testing.T) {
+	data, err := ioutil.ReadFile("./testdata/streetlights-kafka.yaml")
+	assert.Nil(t, err)
+
+	doc, err := Parse(data)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, doc.MajorVersion())
+	assert.Equal(t, "Streetlights Kafka API", doc.Info.Title)
+	assert.Equal(t, "confluent", doc.Servers["production"].Bindings.Kafka.SchemaRegistryVendor)
+	assert.Equal(t, 60, doc.Servers["test"].Bindings.MQTT.KeepAlive)
+	assert.Equal(t, "scramSha256", doc.GetSecurityScheme("saslScram").Type)
+
+	addresses, err := doc.GetAddresses()
+	assert.Nil(t, err)
+	assert.Equal(t, []Address{
+		{Server: "production", Protocol: "kafka-secure", ProtocolVersion: "2.8.0", Host: "broker1.example.com", Port: 9093, Security: []string{"saslScram"}},
+		{Server: "production", Protocol: "kafka-secure", ProtocolVersion: "2.8.0", Host: "broker2.example.com", Port: 9093, Security: []string{"saslScram"}},
+		{Server: "test", Protocol: "mqtt", Host: "test.mosquitto.org", Port: 1883, Path: "/lights", Security: []string{}},
+	}, addresses)
+
+	operations := doc.GetOperations()
+	assert.Len(t, operations, 2)
+	assert.Equal(t, "smartylighting.streetlights.1.0.action.turn.on", operations[0].Channel)
+	assert.Equal(t, ActionPublish, operations[0].Action)
+	assert.Equal(t, []string{"turnOnOff", "dimLight"}, operations[0].Messages)
+	assert.Equal(t, ActionSubscribe, operations[1].Action)
+	assert.Equal(t, "receiveLightMeasurement", operations[1].OperationID)
+	assert.Equal(t, []string{"lightMeasured"}, operations[1].Messages)
+	assert.Equal(t, []string{"saslScram"}, operations[1].Security)
+	assert.Equal(t, "lighting-measured", operations[1].ChannelBindings.Kafka.Topic)
+	assert.Equal(t, 1, operations[1].Bindings.MQTT.QoS)
+}
+
+func TestParseAsyncAPI3(t *testing.T) {
+	data, err := ioutil.ReadFile("./testdata/chat-websocket.json")
+	assert.Nil(t, err)
+
+	doc, err := Parse(data)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, doc.MajorVersion())
+	assert.Equal(t, "https://auth.example.com/token", doc.GetSecurityScheme("oauth").Flows.ClientCredentials.TokenURL)
+
+	addresses, err := doc.GetAddresses()
+	assert.Nil(t, err)
+	assert.Equal(t, []Address{
+		{Server: "internal", Protocol: "amqp", Host: "rabbit.internal", Port: 15672, Security: []string{}},
+		{Server: "public", Protocol: "wss", Host: "chat.example.com", Port: 443, Path: "/v1", Security: []string{"oauth"}},
+	}, addresses)
+
+	operations := doc.GetOperations()
+	assert.Len(t, operations, 2)
+	assert.Equal(t, OperationInfo{
+		Channel:     "rooms/{roomId}",
+		Action:      ActionReceive,
+		OperationID: "receiveMessages",
+		Messages:    []string{"chatMessage", "joined"},
+		Security:    []string{},
+		ChannelBindings: &ChannelBindings{
+			WS:   &WebSocketChannelBinding{Method: "GET"},
+			AMQP: &AMQPChannelBinding{Is: "routingKey", Exchange: &AMQPExchange{Name: "rooms", Type: "topic", Durable: true}},
+		},
+	}, operations[0])
+	assert.Equal(t, ActionSend, operations[1].Action)
+	assert.Equal(t, []string{"chatMessage"}, operations[1].Messages)
+	assert.Equal(t, []string{"oauth"}, operations[1].Security)
+	assert.Equal(t, 2, operations[1].Bindings.AMQP.DeliveryMode)
+}
+
+func TestParseAsyncAPIValidation(t *testing.T) {
+	testCases := map[string]struct {
+		spec string
+		err  string
+	}{
+		"not a document": {
+			spec: "not an asyncapi document",
+			err:  "cannot unmarshal",
+		},
+		"invalid version": {
+			spec: `{"asyncapi": "1.2.0", "info": {"title": "test", "version": "1"}, "channels": {}}`,
+			err:  "'asyncapi' key is invalid.",
+		},
+		"no title": {
+			spec: `{"asyncapi": "2.0.0", "info": {"version": "1"}, "channels": {}}`,
+			err:  "'info.title' key not found.",
+		},
+		"no channels": {
+			spec: `{"asyncapi": "2.0.0", "info": {"title": "test", "version": "1"}}`,
+			err:  "'channels' key not found.",
+		},
+		"no server url": {
+			spec: `{"asyncapi": "2.0.0", "info": {"title": "test", "version": "1"}, "channels": {}, "servers": {"prod": {"protocol": "mqtt"}}}`,
+			err:  "'servers.prod.url' key not found.",
+		},
+		"undefined variable": {
+			spec: `{"asyncapi": "2.0.0", "info": {"title": "test", "version": "1"}, "channels": {}, "servers": {"prod": {"url": "{host}:1883", "protocol": "mqtt"}}}`,
+			err:  "'servers.prod.variables.host' key not found.",
+		},
+		"unknown security scheme": {
+			spec: `{"asyncapi": "2.0.0", "info": {"title": "test", "version": "1"}, "channels": {}, "servers": {"prod": {"url": "broker:1883", "protocol": "mqtt", "security": [{"apiKey": []}]}}}`,
+			err:  "unknown security scheme apiKey",
+		},
+		"invalid action": {
+			spec: `{"asyncapi": "3.0.0", "info": {"title": "test", "version": "1"}, "channels": {"room": {}}, "operations": {"op": {"action": "publish", "channel": {"$ref": "#/channels/room"}}}}`,
+			err:  "'operations.op.action' must be send or receive.",
+		},
+		"unknown channel": {
+			spec: `{"asyncapi": "3.0.0", "info": {"title": "test", "version": "1"}, "operations": {"op": {"action": "send", "channel": {"$ref": "#/channels/room"}}}}`,
+			err:  "unknown channel #/channels/room",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.spec))
+			assert.NotNil(t, err)
+			if err != nil {
+				assert.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
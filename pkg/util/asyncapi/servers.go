@@ -0,0 +1,120 @@
+package asyncapi
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// defaultPorts - the default ports of the protocols, used when the server does not set one
+var defaultPorts = map[string]int{
+	"amqp":         5672,
+	"amqps":        5671,
+	"http":         80,
+	"https":        443,
+	"ibmmq":        1414,
+	"jms":          61616,
+	"kafka":        9092,
+	"kafka-secure": 9093,
+	"mqtt":         1883,
+	"secure-mqtt":  8883,
+	"mqtts":        8883,
+	"nats":         4222,
+	"redis":        6379,
+	"stomp":        61613,
+	"stomps":       61614,
+	"ws":           80,
+	"wss":          443,
+}
+
+// Address - a network address of a server, kafka servers can list several brokers
+type Address struct {
+	Server          string
+	Protocol        string
+	ProtocolVersion string
+	Host            string
+	Port            int
+	Path            string
+	Security        []string // the names of the security schemes of the server
+}
+
+// GetAddresses - returns the addresses of all the servers, ordered by server name
+func (d *Document) GetAddresses() ([]Address, error) {
+	addresses := make([]Address, 0)
+	for _, name := range sortedKeys(d.Servers) {
+		serverAddresses, err := d.Servers[name].addresses(name)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, serverAddresses...)
+	}
+	return addresses, nil
+}
+
+func (s *Server) addresses(name string) ([]Address, error) {
+	protocol := strings.ToLower(s.Protocol)
+	location := s.URL
+	if s.Host != "" {
+		location = s.Host + s.Pathname
+	}
+	location = s.substituteVariables(location)
+
+	// kafka bootstrap servers are a comma separated list of brokers
+	brokers := []string{location}
+	if strings.HasPrefix(protocol, "kafka") {
+		brokers = strings.Split(location, ",")
+	}
+
+	addresses := make([]Address, 0, len(brokers))
+	for _, broker := range brokers {
+		broker = strings.TrimSpace(broker)
+		if !strings.Contains(broker, "://") {
+			broker = protocol + "://" + broker
+		}
+		serverURL, err := url.Parse(broker)
+		if err != nil {
+			return nil, fmt.Errorf("invalid url of the server %s: %s", name, err)
+		}
+		if serverURL.Hostname() == "" {
+			return nil, fmt.Errorf("invalid url of the server %s: no host", name)
+		}
+
+		port := defaultPorts[protocol]
+		if serverURL.Port() != "" {
+			if port, err = strconv.Atoi(serverURL.Port()); err != nil {
+				return nil, fmt.Errorf("invalid port of the server %s: %s", name, serverURL.Port())
+			}
+		} else if port == 0 {
+			// the protocol of the url, e.g. wss://, can differ from the protocol of the server
+			port, _ = net.LookupPort("tcp", serverURL.Scheme)
+		}
+
+		addresses = append(addresses, Address{
+			Server:          name,
+			Protocol:        protocol,
+			ProtocolVersion: s.ProtocolVersion,
+			Host:            serverURL.Hostname(),
+			Port:            port,
+			Path:            serverURL.Path,
+			Security:        securitySchemeNames(s.Security),
+		})
+	}
+	return addresses, nil
+}
+
+// substituteVariables - replaces the variables with their default value, or the first value of their enum
+func (s *Server) substituteVariables(location string) string {
+	for name, variable := range s.Variables {
+		if variable == nil {
+			continue
+		}
+		value := variable.Default
+		if value == "" && len(variable.Enum) > 0 {
+			value = variable.Enum[0]
+		}
+		location = strings.ReplaceAll(location, "{"+name+"}", value)
+	}
+	return location
+}
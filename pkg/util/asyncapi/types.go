@@ -0,0 +1,199 @@
+package asyncapi
+
+// Document - an AsyncAPI 2.x or 3.x document, the fields of both versions are kept in the same model
+type Document struct {
+	AsyncAPI   string                `yaml:"asyncapi" json:"asyncapi"`
+	ID         string                `yaml:"id" json:"id"`
+	Info       *Info                 `yaml:"info" json:"info"`
+	Servers    map[string]*Server    `yaml:"servers" json:"servers"`
+	Channels   map[string]*Channel   `yaml:"channels" json:"channels"`
+	Operations map[string]*Operation `yaml:"operations" json:"operations"` // 3.x only
+	Components *Components           `yaml:"components" json:"components"`
+}
+
+// Info - the title, version and description of the API
+type Info struct {
+	Title       string `yaml:"title" json:"title"`
+	Version     string `yaml:"version" json:"version"`
+	Description string `yaml:"description" json:"description"`
+}
+
+// Server - a message broker, 2.x servers have an url, 3.x servers a host and a pathname
+type Server struct {
+	URL             string                     `yaml:"url" json:"url"`
+	Host            string                     `yaml:"host" json:"host"`
+	Pathname        string                     `yaml:"pathname" json:"pathname"`
+	Protocol        string                     `yaml:"protocol" json:"protocol"`
+	ProtocolVersion string                     `yaml:"protocolVersion" json:"protocolVersion"`
+	Description     string                     `yaml:"description" json:"description"`
+	Variables       map[string]*ServerVariable `yaml:"variables" json:"variables"`
+	Security        []map[string]interface{}   `yaml:"security" json:"security"`
+	Bindings        *ServerBindings            `yaml:"bindings" json:"bindings"`
+}
+
+// ServerVariable - a variable of the url, or host and pathname, of a server
+type ServerVariable struct {
+	Default     string   `yaml:"default" json:"default"`
+	Enum        []string `yaml:"enum" json:"enum"`
+	Description string   `yaml:"description" json:"description"`
+}
+
+// Channel - a channel of the API, the address of 3.x channels is their topic, 2.x channels are keyed by it
+type Channel struct {
+	Address     string                 `yaml:"address" json:"address"`
+	Description string                 `yaml:"description" json:"description"`
+	Servers     []interface{}          `yaml:"servers" json:"servers"`
+	Subscribe   *Operation             `yaml:"subscribe" json:"subscribe"` // 2.x only
+	Publish     *Operation             `yaml:"publish" json:"publish"`     // 2.x only
+	Messages    map[string]*Message    `yaml:"messages" json:"messages"`   // 3.x only
+	Parameters  map[string]interface{} `yaml:"parameters" json:"parameters"`
+	Bindings    *ChannelBindings       `yaml:"bindings" json:"bindings"`
+}
+
+// Operation - an operation of a 2.x channel, or a 3.x operation with its action and channel reference
+type Operation struct {
+	Action      string                   `yaml:"action" json:"action"`   // 3.x only
+	Channel     *Reference               `yaml:"channel" json:"channel"` // 3.x only
+	OperationID string                   `yaml:"operationId" json:"operationId"`
+	Summary     string                   `yaml:"summary" json:"summary"`
+	Description string                   `yaml:"description" json:"description"`
+	Message     *Message                 `yaml:"message" json:"message"`   // 2.x only
+	Messages    []*Message               `yaml:"messages" json:"messages"` // 3.x only
+	Security    []map[string]interface{} `yaml:"security" json:"security"`
+	Bindings    *OperationBindings       `yaml:"bindings" json:"bindings"`
+}
+
+// Reference - a $ref to another object of the document
+type Reference struct {
+	Ref string `yaml:"$ref" json:"$ref"`
+}
+
+// Message - a message, or a reference to a message, of an operation
+type Message struct {
+	Ref         string     `yaml:"$ref" json:"$ref"`
+	Name        string     `yaml:"name" json:"name"`
+	MessageID   string     `yaml:"messageId" json:"messageId"`
+	Title       string     `yaml:"title" json:"title"`
+	Summary     string     `yaml:"summary" json:"summary"`
+	ContentType string     `yaml:"contentType" json:"contentType"`
+	OneOf       []*Message `yaml:"oneOf" json:"oneOf"`
+}
+
+// Components - the reusable objects of the document
+type Components struct {
+	Messages        map[string]*Message        `yaml:"messages" json:"messages"`
+	SecuritySchemes map[string]*SecurityScheme `yaml:"securitySchemes" json:"securitySchemes"`
+}
+
+// SecurityScheme - a security scheme of the servers and operations
+type SecurityScheme struct {
+	Type             string      `yaml:"type" json:"type"`
+	Description      string      `yaml:"description" json:"description"`
+	Name             string      `yaml:"name" json:"name"`
+	In               string      `yaml:"in" json:"in"`
+	Scheme           string      `yaml:"scheme" json:"scheme"`
+	BearerFormat     string      `yaml:"bearerFormat" json:"bearerFormat"`
+	OpenIDConnectURL string      `yaml:"openIdConnectUrl" json:"openIdConnectUrl"`
+	Flows            *OAuthFlows `yaml:"flows" json:"flows"`
+}
+
+// OAuthFlows - the OAuth flows of an oauth2 security scheme
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `yaml:"implicit" json:"implicit"`
+	Password          *OAuthFlow `yaml:"password" json:"password"`
+	ClientCredentials *OAuthFlow `yaml:"clientCredentials" json:"clientCredentials"`
+	AuthorizationCode *OAuthFlow `yaml:"authorizationCode" json:"authorizationCode"`
+}
+
+// OAuthFlow - an OAuth flow, 2.x flows have scopes and 3.x flows available scopes
+type OAuthFlow struct {
+	AuthorizationURL string            `yaml:"authorizationUrl" json:"authorizationUrl"`
+	TokenURL         string            `yaml:"tokenUrl" json:"tokenUrl"`
+	RefreshURL       string            `yaml:"refreshUrl" json:"refreshUrl"`
+	Scopes           map[string]string `yaml:"scopes" json:"scopes"`
+	AvailableScopes  map[string]string `yaml:"availableScopes" json:"availableScopes"`
+}
+
+// ServerBindings - the protocol specific settings of a server
+type ServerBindings struct {
+	Kafka *KafkaServerBinding `yaml:"kafka" json:"kafka"`
+	MQTT  *MQTTServerBinding  `yaml:"mqtt" json:"mqtt"`
+}
+
+// KafkaServerBinding - the schema registry of a kafka cluster
+type KafkaServerBinding struct {
+	SchemaRegistryURL    string `yaml:"schemaRegistryUrl" json:"schemaRegistryUrl"`
+	SchemaRegistryVendor string `yaml:"schemaRegistryVendor" json:"schemaRegistryVendor"`
+}
+
+// MQTTServerBinding - the connection settings of an MQTT broker
+type MQTTServerBinding struct {
+	ClientID     string `yaml:"clientId" json:"clientId"`
+	CleanSession bool   `yaml:"cleanSession" json:"cleanSession"`
+	KeepAlive    int    `yaml:"keepAlive" json:"keepAlive"`
+}
+
+// ChannelBindings - the protocol specific settings of a channel
+type ChannelBindings struct {
+	Kafka *KafkaChannelBinding     `yaml:"kafka" json:"kafka"`
+	AMQP  *AMQPChannelBinding      `yaml:"amqp" json:"amqp"`
+	WS    *WebSocketChannelBinding `yaml:"ws" json:"ws"`
+}
+
+// KafkaChannelBinding - the topic of a channel
+type KafkaChannelBinding struct {
+	Topic      string `yaml:"topic" json:"topic"`
+	Partitions int    `yaml:"partitions" json:"partitions"`
+	Replicas   int    `yaml:"replicas" json:"replicas"`
+}
+
+// AMQPChannelBinding - the exchange, or queue, of a channel
+type AMQPChannelBinding struct {
+	Is       string        `yaml:"is" json:"is"` // routingKey or queue
+	Exchange *AMQPExchange `yaml:"exchange" json:"exchange"`
+	Queue    *AMQPQueue    `yaml:"queue" json:"queue"`
+}
+
+// AMQPExchange - an AMQP exchange
+type AMQPExchange struct {
+	Name       string `yaml:"name" json:"name"`
+	Type       string `yaml:"type" json:"type"`
+	Durable    bool   `yaml:"durable" json:"durable"`
+	AutoDelete bool   `yaml:"autoDelete" json:"autoDelete"`
+	VHost      string `yaml:"vhost" json:"vhost"`
+}
+
+// AMQPQueue - an AMQP queue
+type AMQPQueue struct {
+	Name       string `yaml:"name" json:"name"`
+	Durable    bool   `yaml:"durable" json:"durable"`
+	Exclusive  bool   `yaml:"exclusive" json:"exclusive"`
+	AutoDelete bool   `yaml:"autoDelete" json:"autoDelete"`
+	VHost      string `yaml:"vhost" json:"vhost"`
+}
+
+// WebSocketChannelBinding - the HTTP method of the WebSocket handshake
+type WebSocketChannelBinding struct {
+	Method string `yaml:"method" json:"method"`
+}
+
+// OperationBindings - the protocol specific settings of an operation
+type OperationBindings struct {
+	MQTT *MQTTOperationBinding `yaml:"mqtt" json:"mqtt"`
+	AMQP *AMQPOperationBinding `yaml:"amqp" json:"amqp"`
+}
+
+// MQTTOperationBinding - the quality of service of the messages of an operation
+type MQTTOperationBinding struct {
+	QoS    int  `yaml:"qos" json:"qos"`
+	Retain bool `yaml:"retain" json:"retain"`
+}
+
+// AMQPOperationBinding - the delivery settings of the messages of an operation
+type AMQPOperationBinding struct {
+	Expiration   int      `yaml:"expiration" json:"expiration"`
+	DeliveryMode int      `yaml:"deliveryMode" json:"deliveryMode"`
+	Mandatory    bool     `yaml:"mandatory" json:"mandatory"`
+	Priority     int      `yaml:"priority" json:"priority"`
+	CC           []string `yaml:"cc" json:"cc"`
+}
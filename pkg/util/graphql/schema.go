@@ -0,0 +1,169 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Operation types of a GraphQL schema
+const (
+	Query        = "query"
+	Mutation     = "mutation"
+	Subscription = "subscription"
+)
+
+// defaultRootTypes - the names of the root types when the schema does not define them
+var defaultRootTypes = map[string]string{
+	Query:        "Query",
+	Mutation:     "Mutation",
+	Subscription: "Subscription",
+}
+
+// Schema - the operations of a GraphQL schema, parsed from its SDL or from the result of an introspection query
+type Schema struct {
+	Operations []Operation
+}
+
+// Operation - a field of the query, mutation or subscription root type
+type Operation struct {
+	Type        string // query, mutation or subscription
+	Name        string
+	Description string
+	Arguments   []Argument
+	ReturnType  string
+}
+
+// Argument - an argument of an operation
+type Argument struct {
+	Name string
+	Type string
+}
+
+// GetOperations - returns the operations of the type, query, mutation or subscription
+func (s *Schema) GetOperations(operationType string) []Operation {
+	operations := make([]Operation, 0)
+	for _, op := range s.Operations {
+		if op.Type == operationType {
+			operations = append(operations, op)
+		}
+	}
+	return operations
+}
+
+// introspection - the result of an introspection query, with or without its data envelope
+type introspection struct {
+	Data *struct {
+		Schema *introspectionSchema `json:"__schema"`
+	} `json:"data"`
+	Schema *introspectionSchema `json:"__schema"`
+}
+
+type introspectionSchema struct {
+	QueryType        *introspectionName  `json:"queryType"`
+	MutationType     *introspectionName  `json:"mutationType"`
+	SubscriptionType *introspectionName  `json:"subscriptionType"`
+	Types            []introspectionType `json:"types"`
+}
+
+type introspectionName struct {
+	Name string `json:"name"`
+}
+
+type introspectionType struct {
+	Kind   string               `json:"kind"`
+	Name   string               `json:"name"`
+	Fields []introspectionField `json:"fields"`
+}
+
+type introspectionField struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Args        []introspectionField  `json:"args"`
+	Type        *introspectionTypeRef `json:"type"`
+}
+
+type introspectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   string                `json:"name"`
+	OfType *introspectionTypeRef `json:"ofType"`
+}
+
+// String - returns the type reference in the SDL notation, e.g. [Book!]!
+func (t *introspectionTypeRef) String() string {
+	if t == nil {
+		return ""
+	}
+	switch t.Kind {
+	case "NON_NULL":
+		return t.OfType.String() + "!"
+	case "LIST":
+		return "[" + t.OfType.String() + "]"
+	}
+	return t.Name
+}
+
+// IsIntrospection - returns true when the document is the JSON result of an introspection query
+func IsIntrospection(data []byte) bool {
+	result := introspection{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return false
+	}
+	return result.schema() != nil
+}
+
+func (i introspection) schema() *introspectionSchema {
+	if i.Schema != nil {
+		return i.Schema
+	}
+	if i.Data != nil {
+		return i.Data.Schema
+	}
+	return nil
+}
+
+// ParseIntrospection - parses the JSON result of an introspection query
+func ParseIntrospection(data []byte) (*Schema, error) {
+	result := introspection{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	schema := result.schema()
+	if schema == nil {
+		return nil, errors.New("invalid GraphQL introspection result, __schema not found")
+	}
+
+	rootTypes := map[string]*introspectionName{
+		Query:        schema.QueryType,
+		Mutation:     schema.MutationType,
+		Subscription: schema.SubscriptionType,
+	}
+	types := make(map[string]introspectionType)
+	for _, t := range schema.Types {
+		types[t.Name] = t
+	}
+
+	parsed := &Schema{Operations: make([]Operation, 0)}
+	for _, operationType := range []string{Query, Mutation, Subscription} {
+		rootType := rootTypes[operationType]
+		if rootType == nil {
+			continue
+		}
+		for _, field := range types[rootType.Name].Fields {
+			op := Operation{
+				Type:        operationType,
+				Name:        field.Name,
+				Description: field.Description,
+				Arguments:   make([]Argument, 0, len(field.Args)),
+				ReturnType:  field.Type.String(),
+			}
+			for _, arg := range field.Args {
+				op.Arguments = append(op.Arguments, Argument{Name: arg.Name, Type: arg.Type.String()})
+			}
+			parsed.Operations = append(parsed.Operations, op)
+		}
+	}
+	if len(parsed.Operations) == 0 {
+		return nil, errors.New("invalid GraphQL introspection result, no operation found")
+	}
+	return parsed, nil
+}
@@ -0,0 +1,86 @@
+package graphql
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSDL(t *testing.T) {
+	data, err := ioutil.ReadFile("./testdata/library.graphql")
+	assert.Nil(t, err)
+
+	schema, err := ParseSDL(data)
+	assert.Nil(t, err)
+	assert.Equal(t, []Operation{
+		{
+			Type:        Query,
+			Name:        "book",
+			Description: "Returns a book by its identifier",
+			Arguments:   []Argument{{Name: "id", Type: "ID!"}},
+			ReturnType:  "Book",
+		},
+		{
+			Type:       Query,
+			Name:       "books",
+			Arguments:  []Argument{{Name: "genre", Type: "Genre"}, {Name: "first", Type: "Int"}},
+			ReturnType: "[Book!]!",
+		},
+		{
+			Type:       Query,
+			Name:       "search",
+			Arguments:  []Argument{{Name: "text", Type: "String!"}},
+			ReturnType: "[SearchResult]",
+		},
+		{
+			Type:       Query,
+			Name:       "author",
+			Arguments:  []Argument{{Name: "name", Type: "String!"}},
+			ReturnType: "Author",
+		},
+	}, schema.GetOperations(Query))
+	assert.Len(t, schema.GetOperations(Mutation), 1)
+	assert.Equal(t, "addBook", schema.GetOperations(Mutation)[0].Name)
+	assert.Len(t, schema.GetOperations(Subscription), 1)
+	assert.Equal(t, "bookAdded", schema.GetOperations(Subscription)[0].Name)
+
+	// documents without a root type, or that are not GraphQL
+	_, err = ParseSDL([]byte("type Book { id: ID! }"))
+	assert.NotNil(t, err)
+	_, err = ParseSDL([]byte(`{"openapi": "3.0.1"}`))
+	assert.NotNil(t, err)
+	_, err = ParseSDL([]byte("type Query { book(id: ID!: Book }"))
+	assert.NotNil(t, err)
+	_, err = ParseSDL([]byte(`type Query { """unterminated`))
+	assert.NotNil(t, err)
+}
+
+func TestParseIntrospection(t *testing.T) {
+	data, err := ioutil.ReadFile("./testdata/library.json")
+	assert.Nil(t, err)
+	assert.True(t, IsIntrospection(data))
+	assert.False(t, IsIntrospection([]byte(`{"openapi": "3.0.1"}`)))
+	assert.False(t, IsIntrospection([]byte("type Query { id: ID }")))
+
+	schema, err := ParseIntrospection(data)
+	assert.Nil(t, err)
+	assert.Equal(t, []Operation{
+		{
+			Type:        Query,
+			Name:        "book",
+			Description: "Returns a book by its identifier",
+			Arguments:   []Argument{{Name: "id", Type: "ID!"}},
+			ReturnType:  "Book",
+		},
+		{
+			Type:       Query,
+			Name:       "books",
+			Arguments:  []Argument{},
+			ReturnType: "[Book!]!",
+		},
+	}, schema.Operations)
+
+	_, err = ParseIntrospection([]byte(`{"data": {"__schema": {"types": []}}}`))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,443 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenName tokenKind = iota
+	tokenPunctuator
+	tokenString
+	tokenNumber
+	tokenEOF
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+}
+
+// definitionKeywords - the keywords starting the definitions of a document
+var definitionKeywords = map[string]bool{
+	"schema":    true,
+	"extend":    true,
+	"type":      true,
+	"interface": true,
+	"input":     true,
+	"enum":      true,
+	"union":     true,
+	"scalar":    true,
+	"directive": true,
+}
+
+// lex - splits the SDL document into tokens, the comments and commas are ignored
+func lex(sdl string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(sdl)
+	line := 1
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r) || r == ',' || r == '\uFEFF':
+			i++
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '"':
+			value, next, lines, err := lexString(runes, i)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", line, err)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, line: line})
+			line += lines
+			i = next
+		case r == '.':
+			if i+2 >= len(runes) || runes[i+1] != '.' || runes[i+2] != '.' {
+				return nil, fmt.Errorf("line %d: unexpected character '.'", line)
+			}
+			tokens = append(tokens, token{kind: tokenPunctuator, value: "...", line: line})
+			i += 3
+		case strings.ContainsRune("!$&()[]{}:=@|", r):
+			tokens = append(tokens, token{kind: tokenPunctuator, value: string(r), line: line})
+			i++
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenName, value: string(runes[start:i]), line: line})
+		case r == '-' || unicode.IsDigit(r):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || strings.ContainsRune(".eE+-", runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), line: line})
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character '%c'", line, r)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, line: line}), nil
+}
+
+// lexString - returns the value of the string, or block string, starting at i, the index after it and its new lines
+func lexString(runes []rune, i int) (string, int, int, error) {
+	if i+2 < len(runes) && runes[i+1] == '"' && runes[i+2] == '"' {
+		for j := i + 3; j+2 < len(runes); j++ {
+			if runes[j] == '"' && runes[j+1] == '"' && runes[j+2] == '"' && runes[j-1] != '\\' {
+				value := string(runes[i+3 : j])
+				return strings.TrimSpace(value), j + 3, strings.Count(value, "\n"), nil
+			}
+		}
+		return "", 0, 0, errors.New("unterminated block string")
+	}
+
+	builder := strings.Builder{}
+	for j := i + 1; j < len(runes); j++ {
+		switch runes[j] {
+		case '\\':
+			j++
+			if j < len(runes) {
+				builder.WriteRune(runes[j])
+			}
+		case '"':
+			return builder.String(), j + 1, 0, nil
+		case '\n':
+			return "", 0, 0, errors.New("unterminated string")
+		default:
+			builder.WriteRune(runes[j])
+		}
+	}
+	return "", 0, 0, errors.New("unterminated string")
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	// the fields of the object types, by type name, in the order of the document
+	fields    map[string][]Operation
+	rootTypes map[string]string
+}
+
+// ParseSDL - parses a GraphQL schema definition document, returns the fields of its root types as operations
+func ParseSDL(sdl []byte) (*Schema, error) {
+	tokens, err := lex(string(sdl))
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{
+		tokens:    tokens,
+		fields:    make(map[string][]Operation),
+		rootTypes: make(map[string]string),
+	}
+	if err := p.parseDocument(); err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{Operations: make([]Operation, 0)}
+	for _, operationType := range []string{Query, Mutation, Subscription} {
+		rootType, ok := p.rootTypes[operationType]
+		if !ok {
+			rootType = defaultRootTypes[operationType]
+		}
+		for _, op := range p.fields[rootType] {
+			op.Type = operationType
+			schema.Operations = append(schema.Operations, op)
+		}
+	}
+	if len(schema.Operations) == 0 {
+		return nil, errors.New("invalid GraphQL schema, no query, mutation or subscription found")
+	}
+	return schema, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) is(kind tokenKind, value string) bool {
+	t := p.peek()
+	return t.kind == kind && t.value == value
+}
+
+func (p *parser) expect(kind tokenKind, value string) (token, error) {
+	t := p.next()
+	if t.kind != kind || (value != "" && t.value != value) {
+		expected := value
+		if expected == "" {
+			expected = "a name"
+		}
+		return t, fmt.Errorf("line %d: expected %s, found '%s'", t.line, expected, t.value)
+	}
+	return t, nil
+}
+
+func (p *parser) parseDocument() error {
+	for p.peek().kind != tokenEOF {
+		// descriptions of the definitions
+		if p.peek().kind == tokenString {
+			p.next()
+			continue
+		}
+
+		t, err := p.expect(tokenName, "")
+		if err != nil {
+			return err
+		}
+		if t.value == "extend" {
+			t, err = p.expect(tokenName, "")
+			if err != nil {
+				return err
+			}
+		}
+
+		switch t.value {
+		case "schema":
+			err = p.parseSchemaDefinition()
+		case "type":
+			err = p.parseObjectType()
+		case "interface", "input", "enum", "union", "scalar", "directive":
+			err = p.skipDefinition()
+		default:
+			err = fmt.Errorf("line %d: unexpected '%s'", t.line, t.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseSchemaDefinition() error {
+	if err := p.skipDirectives(); err != nil {
+		return err
+	}
+	if _, err := p.expect(tokenPunctuator, "{"); err != nil {
+		return err
+	}
+	for !p.is(tokenPunctuator, "}") {
+		operationType, err := p.expect(tokenName, "")
+		if err != nil {
+			return err
+		}
+		if _, err := p.expect(tokenPunctuator, ":"); err != nil {
+			return err
+		}
+		typeName, err := p.expect(tokenName, "")
+		if err != nil {
+			return err
+		}
+		p.rootTypes[operationType.value] = typeName.value
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) parseObjectType() error {
+	name, err := p.expect(tokenName, "")
+	if err != nil {
+		return err
+	}
+	// implements A & B
+	if p.is(tokenName, "implements") {
+		p.next()
+		for p.peek().kind == tokenName || p.is(tokenPunctuator, "&") {
+			if p.peek().kind == tokenName && definitionKeywords[p.peek().value] {
+				break
+			}
+			p.next()
+		}
+	}
+	if err := p.skipDirectives(); err != nil {
+		return err
+	}
+	if !p.is(tokenPunctuator, "{") {
+		// a type without fields
+		return nil
+	}
+	p.next()
+
+	for !p.is(tokenPunctuator, "}") {
+		field, err := p.parseField()
+		if err != nil {
+			return err
+		}
+		p.fields[name.value] = append(p.fields[name.value], field)
+	}
+	p.next()
+	return nil
+}
+
+// parseField - parses a field definition: "description" name(arguments): Type @directives
+func (p *parser) parseField() (Operation, error) {
+	field := Operation{Arguments: make([]Argument, 0)}
+	if p.peek().kind == tokenString {
+		field.Description = p.next().value
+	}
+	name, err := p.expect(tokenName, "")
+	if err != nil {
+		return field, err
+	}
+	field.Name = name.value
+
+	if p.is(tokenPunctuator, "(") {
+		p.next()
+		for !p.is(tokenPunctuator, ")") {
+			if p.peek().kind == tokenString {
+				p.next()
+			}
+			argName, err := p.expect(tokenName, "")
+			if err != nil {
+				return field, err
+			}
+			if _, err := p.expect(tokenPunctuator, ":"); err != nil {
+				return field, err
+			}
+			argType, err := p.parseType()
+			if err != nil {
+				return field, err
+			}
+			if p.is(tokenPunctuator, "=") {
+				p.next()
+				if err := p.skipValue(); err != nil {
+					return field, err
+				}
+			}
+			if err := p.skipDirectives(); err != nil {
+				return field, err
+			}
+			field.Arguments = append(field.Arguments, Argument{Name: argName.value, Type: argType})
+		}
+		p.next()
+	}
+
+	if _, err := p.expect(tokenPunctuator, ":"); err != nil {
+		return field, err
+	}
+	if field.ReturnType, err = p.parseType(); err != nil {
+		return field, err
+	}
+	return field, p.skipDirectives()
+}
+
+// parseType - parses a type reference, e.g. [Book!]!
+func (p *parser) parseType() (string, error) {
+	var typeName string
+	if p.is(tokenPunctuator, "[") {
+		p.next()
+		inner, err := p.parseType()
+		if err != nil {
+			return "", err
+		}
+		if _, err := p.expect(tokenPunctuator, "]"); err != nil {
+			return "", err
+		}
+		typeName = "[" + inner + "]"
+	} else {
+		name, err := p.expect(tokenName, "")
+		if err != nil {
+			return "", err
+		}
+		typeName = name.value
+	}
+	if p.is(tokenPunctuator, "!") {
+		p.next()
+		typeName += "!"
+	}
+	return typeName, nil
+}
+
+func (p *parser) skipDirectives() error {
+	for p.is(tokenPunctuator, "@") {
+		p.next()
+		if _, err := p.expect(tokenName, ""); err != nil {
+			return err
+		}
+		if p.is(tokenPunctuator, "(") {
+			if err := p.skipBalanced("(", ")"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// skipValue - skips a constant value, a list or object value is skipped up to its closing bracket
+func (p *parser) skipValue() error {
+	switch {
+	case p.is(tokenPunctuator, "["):
+		return p.skipBalanced("[", "]")
+	case p.is(tokenPunctuator, "{"):
+		return p.skipBalanced("{", "}")
+	}
+	t := p.next()
+	if t.kind == tokenEOF || t.kind == tokenPunctuator {
+		return fmt.Errorf("line %d: expected a value, found '%s'", t.line, t.value)
+	}
+	return nil
+}
+
+func (p *parser) skipBalanced(open, close string) error {
+	depth := 0
+	for {
+		t := p.next()
+		switch {
+		case t.kind == tokenEOF:
+			return fmt.Errorf("line %d: expected '%s'", t.line, close)
+		case t.kind == tokenPunctuator && t.value == open:
+			depth++
+		case t.kind == tokenPunctuator && t.value == close:
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+// skipDefinition - skips the definitions that have no operation, up to the next definition
+func (p *parser) skipDefinition() error {
+	depth := 0
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokenEOF:
+			if depth > 0 {
+				return fmt.Errorf("line %d: unexpected end of the document", t.line)
+			}
+			return nil
+		case t.kind == tokenPunctuator && strings.Contains("({[", t.value):
+			depth++
+		case t.kind == tokenPunctuator && strings.Contains(")}]", t.value):
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("line %d: unexpected '%s'", t.line, t.value)
+			}
+			if depth == 0 && t.value == "}" {
+				p.next()
+				return nil
+			}
+		case depth == 0 && (t.kind == tokenString || (t.kind == tokenName && definitionKeywords[t.value])):
+			return nil
+		}
+		p.next()
+	}
+}
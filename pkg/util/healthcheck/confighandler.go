@@ -0,0 +1,41 @@
+package healthcheck
+
+import (
+	"net/http"
+	"sync"
+)
+
+const configEndpoint = "config"
+
+var (
+	configSourcesLock sync.RWMutex
+	configSources     func() interface{}
+)
+
+// SetConfigSources - Sets the func returning the config values and their source, served on /status/config
+func SetConfigSources(getter func() interface{}) {
+	configSourcesLock.Lock()
+	defer configSourcesLock.Unlock()
+	configSources = getter
+}
+
+// configHandler - serves the config values and where they came from on /status/config, with the same bearer token as
+// the job control endpoints
+func configHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !authorizeControl(w, r) {
+		return
+	}
+
+	configSourcesLock.RLock()
+	getter := configSources
+	configSourcesLock.RUnlock()
+	if getter == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, getter())
+}
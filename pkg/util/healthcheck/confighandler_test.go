@@ -0,0 +1,58 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corecfg "github.com/Axway/agent-sdk/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveConfig(method, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/status/"+configEndpoint, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	configHandler(rec, req)
+	return rec
+}
+
+func TestConfigHandler(t *testing.T) {
+	defer SetConfigSources(nil)
+	defer SetStatusConfig(GetStatusConfig())
+
+	cfg := corecfg.NewStatusConfig().(*corecfg.StatusConfiguration)
+	cfg.ControlToken = "secret"
+	SetStatusConfig(cfg)
+
+	// the config sources are not set
+	SetConfigSources(nil)
+	rec := serveConfig(http.MethodGet, "secret")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	SetConfigSources(func() interface{} {
+		return []map[string]string{{"property": "central.team", "source": "resource", "value": "team"}}
+	})
+	rec = serveConfig(http.MethodGet, "secret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"property":"central.team","source":"resource","value":"team"}]`, rec.Body.String())
+
+	// the config values require the control token
+	rec = serveConfig(http.MethodGet, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "team")
+	rec = serveConfig(http.MethodGet, "bad")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	SetStatusConfig(corecfg.NewStatusConfig())
+	rec = serveConfig(http.MethodGet, "secret")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serveConfig(http.MethodPost, "secret")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	_, err := RegisterHealthcheck("config", configEndpoint, func(name string) *Status { return &Status{Result: OK} })
+	assert.NotNil(t, err)
+}
@@ -49,6 +49,12 @@ func RegisterHealthcheck(name, endpoint string, check CheckStatus) (string, erro
 	if _, ok := globalHealthChecker.Checks[endpoint]; ok {
 		return "", fmt.Errorf("A check with the endpoint of %s already exists", endpoint)
 	}
+	if endpoint == jobsEndpoint {
+		return "", fmt.Errorf("The endpoint %s is reserved for the jobs", endpoint)
+	}
+	if endpoint == configEndpoint {
+		return "", fmt.Errorf("The endpoint %s is reserved for the config sources", endpoint)
+	}
 
 	newID, _ := uuid.NewUUID()
 	newChecker := &statusCheck{
@@ -135,6 +141,10 @@ func HandleRequests() {
 	if !globalHealthChecker.registered {
 		http.HandleFunc("/status", statusHandler)
 		http.HandleFunc("/status/", statusHandler)
+		http.HandleFunc("/status/"+jobsEndpoint, jobsHandler)
+		http.HandleFunc("/status/"+jobsEndpoint+"/", jobsHandler)
+		http.HandleFunc("/status/"+configEndpoint, configHandler)
+		http.HandleFunc(metricsPath, metricsHandler)
 		globalHealthChecker.registered = true
 	}
 
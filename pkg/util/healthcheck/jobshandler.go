@@ -0,0 +1,117 @@
+package healthcheck
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Axway/agent-sdk/pkg/jobs"
+	"github.com/Axway/agent-sdk/pkg/util/log"
+)
+
+const jobsEndpoint = "jobs"
+
+// jobActions - the control actions of the jobs
+var jobActions = map[string]func(jobID string) error{
+	"trigger": jobs.TriggerJob,
+	"pause":   jobs.PauseJob,
+	"resume":  jobs.ResumeJob,
+}
+
+// jobsHandler - serves the info of all jobs on /status/jobs, the info of a job, by id or name, on /status/jobs/{job}
+// and the trigger, pause and resume of a job on POST /status/jobs/{job}/{action}
+func jobsHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/status/"+jobsEndpoint), "/")
+	parts := make([]string, 0)
+	if path != "" {
+		parts = strings.Split(path, "/")
+	}
+
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, jobs.GetJobsInfo())
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		jobID, err := jobs.FindJobID(parts[0])
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		info, err := jobs.GetJobInfo(jobID)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, info)
+	case len(parts) == 2 && r.Method == http.MethodPost:
+		controlJob(w, r, parts[0], parts[1])
+	case len(parts) <= 2:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// controlJob - runs the action on the job, once the request is authorized
+func controlJob(w http.ResponseWriter, r *http.Request, job, action string) {
+	actionFunc, ok := jobActions[action]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown job action %s, expected trigger, pause or resume", action))
+		return
+	}
+	if !authorizeControl(w, r) {
+		return
+	}
+
+	jobID, err := jobs.FindJobID(job)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err := actionFunc(jobID); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	log.Infof("Job %s: %s requested from %s", job, action, r.RemoteAddr)
+
+	info, _ := jobs.GetJobInfo(jobID)
+	writeJSON(w, http.StatusAccepted, info)
+}
+
+// authorizeControl - checks the bearer token of the request against the control token of the status config, writes
+// the error response when the request is not authorized
+func authorizeControl(w http.ResponseWriter, r *http.Request) bool {
+	token := ""
+	if statusConfig != nil {
+		token = statusConfig.GetControlToken()
+	}
+	if token == "" {
+		writeError(w, http.StatusForbidden, fmt.Errorf("the control endpoints are disabled, status.controlToken is not set"))
+		return false
+	}
+
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") || subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, fmt.Errorf("a valid bearer token is required"))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, code int, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		log.Errorf("Error hit marshalling the job data to json: %s", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	w.Write(body)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
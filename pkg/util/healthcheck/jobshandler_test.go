@@ -0,0 +1,119 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	corecfg "github.com/Axway/agent-sdk/pkg/config"
+	"github.com/Axway/agent-sdk/pkg/jobs"
+	"github.com/stretchr/testify/assert"
+)
+
+type resyncJob struct {
+	jobs.Job
+	lock       sync.Mutex
+	executions int
+}
+
+func (j *resyncJob) Execute() error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.executions++
+	return nil
+}
+
+func (j *resyncJob) Status() error { return nil }
+
+func (j *resyncJob) Ready() bool { return true }
+
+func (j *resyncJob) getExecutions() int {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.executions
+}
+
+func serveJobs(method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	jobsHandler(rec, req)
+	return rec
+}
+
+func TestJobsHandler(t *testing.T) {
+	job := &resyncJob{}
+	jobID, _ := jobs.RegisterIntervalJob(job, time.Hour, jobs.WithJobName("test-resync"))
+	defer jobs.UnregisterJob(jobID)
+	time.Sleep(20 * time.Millisecond)
+
+	// the info of all jobs
+	rec := serveJobs(http.MethodGet, "/status/jobs", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	infos := make([]jobs.JobInfo, 0)
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &infos))
+	found := false
+	for _, info := range infos {
+		if info.ID == jobID {
+			found = true
+			assert.Equal(t, "test-resync", info.Name)
+		}
+	}
+	assert.True(t, found)
+
+	// the info of the job by name
+	rec = serveJobs(http.MethodGet, "/status/jobs/test-resync", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	info := jobs.JobInfo{}
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &info))
+	assert.Equal(t, jobID, info.ID)
+	assert.Equal(t, 1, info.RunCount)
+
+	rec = serveJobs(http.MethodGet, "/status/jobs/unknown", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// the control endpoints are disabled without a token
+	SetStatusConfig(corecfg.NewStatusConfig())
+	rec = serveJobs(http.MethodPost, "/status/jobs/test-resync/trigger", "token")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	cfg := corecfg.NewStatusConfig().(*corecfg.StatusConfiguration)
+	cfg.ControlToken = "secret"
+	SetStatusConfig(cfg)
+	rec = serveJobs(http.MethodPost, "/status/jobs/test-resync/trigger", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = serveJobs(http.MethodPost, "/status/jobs/test-resync/trigger", "bad")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, 1, job.getExecutions())
+
+	// trigger, pause and resume
+	rec = serveJobs(http.MethodPost, "/status/jobs/test-resync/trigger", "secret")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 2, job.getExecutions())
+
+	rec = serveJobs(http.MethodPost, "/status/jobs/"+jobID+"/pause", "secret")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &info))
+	assert.True(t, info.Paused)
+	rec = serveJobs(http.MethodPost, "/status/jobs/test-resync/trigger", "secret")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = serveJobs(http.MethodPost, "/status/jobs/test-resync/resume", "secret")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 3, job.getExecutions())
+
+	rec = serveJobs(http.MethodPost, "/status/jobs/test-resync/restart", "secret")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = serveJobs(http.MethodDelete, "/status/jobs/test-resync", "secret")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	_, err := RegisterHealthcheck("jobs", jobsEndpoint, func(name string) *Status { return &Status{Result: OK} })
+	assert.NotNil(t, err)
+}
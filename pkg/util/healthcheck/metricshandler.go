@@ -0,0 +1,23 @@
+package healthcheck
+
+import (
+	"net/http"
+
+	"github.com/Axway/agent-sdk/pkg/util/metrics"
+)
+
+const metricsPath = "/metrics"
+
+// metricsHandler - serves the metrics of the default registry, in the OpenMetrics text format when the scraper
+// accepts it, unless disabled by the status.metrics config
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if statusConfig != nil && !statusConfig.IsMetricsEnabled() {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	metrics.GetDefaultRegistry().Handler().ServeHTTP(w, r)
+}
@@ -0,0 +1,40 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corecfg "github.com/Axway/agent-sdk/pkg/config"
+	"github.com/Axway/agent-sdk/pkg/util/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	defer SetStatusConfig(corecfg.NewStatusConfig())
+	counter, _ := metrics.GetOrRegisterCounter("agent_test_requests", "The test requests", nil)
+	counter.Inc(3)
+
+	SetStatusConfig(corecfg.NewStatusConfig())
+	req := httptest.NewRequest(http.MethodGet, metricsPath, nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	rec := httptest.NewRecorder()
+	metricsHandler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, metrics.OpenMetricsContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "# HELP agent_test_requests The test requests\nagent_test_requests_total 3\n")
+	// the job metrics are collected by the jobs package
+	assert.Contains(t, rec.Body.String(), "# TYPE agent_job_runs counter\n")
+
+	rec = httptest.NewRecorder()
+	metricsHandler(rec, httptest.NewRequest(http.MethodPost, metricsPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	// disabled by the config
+	cfg := corecfg.NewStatusConfig().(*corecfg.StatusConfiguration)
+	cfg.Metrics = false
+	SetStatusConfig(cfg)
+	rec = httptest.NewRecorder()
+	metricsHandler(rec, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// MetricType - the type of a metric family
+type MetricType string
+
+const (
+	// CounterType - a value that only increases, e.g. a count of requests
+	CounterType MetricType = "counter"
+	// GaugeType - a value that goes up and down, e.g. the items in a cache
+	GaugeType MetricType = "gauge"
+	// HistogramType - the counts of the observed values by bucket, with their sum and count
+	HistogramType MetricType = "histogram"
+)
+
+// DefaultDurationBuckets - the upper bounds, in seconds, of the buckets of the duration histograms
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Labels - the label names and values of a metric
+type Labels map[string]string
+
+// key - returns the labels sorted by name, identifying the metric in its family
+func (l Labels) key() string {
+	names := l.names()
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"="+l[name])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// names - returns the label names, sorted
+func (l Labels) names() []string {
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// copy - returns a copy of the labels, the caller may change its map
+func (l Labels) copy() Labels {
+	labels := make(Labels, len(l))
+	for name, value := range l {
+		labels[name] = value
+	}
+	return labels
+}
+
+// Bucket - the count of the observed values less than or equal to the upper bound, including the lower buckets
+type Bucket struct {
+	UpperBound float64
+	Count      uint64
+}
+
+// Sample - the value of a metric, with its labels
+type Sample struct {
+	Labels Labels
+	// Value - the value of a counter or a gauge
+	Value float64
+	// Buckets - the cumulative counts of a histogram, the +Inf bucket is the Count
+	Buckets []Bucket
+	// Count - the count of the values observed by a histogram
+	Count uint64
+	// Sum - the sum of the values observed by a histogram
+	Sum float64
+}
+
+// Family - the metrics with the same name, type and help, differing by their labels. The name of a counter does not
+// include the _total suffix, it is added to its samples
+type Family struct {
+	Name    string
+	Help    string
+	Type    MetricType
+	Samples []Sample
+}
+
+// Collector - Callback returning the metric families read when the metrics are scraped, e.g. the size of a cache
+type Collector func() []Family
+
+// Histogram - counts the observed values by bucket
+type Histogram interface {
+	// Observe - records the value
+	Observe(value float64)
+	// UpdateSince - records the seconds elapsed since the time
+	UpdateSince(start time.Time)
+	// Snapshot - returns the buckets, count and sum of the observed values
+	Snapshot() Sample
+}
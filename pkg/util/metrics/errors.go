@@ -0,0 +1,9 @@
+package metrics
+
+import "github.com/Axway/agent-sdk/pkg/util/errors"
+
+// Errors hit when registering the metrics
+var (
+	ErrInvalidMetricName  = errors.Newf(1620, "invalid metric or label name %s, the names must match [a-zA-Z_:][a-zA-Z0-9_:]*")
+	ErrMetricTypeConflict = errors.Newf(1621, "the metric %s is already registered as a %s, or with other buckets")
+)
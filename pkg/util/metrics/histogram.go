@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// histogram - counts the observed values in fixed buckets
+type histogram struct {
+	lock        sync.Mutex
+	upperBounds []float64
+	counts      []uint64 // a count per bucket, then the count of the values above the last bucket
+	count       uint64
+	sum         float64
+}
+
+// NewHistogram - creates a histogram with the bucket upper bounds, DefaultDurationBuckets when none are given
+func NewHistogram(upperBounds []float64) Histogram {
+	return newHistogram(upperBounds)
+}
+
+func newHistogram(upperBounds []float64) *histogram {
+	bounds := normalizeBuckets(upperBounds)
+	return &histogram{
+		upperBounds: bounds,
+		counts:      make([]uint64, len(bounds)+1),
+	}
+}
+
+// normalizeBuckets - returns the sorted copy of the upper bounds, DefaultDurationBuckets when none are given
+func normalizeBuckets(upperBounds []float64) []float64 {
+	if len(upperBounds) == 0 {
+		upperBounds = DefaultDurationBuckets
+	}
+	bounds := make([]float64, len(upperBounds))
+	copy(bounds, upperBounds)
+	sort.Float64s(bounds)
+	return bounds
+}
+
+// sameBuckets - returns true when the normalized upper bounds are equal
+func sameBuckets(upperBounds, otherBounds []float64) bool {
+	if len(upperBounds) != len(otherBounds) {
+		return false
+	}
+	for i := range upperBounds {
+		if upperBounds[i] != otherBounds[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Observe - records the value
+func (h *histogram) Observe(value float64) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.counts[sort.SearchFloat64s(h.upperBounds, value)]++
+	h.count++
+	h.sum += value
+}
+
+// UpdateSince - records the seconds elapsed since the time
+func (h *histogram) UpdateSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+// Snapshot - returns the cumulative counts of the buckets, the count and the sum of the observed values
+func (h *histogram) Snapshot() Sample {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	sample := Sample{
+		Buckets: make([]Bucket, len(h.upperBounds)),
+		Count:   h.count,
+		Sum:     h.sum,
+	}
+	var count uint64
+	for i, upper := range h.upperBounds {
+		count += h.counts[i]
+		sample.Buckets[i] = Bucket{UpperBound: upper, Count: count}
+	}
+	return sample
+}
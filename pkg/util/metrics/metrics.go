@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+// defaultRegistry - the registry of the SDK and agent metrics, exposed by the status server
+var defaultRegistry = NewRegistry()
+
+// GetDefaultRegistry - Returns the registry exposed on the metrics endpoint of the status server
+func GetDefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// GetOrRegisterCounter - Returns the counter with the labels in the default registry, registered on first use
+func GetOrRegisterCounter(name, help string, labels Labels) (gometrics.Counter, error) {
+	return defaultRegistry.GetOrRegisterCounter(name, help, labels)
+}
+
+// GetOrRegisterGauge - Returns the gauge with the labels in the default registry, registered on first use
+func GetOrRegisterGauge(name, help string, labels Labels) (gometrics.GaugeFloat64, error) {
+	return defaultRegistry.GetOrRegisterGauge(name, help, labels)
+}
+
+// GetOrRegisterHistogram - Returns the histogram with the labels in the default registry, registered on first use
+func GetOrRegisterHistogram(name, help string, upperBounds []float64, labels Labels) (Histogram, error) {
+	return defaultRegistry.GetOrRegisterHistogram(name, help, upperBounds, labels)
+}
+
+// RegisterCollector - Registers the collector in the default registry, replacing the collector with the same name
+func RegisterCollector(name string, collector Collector) {
+	defaultRegistry.RegisterCollector(name, collector)
+}
+
+// UnregisterCollector - Removes the collector from the default registry
+func UnregisterCollector(name string) {
+	defaultRegistry.UnregisterCollector(name)
+}
+
+// IncCounter - Increments the counter with the labels in the default registry, the counter is not exposed when it can
+// not be registered
+func IncCounter(name, help string, labels Labels, count int64) {
+	counter, _ := defaultRegistry.GetOrRegisterCounter(name, help, labels)
+	counter.Inc(count)
+}
+
+// ObserveHistogram - Records the value in the histogram with the labels in the default registry, the histogram is not
+// exposed when it can not be registered
+func ObserveHistogram(name, help string, upperBounds []float64, labels Labels, value float64) {
+	histogram, _ := defaultRegistry.GetOrRegisterHistogram(name, help, upperBounds, labels)
+	histogram.Observe(value)
+}
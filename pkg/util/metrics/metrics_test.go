@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	counter, err := registry.GetOrRegisterCounter("agent_requests_total", "The requests", Labels{"code": "200"})
+	assert.Nil(t, err)
+	counter.Inc(2)
+	same, err := registry.GetOrRegisterCounter("agent_requests", "The requests", Labels{"code": "200"})
+	assert.Nil(t, err)
+	assert.Equal(t, counter, same)
+	other, _ := registry.GetOrRegisterCounter("agent_requests", "The requests", Labels{"code": "500"})
+	other.Inc(1)
+
+	gauge, err := registry.GetOrRegisterGauge("agent_items", "The items", nil)
+	assert.Nil(t, err)
+	gauge.Update(4)
+
+	histogram, err := registry.GetOrRegisterHistogram("agent_duration_seconds", "The durations", []float64{1, 0.1}, nil)
+	assert.Nil(t, err)
+	histogram.Observe(0.05)
+	histogram.Observe(0.5)
+	histogram.Observe(2)
+
+	// invalid names and type conflicts return a metric that is not exposed
+	_, err = registry.GetOrRegisterCounter("agent-requests", "", nil)
+	assert.NotNil(t, err)
+	_, err = registry.GetOrRegisterCounter("agent_requests", "", Labels{"__name": "x"})
+	assert.NotNil(t, err)
+	_, err = registry.GetOrRegisterHistogram("agent_duration_seconds", "", []float64{1, 0.1}, Labels{"le": "1"})
+	assert.NotNil(t, err)
+	notExposed, err := registry.GetOrRegisterGauge("agent_requests", "", nil)
+	assert.NotNil(t, err)
+	notExposed.Update(10)
+	_, err = registry.GetOrRegisterHistogram("agent_duration_seconds", "", []float64{1}, nil)
+	assert.NotNil(t, err)
+
+	registry.RegisterCollector("cache", func() []Family {
+		return []Family{
+			{Name: "agent_items", Type: GaugeType, Samples: []Sample{{Labels: Labels{"cache": "apis"}, Value: 3}}},
+			{Name: "agent_requests", Type: GaugeType, Samples: []Sample{{Value: 1}}},
+		}
+	})
+
+	families := registry.Gather()
+	assert.Len(t, families, 3)
+	assert.Equal(t, "agent_duration_seconds", families[0].Name)
+	assert.Equal(t, []Bucket{{UpperBound: 0.1, Count: 1}, {UpperBound: 1, Count: 2}}, families[0].Samples[0].Buckets)
+	assert.Equal(t, uint64(3), families[0].Samples[0].Count)
+	assert.Equal(t, 2.55, families[0].Samples[0].Sum)
+	assert.Equal(t, "agent_items", families[1].Name)
+	assert.Len(t, families[1].Samples, 2)
+	assert.Equal(t, "agent_requests", families[2].Name)
+	assert.Equal(t, CounterType, families[2].Type)
+	assert.Equal(t, []Sample{{Labels: Labels{"code": "200"}, Value: 2}, {Labels: Labels{"code": "500"}, Value: 1}}, families[2].Samples)
+
+	registry.UnregisterCollector("cache")
+	families = registry.Gather()
+	assert.Len(t, families[1].Samples, 1)
+}
+
+func TestHistogramUpdateSince(t *testing.T) {
+	histogram := NewHistogram(nil)
+	histogram.UpdateSince(time.Now().Add(-200 * time.Millisecond))
+	sample := histogram.Snapshot()
+	assert.Len(t, sample.Buckets, len(DefaultDurationBuckets))
+	assert.Equal(t, uint64(1), sample.Count)
+	assert.True(t, sample.Sum >= 0.2)
+	for _, bucket := range sample.Buckets {
+		if bucket.UpperBound < 0.2 {
+			assert.Equal(t, uint64(0), bucket.Count)
+		} else {
+			assert.Equal(t, uint64(1), bucket.Count)
+		}
+	}
+}
+
+func testFamilies() []Family {
+	return []Family{
+		{Name: "agent_requests", Help: "The requests\nby code", Type: CounterType, Samples: []Sample{
+			{Labels: Labels{"code": "200", "host": `a"b\c`}, Value: 2},
+		}},
+		{Name: "agent_items", Type: GaugeType, Samples: []Sample{{Value: 1.5}}},
+		{Name: "agent_duration_seconds", Help: "The durations", Type: HistogramType, Samples: []Sample{
+			{Labels: Labels{"job": "sync"}, Buckets: []Bucket{{UpperBound: 0.5, Count: 1}, {UpperBound: 1, Count: 2}}, Count: 3, Sum: 4.25},
+		}},
+	}
+}
+
+func TestWriteOpenMetrics(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WriteOpenMetrics(buf, testFamilies()))
+	assert.Equal(t, `# TYPE agent_requests counter
+# HELP agent_requests The requests\nby code
+agent_requests_total{code="200",host="a\"b\\c"} 2
+# TYPE agent_items gauge
+agent_items 1.5
+# TYPE agent_duration_seconds histogram
+# HELP agent_duration_seconds The durations
+agent_duration_seconds_bucket{job="sync",le="0.5"} 1
+agent_duration_seconds_bucket{job="sync",le="1.0"} 2
+agent_duration_seconds_bucket{job="sync",le="+Inf"} 3
+agent_duration_seconds_sum{job="sync"} 4.25
+agent_duration_seconds_count{job="sync"} 3
+# EOF
+`, buf.String())
+}
+
+func TestWritePrometheus(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WritePrometheus(buf, testFamilies()[:2]))
+	assert.Equal(t, `# HELP agent_requests_total The requests\nby code
+# TYPE agent_requests_total counter
+agent_requests_total{code="200",host="a\"b\\c"} 2
+# TYPE agent_items gauge
+agent_items 1.5
+`, buf.String())
+}
+
+func TestHandler(t *testing.T) {
+	registry := NewRegistry()
+	counter, _ := registry.GetOrRegisterCounter("agent_requests", "The requests", nil)
+	counter.Inc(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text;version=1.0.0,text/plain;q=0.5")
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, OpenMetricsContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "agent_requests_total 1\n# EOF\n")
+
+	req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec = httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, PrometheusContentType, rec.Header().Get("Content-Type"))
+	assert.NotContains(t, rec.Body.String(), "# EOF")
+}
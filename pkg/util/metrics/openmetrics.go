@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// OpenMetricsContentType - the content type of the OpenMetrics text format
+	OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	// PrometheusContentType - the content type of the Prometheus text format
+	PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+// Handler - Returns the handler serving the metrics of the registry, in the OpenMetrics text format when the scraper
+// accepts it, in the Prometheus text format otherwise
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		openMetrics := strings.Contains(req.Header.Get("Accept"), "application/openmetrics-text")
+		contentType := PrometheusContentType
+		if openMetrics {
+			contentType = OpenMetricsContentType
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		write(w, r.Gather(), openMetrics)
+	})
+}
+
+// WriteOpenMetrics - writes the families in the OpenMetrics text format
+func WriteOpenMetrics(w io.Writer, families []Family) error {
+	return write(w, families, true)
+}
+
+// WritePrometheus - writes the families in the Prometheus text format
+func WritePrometheus(w io.Writer, families []Family) error {
+	return write(w, families, false)
+}
+
+// write - writes the families in the OpenMetrics text format, or in the Prometheus text format where the metadata of a
+// counter is named after its samples and the exposition has no EOF
+func write(w io.Writer, families []Family, openMetrics bool) error {
+	buf := bufio.NewWriter(w)
+	for _, f := range families {
+		metadataName := f.Name
+		if f.Type == CounterType && !openMetrics {
+			metadataName += "_total"
+		}
+		if openMetrics {
+			buf.WriteString("# TYPE " + metadataName + " " + string(f.Type) + "\n")
+		}
+		if f.Help != "" {
+			buf.WriteString("# HELP " + metadataName + " " + helpEscaper.Replace(f.Help) + "\n")
+		}
+		if !openMetrics {
+			buf.WriteString("# TYPE " + metadataName + " " + string(f.Type) + "\n")
+		}
+
+		for _, sample := range f.Samples {
+			switch f.Type {
+			case CounterType:
+				writeSample(buf, f.Name+"_total", sample.Labels, "", sample.Value)
+			case HistogramType:
+				for _, bucket := range sample.Buckets {
+					writeSample(buf, f.Name+"_bucket", sample.Labels, formatBound(bucket.UpperBound), float64(bucket.Count))
+				}
+				writeSample(buf, f.Name+"_bucket", sample.Labels, "+Inf", float64(sample.Count))
+				writeSample(buf, f.Name+"_sum", sample.Labels, "", sample.Sum)
+				writeSample(buf, f.Name+"_count", sample.Labels, "", float64(sample.Count))
+			default:
+				writeSample(buf, f.Name, sample.Labels, "", sample.Value)
+			}
+		}
+	}
+	if openMetrics {
+		buf.WriteString("# EOF\n")
+	}
+	return buf.Flush()
+}
+
+// writeSample - writes a line of the exposition, the le label is added to the labels when set
+func writeSample(buf *bufio.Writer, name string, labels Labels, le string, value float64) {
+	buf.WriteString(name)
+	if len(labels) > 0 || le != "" {
+		pairs := make([]string, 0, len(labels)+1)
+		for _, labelName := range labels.names() {
+			pairs = append(pairs, labelName+`="`+labelValueEscaper.Replace(labels[labelName])+`"`)
+		}
+		if le != "" {
+			pairs = append(pairs, `le="`+le+`"`)
+		}
+		buf.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+	buf.WriteString(" " + formatValue(value) + "\n")
+}
+
+// formatValue - formats the value of a sample
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// formatBound - formats the upper bound of a bucket as a float, e.g. 1.0
+func formatBound(bound float64) string {
+	formatted := formatValue(bound)
+	if !strings.ContainsAny(formatted, ".eIN") {
+		formatted += ".0"
+	}
+	return formatted
+}
@@ -0,0 +1,212 @@
+package metrics
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Axway/agent-sdk/pkg/util/log"
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+var (
+	metricNameRegEx = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRegEx  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// series - a metric of a family, identified by its labels
+type series struct {
+	labels Labels
+	metric interface{} // gometrics.Counter, gometrics.GaugeFloat64 or *histogram
+}
+
+// family - the registered metrics with the same name
+type family struct {
+	name        string
+	help        string
+	metricType  MetricType
+	upperBounds []float64 // the buckets of a histogram
+	series      map[string]*series
+}
+
+// Registry - the metrics exposed on the metrics endpoint, the registered metrics and the families of the collectors
+type Registry struct {
+	lock       sync.RWMutex
+	families   map[string]*family
+	collectors map[string]Collector
+}
+
+// NewRegistry - creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		families:   make(map[string]*family),
+		collectors: make(map[string]Collector),
+	}
+}
+
+// validateNames - returns an error when the metric name or a label name is not valid
+func validateNames(name string, labels Labels) error {
+	if !metricNameRegEx.MatchString(name) {
+		return ErrInvalidMetricName.FormatError(name)
+	}
+	for labelName := range labels {
+		if !labelNameRegEx.MatchString(labelName) || strings.HasPrefix(labelName, "__") {
+			return ErrInvalidMetricName.FormatError(labelName)
+		}
+	}
+	return nil
+}
+
+// getOrRegister - returns the metric of the family with the labels, created by newMetric on first use. When the
+// family is registered with another type or other buckets, an error is returned with a metric that is not exposed
+func (r *Registry) getOrRegister(name, help string, metricType MetricType, upperBounds []float64, labels Labels, newMetric func() interface{}) (interface{}, error) {
+	if metricType == CounterType {
+		name = strings.TrimSuffix(name, "_total")
+	}
+	if err := validateNames(name, labels); err != nil {
+		return newMetric(), err
+	}
+	if metricType == HistogramType {
+		if _, found := labels["le"]; found {
+			return newMetric(), ErrInvalidMetricName.FormatError("le")
+		}
+		upperBounds = normalizeBuckets(upperBounds)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	f, found := r.families[name]
+	if !found {
+		f = &family{
+			name:        name,
+			help:        help,
+			metricType:  metricType,
+			upperBounds: upperBounds,
+			series:      make(map[string]*series),
+		}
+		r.families[name] = f
+	}
+	if f.metricType != metricType || !sameBuckets(f.upperBounds, upperBounds) {
+		return newMetric(), ErrMetricTypeConflict.FormatError(name, f.metricType)
+	}
+
+	key := labels.key()
+	s, found := f.series[key]
+	if !found {
+		s = &series{labels: labels.copy(), metric: newMetric()}
+		f.series[key] = s
+	}
+	return s.metric, nil
+}
+
+// GetOrRegisterCounter - returns the counter with the labels, registered on first use. The _total suffix is added to
+// the name of the counter when it is exposed
+func (r *Registry) GetOrRegisterCounter(name, help string, labels Labels) (gometrics.Counter, error) {
+	metric, err := r.getOrRegister(name, help, CounterType, nil, labels, func() interface{} { return gometrics.NewCounter() })
+	return metric.(gometrics.Counter), err
+}
+
+// GetOrRegisterGauge - returns the gauge with the labels, registered on first use
+func (r *Registry) GetOrRegisterGauge(name, help string, labels Labels) (gometrics.GaugeFloat64, error) {
+	metric, err := r.getOrRegister(name, help, GaugeType, nil, labels, func() interface{} { return gometrics.NewGaugeFloat64() })
+	return metric.(gometrics.GaugeFloat64), err
+}
+
+// GetOrRegisterHistogram - returns the histogram with the labels, registered on first use. The histograms of a family
+// have the same buckets, DefaultDurationBuckets when none are given
+func (r *Registry) GetOrRegisterHistogram(name, help string, upperBounds []float64, labels Labels) (Histogram, error) {
+	metric, err := r.getOrRegister(name, help, HistogramType, upperBounds, labels, func() interface{} { return newHistogram(upperBounds) })
+	return metric.(*histogram), err
+}
+
+// RegisterCollector - registers the collector called when the metrics are scraped, replacing the collector registered
+// with the same name
+func (r *Registry) RegisterCollector(name string, collector Collector) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.collectors[name] = collector
+}
+
+// UnregisterCollector - removes the collector registered with the name
+func (r *Registry) UnregisterCollector(name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.collectors, name)
+}
+
+// Gather - returns the families of the registered metrics and of the collectors, sorted by name. The families with the
+// same name are merged, the samples of a family registered with another type are dropped
+func (r *Registry) Gather() []Family {
+	r.lock.RLock()
+	families := make(map[string]*Family, len(r.families))
+	for name, f := range r.families {
+		families[name] = f.gather()
+	}
+	collectorNames := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		collectorNames = append(collectorNames, name)
+	}
+	sort.Strings(collectorNames)
+	collectors := make([]Collector, 0, len(collectorNames))
+	for _, name := range collectorNames {
+		collectors = append(collectors, r.collectors[name])
+	}
+	r.lock.RUnlock()
+
+	// the collectors are called without the lock, they may register metrics
+	for _, collector := range collectors {
+		for _, collected := range collector() {
+			if collected.Type == CounterType {
+				collected.Name = strings.TrimSuffix(collected.Name, "_total")
+			}
+			if err := validateNames(collected.Name, nil); err != nil {
+				log.Error(err.Error())
+				continue
+			}
+			existing, found := families[collected.Name]
+			if !found {
+				c := collected
+				families[collected.Name] = &c
+				continue
+			}
+			if existing.Type != collected.Type {
+				log.Error(ErrMetricTypeConflict.FormatError(collected.Name, existing.Type).Error())
+				continue
+			}
+			existing.Samples = append(existing.Samples, collected.Samples...)
+		}
+	}
+
+	gathered := make([]Family, 0, len(families))
+	for _, f := range families {
+		sort.SliceStable(f.Samples, func(i, j int) bool { return f.Samples[i].Labels.key() < f.Samples[j].Labels.key() })
+		gathered = append(gathered, *f)
+	}
+	sort.Slice(gathered, func(i, j int) bool { return gathered[i].Name < gathered[j].Name })
+	return gathered
+}
+
+// gather - returns the samples of the family
+func (f *family) gather() *Family {
+	gathered := &Family{
+		Name:    f.name,
+		Help:    f.help,
+		Type:    f.metricType,
+		Samples: make([]Sample, 0, len(f.series)),
+	}
+	for _, s := range f.series {
+		var sample Sample
+		switch metric := s.metric.(type) {
+		case gometrics.Counter:
+			sample.Value = float64(metric.Count())
+		case gometrics.GaugeFloat64:
+			sample.Value = metric.Value()
+		case *histogram:
+			sample = metric.Snapshot()
+		}
+		sample.Labels = s.labels
+		gathered.Samples = append(gathered.Samples, sample)
+	}
+	return gathered
+}
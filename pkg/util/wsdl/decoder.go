@@ -1,20 +1,64 @@
 package wsdl
 
 import (
+	"bytes"
 	"encoding/xml"
+	"fmt"
 )
 
-// Unmarshal unmarshals WSDL documents starting from the <definitions> tag.
+// Unmarshal unmarshals WSDL documents starting from the <definitions> tag, or
+// the <description> tag of WSDL 2.0 documents.
 //
 // The Definitions object it returns is an unmarshalled version of the
 // WSDL XML that can be introspected to generate the Web Services API.
-func Unmarshal(bytes []byte) (*Definitions, error) {
-	var d Definitions
-	// decoder := xml.NewDecoder(r)
-	// decoder.CharsetReader = charset.NewReaderLabel
-	err := xml.Unmarshal(bytes, &d)
+// The imports are not resolved, see UnmarshalWithResolver.
+func Unmarshal(data []byte) (*Definitions, error) {
+	root, err := rootElement(data)
 	if err != nil {
 		return nil, err
 	}
-	return &d, nil
+
+	switch root.Name.Local {
+	case "description":
+		var desc description
+		err := xml.Unmarshal(data, &desc)
+		if err != nil {
+			return nil, err
+		}
+		return desc.toDefinitions(namespaces(root)), nil
+	case "definitions":
+		var d Definitions
+		// decoder := xml.NewDecoder(r)
+		// decoder.CharsetReader = charset.NewReaderLabel
+		err := xml.Unmarshal(data, &d)
+		if err != nil {
+			return nil, err
+		}
+		return &d, nil
+	}
+	return nil, fmt.Errorf("expected a definitions or description element, found %s", root.Name.Local)
+}
+
+// rootElement - returns the first element of the document
+func rootElement(data []byte) (xml.StartElement, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start, nil
+		}
+	}
+}
+
+func namespaces(start xml.StartElement) map[string]string {
+	ns := make(map[string]string)
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" {
+			ns[attr.Name.Local] = attr.Value
+		}
+	}
+	return ns
 }
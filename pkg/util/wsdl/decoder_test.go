@@ -0,0 +1,123 @@
+package wsdl
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalWithResolver(t *testing.T) {
+	data, err := ioutil.ReadFile("./testdata/bundle/orders.wsdl")
+	assert.Nil(t, err)
+
+	// without a resolver the imports are not resolved
+	def, err := Unmarshal(data)
+	assert.Nil(t, err)
+	assert.Equal(t, WSDL11, def.Version)
+	assert.Len(t, def.Services, 2)
+	assert.Len(t, def.Bindings, 2)
+	assert.Len(t, def.PortType, 0)
+	assert.Len(t, def.Schemas, 1)
+	assert.Equal(t, "OrdersService", def.Service.Name)
+	assert.Equal(t, "OrdersSoap", def.Binding.Name)
+
+	def, err = UnmarshalWithResolver(data, "orders.wsdl", NewLocalResolver("./testdata/bundle"))
+	assert.Nil(t, err)
+	assert.Len(t, def.Messages, 4)
+	assert.Len(t, def.PortType, 1)
+	// the included schema and the schema it includes
+	assert.Len(t, def.Schemas, 3)
+	assert.Equal(t, "Order", def.Schemas[2].ComplexTypes[0].Name)
+
+	assert.Equal(t, SOAP11, def.Bindings[0].SOAPVersion())
+	assert.Equal(t, SOAP12, def.Bindings[1].SOAPVersion())
+	assert.Equal(t, SOAP12, def.Services[0].Ports[1].Address.SOAPVersion())
+
+	assert.Equal(t, []OperationInfo{
+		{
+			Name:        "GetOrder",
+			Binding:     "OrdersSoap",
+			PortType:    "OrdersPortType",
+			SOAPAction:  "http://example.com/orders/GetOrder",
+			SOAPVersion: SOAP11,
+			Style:       "document",
+			Input:       "tns:GetOrderRequest",
+			Output:      "tns:GetOrderResponse",
+			Doc:         "Returns an order",
+		},
+		{
+			Name:        "CancelOrder",
+			Binding:     "OrdersSoap",
+			PortType:    "OrdersPortType",
+			SOAPAction:  "http://example.com/orders/CancelOrder",
+			SOAPVersion: SOAP11,
+			Style:       "document",
+			Input:       "tns:CancelOrderRequest",
+			Output:      "tns:CancelOrderResponse",
+		},
+		{
+			Name:        "GetOrder",
+			Binding:     "OrdersSoap12",
+			PortType:    "OrdersPortType",
+			SOAPAction:  "http://example.com/orders/GetOrder12",
+			SOAPVersion: SOAP12,
+			Style:       "document",
+			Input:       "tns:GetOrderRequest",
+			Output:      "tns:GetOrderResponse",
+			Doc:         "Returns an order",
+		},
+	}, def.GetOperations())
+
+	// a missing import fails
+	_, err = UnmarshalWithResolver(data, "orders.wsdl", NewBundleResolver(map[string][]byte{}))
+	assert.NotNil(t, err)
+}
+
+func TestLocalResolver(t *testing.T) {
+	resolver := NewLocalResolver("./testdata/bundle")
+
+	data, err := resolver.Resolve("schemas/../orders.wsdl")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, data)
+
+	// the files outside of the directory are not resolved
+	for _, location := range []string{"../reservation.wsdl", "schemas/../../reservation.wsdl", "..", "/etc/hosts", "http://example.com/orders.xsd"} {
+		_, err = resolver.Resolve(location)
+		assert.NotNil(t, err, location)
+	}
+
+	// an import going up from the document is not resolved
+	_, err = UnmarshalWithResolver([]byte(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/"><import location="../reservation.wsdl"/></definitions>`), "orders.wsdl", resolver)
+	assert.NotNil(t, err)
+}
+
+func TestUnmarshalWSDL20(t *testing.T) {
+	data, err := ioutil.ReadFile("./testdata/reservation.wsdl")
+	assert.Nil(t, err)
+
+	def, err := Unmarshal(data)
+	assert.Nil(t, err)
+	assert.Equal(t, WSDL20, def.Version)
+	assert.Equal(t, "http://greath.example.com/2004/wsdl/resSvc", def.TargetNamespace)
+	assert.Equal(t, "http://www.w3.org/ns/wsdl/soap", def.Namespaces["wsoap"])
+	assert.Len(t, def.Services, 1)
+	assert.Equal(t, "http://greath.example.com/2004/reservation", def.Service.Ports[0].Address.Location)
+	assert.Equal(t, SOAP12, def.Service.Ports[0].Address.SOAPVersion())
+
+	assert.Equal(t, []OperationInfo{
+		{
+			Name:        "opCheckAvailability",
+			Binding:     "reservationSOAPBinding",
+			PortType:    "reservationInterface",
+			SOAPAction:  "http://greath.example.com/2004/wsdl/resSvc#opCheckAvailability",
+			SOAPVersion: SOAP12,
+			Input:       "ghns:checkAvailability",
+			Output:      "ghns:checkAvailabilityResponse",
+			Doc:         "Checks the availability of a room",
+		},
+	}, def.GetOperations())
+
+	_, err = Unmarshal([]byte("<swagger></swagger>"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,75 @@
+package wsdl
+
+// OperationInfo - an operation of a binding, with its SOAP action and its input and output messages
+type OperationInfo struct {
+	Name        string
+	Binding     string
+	PortType    string
+	SOAPAction  string
+	SOAPVersion string // 1.1 or 1.2, empty for the operations of non SOAP bindings
+	Style       string
+	Input       string // the input message, or element of WSDL 2.0 operations
+	Output      string // the output message, or element of WSDL 2.0 operations
+	Doc         string
+}
+
+// GetOperations - returns the operations of all the bindings of the document
+func (def *Definitions) GetOperations() []OperationInfo {
+	operations := make([]OperationInfo, 0)
+	for _, binding := range def.Bindings {
+		portType := def.getPortType(binding.Type)
+		for _, bindingOp := range binding.Operations {
+			info := OperationInfo{
+				Name:        bindingOp.Name,
+				Binding:     binding.Name,
+				SOAPAction:  bindingOp.Operation.Action,
+				SOAPVersion: binding.SOAPVersion(),
+			}
+			if info.SOAPAction == "" {
+				info.SOAPAction = bindingOp.Operation11.Action
+			}
+			if binding.BindingType != nil {
+				info.Style = binding.BindingType.Style
+			}
+			if portType != nil {
+				info.PortType = portType.Name
+				if op := portType.getOperation(bindingOp.Name); op != nil {
+					info.Doc = op.Doc
+					info.Input = op.Input.messageOrElement()
+					info.Output = op.Output.messageOrElement()
+				}
+			}
+			operations = append(operations, info)
+		}
+	}
+	return operations
+}
+
+func (def *Definitions) getPortType(qname string) *PortType {
+	name := localName(qname)
+	for _, portType := range def.PortType {
+		if portType.Name == name {
+			return portType
+		}
+	}
+	return nil
+}
+
+func (p *PortType) getOperation(name string) *Operation {
+	for _, op := range p.Operations {
+		if op.Name == name {
+			return op
+		}
+	}
+	return nil
+}
+
+func (io *IO) messageOrElement() string {
+	if io == nil {
+		return ""
+	}
+	if io.Message != "" {
+		return io.Message
+	}
+	return io.Element
+}
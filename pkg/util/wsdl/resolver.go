@@ -0,0 +1,201 @@
+package wsdl
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Resolver - returns the content of the document at the location of a wsdl:import, xsd:import or xsd:include
+type Resolver interface {
+	Resolve(location string) ([]byte, error)
+}
+
+// bundleResolver - resolves the locations from a set of files, by path or by file name
+type bundleResolver struct {
+	files map[string][]byte
+}
+
+// NewBundleResolver - creates a resolver finding the imported documents in the files, keyed by their path
+func NewBundleResolver(files map[string][]byte) Resolver {
+	return &bundleResolver{files: files}
+}
+
+func (r *bundleResolver) Resolve(location string) ([]byte, error) {
+	if content, ok := r.files[location]; ok {
+		return content, nil
+	}
+	if content, ok := r.files[path.Clean(location)]; ok {
+		return content, nil
+	}
+	// imports by url, or from another directory, are found by their file name
+	name := path.Base(location)
+	for filePath, content := range r.files {
+		if path.Base(filePath) == name {
+			return content, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find %s in the bundle", location)
+}
+
+// localResolver - resolves the locations from the files of a directory
+type localResolver struct {
+	dir string
+}
+
+// NewLocalResolver - creates a resolver reading the imported documents from the directory, the locations outside of
+// the directory are not resolved
+func NewLocalResolver(dir string) Resolver {
+	return &localResolver{dir: dir}
+}
+
+func (r *localResolver) Resolve(location string) ([]byte, error) {
+	if strings.Contains(location, "://") {
+		return nil, fmt.Errorf("could not resolve %s, only local files are resolved", location)
+	}
+	file := filepath.Clean(filepath.FromSlash(location))
+	if filepath.IsAbs(file) || filepath.VolumeName(file) != "" || file == ".." || strings.HasPrefix(file, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("could not resolve %s, only the files of the directory are resolved", location)
+	}
+	return ioutil.ReadFile(filepath.Join(r.dir, file))
+}
+
+// UnmarshalWithResolver unmarshals the WSDL document and resolves its imports, and the imports and includes of its
+// schemas, with the resolver.  The messages, port types, bindings, services and schemas of the imported documents
+// are added to the returned Definitions.  The location is the path of the document, the relative locations of
+// its imports are resolved from it.
+func UnmarshalWithResolver(data []byte, location string, resolver Resolver) (*Definitions, error) {
+	def, err := Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if resolver == nil {
+		return def, nil
+	}
+
+	importer := &importer{resolver: resolver, visited: map[string]bool{path.Clean(location): true}}
+	err = importer.importDefinitions(def, def, location)
+	if err != nil {
+		return nil, err
+	}
+	def.setFirsts()
+	return def, nil
+}
+
+type importer struct {
+	resolver Resolver
+	visited  map[string]bool
+}
+
+// resolveLocation - returns the location relative to the base location, unless it is absolute
+func resolveLocation(base, location string) string {
+	if strings.Contains(location, "://") || strings.HasPrefix(location, "/") {
+		return location
+	}
+	return path.Join(path.Dir(base), location)
+}
+
+func (i *importer) resolve(base, location string) ([]byte, string, bool, error) {
+	resolved := resolveLocation(base, location)
+	if i.visited[resolved] {
+		return nil, resolved, false, nil
+	}
+	i.visited[resolved] = true
+
+	data, err := i.resolver.Resolve(resolved)
+	if err != nil {
+		return nil, resolved, false, err
+	}
+	return data, resolved, true, nil
+}
+
+// importDefinitions - adds the imported documents of def to root
+func (i *importer) importDefinitions(root, def *Definitions, location string) error {
+	for _, schema := range def.Schemas {
+		if err := i.importSchemas(root, schema, location); err != nil {
+			return err
+		}
+	}
+
+	for _, imp := range def.Imports {
+		if imp.Location == "" {
+			continue
+		}
+		data, resolved, found, err := i.resolve(location, imp.Location)
+		if err != nil {
+			return fmt.Errorf("could not import %s: %s", imp.Location, err)
+		}
+		if !found {
+			continue
+		}
+
+		// a wsdl:import can reference a schema document
+		if element, _ := rootElement(data); element.Name.Local == "schema" {
+			if err := i.addSchema(root, data, resolved); err != nil {
+				return err
+			}
+			continue
+		}
+
+		imported, err := Unmarshal(data)
+		if err != nil {
+			return fmt.Errorf("could not import %s: %s", imp.Location, err)
+		}
+		root.Messages = append(root.Messages, imported.Messages...)
+		root.PortType = append(root.PortType, imported.PortType...)
+		root.Bindings = append(root.Bindings, imported.Bindings...)
+		root.Services = append(root.Services, imported.Services...)
+		root.Schemas = append(root.Schemas, imported.Schemas...)
+		for prefix, ns := range imported.Namespaces {
+			if _, ok := root.Namespaces[prefix]; !ok {
+				if root.Namespaces == nil {
+					root.Namespaces = make(map[string]string)
+				}
+				root.Namespaces[prefix] = ns
+			}
+		}
+		if err := i.importDefinitions(root, imported, resolved); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importSchemas - adds the schemas imported, or included, by the schema to root
+func (i *importer) importSchemas(root *Definitions, schema *Schema, location string) error {
+	locations := make([]string, 0)
+	for _, imp := range schema.Imports {
+		locations = append(locations, imp.Location)
+	}
+	for _, inc := range schema.Includes {
+		locations = append(locations, inc.Location)
+	}
+
+	for _, schemaLocation := range locations {
+		if schemaLocation == "" {
+			continue
+		}
+		data, resolved, found, err := i.resolve(location, schemaLocation)
+		if err != nil {
+			return fmt.Errorf("could not import schema %s: %s", schemaLocation, err)
+		}
+		if found {
+			if err := i.addSchema(root, data, resolved); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (i *importer) addSchema(root *Definitions, data []byte, location string) error {
+	schema := &Schema{}
+	if err := xml.Unmarshal(data, schema); err != nil {
+		return fmt.Errorf("could not import schema %s: %s", location, err)
+	}
+	root.Schemas = append(root.Schemas, schema)
+	return i.importSchemas(root, schema, location)
+}
@@ -1,23 +1,46 @@
 package wsdl
 
-// TODO: Add all types from the spec.
-
-import "encoding/xml"
-
-// Definitions is the root element of a WSDL document.
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// WSDL and SOAP versions
+const (
+	WSDL11 = "1.1"
+	WSDL20 = "2.0"
+	SOAP11 = "1.1"
+	SOAP12 = "1.2"
+)
+
+// Namespaces of the SOAP extensions
+const (
+	SOAP11Namespace   = "http://schemas.xmlsoap.org/wsdl/soap/"
+	SOAP12Namespace   = "http://schemas.xmlsoap.org/wsdl/soap12/"
+	WSDL20Namespace   = "http://www.w3.org/ns/wsdl"
+	WSDL20SOAPBinding = "http://www.w3.org/ns/wsdl/soap"
+)
+
+// Definitions is the root element of a WSDL document, WSDL 2.0 descriptions are converted to it.
 type Definitions struct {
 	XMLName         xml.Name          `xml:"definitions"`
 	Name            string            `xml:"name,attr"`
 	TargetNamespace string            `xml:"targetNamespace,attr"`
+	Version         string            `xml:"-"` // the WSDL version, 1.1 or 2.0
 	Namespaces      map[string]string `xml:"-"`
 	SOAPEnv         string            `xml:"SOAP-ENV,attr"`
 	SOAPEnc         string            `xml:"SOAP-ENC,attr"`
-	Service         Service           `xml:"service"`
+	Services        []*Service        `xml:"service"`
 	Imports         []*Import         `xml:"import"`
-	Schema          Schema            `xml:"types>schema"`
+	Schemas         []*Schema         `xml:"types>schema"`
 	Messages        []*Message        `xml:"message"`
 	PortType        []*PortType       `xml:"portType"`
-	Binding         Binding           `xml:"binding"`
+	Bindings        []*Binding        `xml:"binding"`
+
+	// Service, Schema and Binding are the first of their kind, for the documents with a single one
+	Service Service `xml:"-"`
+	Schema  Schema  `xml:"-"`
+	Binding Binding `xml:"-"`
 }
 
 type definitionDup Definitions
@@ -32,11 +55,32 @@ func (def *Definitions) UnmarshalXML(d *xml.Decoder, start xml.StartElement) err
 			def.Namespaces[attr.Name.Local] = attr.Value
 		}
 	}
-	return d.DecodeElement((*definitionDup)(def), &start)
+	err := d.DecodeElement((*definitionDup)(def), &start)
+	if err != nil {
+		return err
+	}
+	def.Version = WSDL11
+	def.setFirsts()
+	return nil
+}
+
+// setFirsts - sets the fields holding the first service, schema and binding
+func (def *Definitions) setFirsts() {
+	def.Service, def.Schema, def.Binding = Service{}, Schema{}, Binding{}
+	if len(def.Services) > 0 {
+		def.Service = *def.Services[0]
+	}
+	if len(def.Schemas) > 0 {
+		def.Schema = *def.Schemas[0]
+	}
+	if len(def.Bindings) > 0 {
+		def.Binding = *def.Bindings[0]
+	}
 }
 
 // Service defines a WSDL service and with a location, like an HTTP server.
 type Service struct {
+	Name  string  `xml:"name,attr"`
 	Doc   string  `xml:"documentation"`
 	Ports []*Port `xml:"port"`
 }
@@ -55,6 +99,22 @@ type Address struct {
 	Location string   `xml:"location,attr"`
 }
 
+// SOAPVersion - returns the SOAP version of a soap or soap12 address, empty for other addresses
+func (a Address) SOAPVersion() string {
+	return soapVersion(a.XMLName.Space)
+}
+
+// soapVersion - returns the SOAP version of the namespace, or of its prefix when it was not declared
+func soapVersion(space string) string {
+	switch strings.ToLower(space) {
+	case SOAP11Namespace, "soap":
+		return SOAP11
+	case SOAP12Namespace, "soap12":
+		return SOAP12
+	}
+	return ""
+}
+
 // Schema of WSDL document.
 type Schema struct {
 	XMLName         xml.Name          `xml:"schema"`
@@ -262,6 +322,7 @@ type Operation struct {
 type IO struct {
 	XMLName xml.Name
 	Message string `xml:"message,attr"`
+	Element string `xml:"element,attr"` // the element of the WSDL 2.0 operations
 }
 
 // Binding describes SOAP to WSDL binding.
@@ -273,10 +334,23 @@ type Binding struct {
 	Operations  []*BindingOperation `xml:"operation"`
 }
 
+// SOAPVersion - returns the SOAP version of the binding, empty when it is not a SOAP binding
+func (b *Binding) SOAPVersion() string {
+	if b.BindingType == nil {
+		return ""
+	}
+	if b.BindingType.Version != "" {
+		return b.BindingType.Version
+	}
+	return soapVersion(b.BindingType.XMLName.Space)
+}
+
 // BindingType contains additional meta data on how to implement the binding.
 type BindingType struct {
+	XMLName   xml.Name
 	Style     string `xml:"style,attr"`
 	Transport string `xml:"transport,attr"`
+	Version   string `xml:"-"` // the SOAP version of the WSDL 2.0 bindings
 }
 
 // BindingOperation describes the requirement for binding SOAP to WSDL
@@ -0,0 +1,131 @@
+package wsdl
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// description is the root element of a WSDL 2.0 document, it is converted to Definitions
+type description struct {
+	XMLName         xml.Name       `xml:"description"`
+	TargetNamespace string         `xml:"targetNamespace,attr"`
+	Imports         []*Import      `xml:"import"`
+	Includes        []*Import      `xml:"include"`
+	Schemas         []*Schema      `xml:"types>schema"`
+	Interfaces      []*interface20 `xml:"interface"`
+	Bindings        []*binding20   `xml:"binding"`
+	Services        []*service20   `xml:"service"`
+}
+
+type interface20 struct {
+	Name       string         `xml:"name,attr"`
+	Operations []*operation20 `xml:"operation"`
+}
+
+type operation20 struct {
+	Name   string `xml:"name,attr"`
+	Doc    string `xml:"documentation"`
+	Input  *IO    `xml:"input"`
+	Output *IO    `xml:"output"`
+}
+
+type binding20 struct {
+	Name       string                `xml:"name,attr"`
+	Interface  string                `xml:"interface,attr"`
+	Type       string                `xml:"type,attr"`
+	Protocol   string                `xml:"protocol,attr"`
+	Version    string                `xml:"version,attr"`
+	Operations []*bindingOperation20 `xml:"operation"`
+}
+
+type bindingOperation20 struct {
+	Ref    string `xml:"ref,attr"`
+	Action string `xml:"action,attr"`
+}
+
+type service20 struct {
+	Name      string        `xml:"name,attr"`
+	Doc       string        `xml:"documentation"`
+	Interface string        `xml:"interface,attr"`
+	Endpoints []*endpoint20 `xml:"endpoint"`
+}
+
+type endpoint20 struct {
+	Name    string `xml:"name,attr"`
+	Binding string `xml:"binding,attr"`
+	Address string `xml:"address,attr"`
+}
+
+// toDefinitions - converts the WSDL 2.0 description, interfaces become port types and endpoints become ports
+func (desc *description) toDefinitions(namespaces map[string]string) *Definitions {
+	def := &Definitions{
+		XMLName:         xml.Name{Space: WSDL20Namespace, Local: "description"},
+		TargetNamespace: desc.TargetNamespace,
+		Version:         WSDL20,
+		Namespaces:      namespaces,
+		Imports:         append(desc.Imports, desc.Includes...),
+		Schemas:         desc.Schemas,
+	}
+
+	for _, iface := range desc.Interfaces {
+		portType := &PortType{Name: iface.Name}
+		for _, op := range iface.Operations {
+			portType.Operations = append(portType.Operations, &Operation{Name: op.Name, Doc: op.Doc, Input: op.Input, Output: op.Output})
+		}
+		def.PortType = append(def.PortType, portType)
+	}
+
+	bindingVersions := make(map[string]string)
+	for _, b := range desc.Bindings {
+		binding := &Binding{
+			Name:        b.Name,
+			Type:        b.Interface,
+			BindingType: &BindingType{Transport: b.Protocol},
+		}
+		if b.Type == WSDL20SOAPBinding {
+			// the SOAP version of WSDL 2.0 bindings defaults to 1.2
+			binding.BindingType.Version = SOAP12
+			if b.Version != "" {
+				binding.BindingType.Version = b.Version
+			}
+		}
+		bindingVersions[b.Name] = binding.BindingType.Version
+
+		for _, op := range b.Operations {
+			operation := &BindingOperation{Name: localName(op.Ref)}
+			if binding.BindingType.Version == SOAP11 {
+				operation.Operation11.Action = op.Action
+			} else {
+				operation.Operation.Action = op.Action
+			}
+			binding.Operations = append(binding.Operations, operation)
+		}
+		def.Bindings = append(def.Bindings, binding)
+	}
+
+	for _, s := range desc.Services {
+		service := &Service{Name: s.Name, Doc: s.Doc}
+		for _, endpoint := range s.Endpoints {
+			address := Address{Location: endpoint.Address}
+			switch bindingVersions[localName(endpoint.Binding)] {
+			case SOAP11:
+				address.XMLName = xml.Name{Space: SOAP11Namespace, Local: "address"}
+			case SOAP12:
+				address.XMLName = xml.Name{Space: SOAP12Namespace, Local: "address"}
+			}
+			service.Ports = append(service.Ports, &Port{Name: endpoint.Name, Binding: endpoint.Binding, Address: address})
+		}
+		def.Services = append(def.Services, service)
+	}
+
+	def.setFirsts()
+	return def
+}
+
+// localName - returns the name without its namespace prefix
+func localName(qname string) string {
+	if i := strings.LastIndex(qname, ":"); i >= 0 {
+		return qname[i+1:]
+	}
+	return qname
+}